@@ -1,12 +1,15 @@
 package actionlint
 
 import (
+	"errors"
 	"fmt"
 	"io"
-	"os"
+	"io/fs"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 
 	"go.yaml.in/yaml/v4"
 )
@@ -251,8 +254,12 @@ func (c *LocalActionsCache) FindMetadata(spec string) (*ActionMetadata, bool, er
 	}
 
 	dir := filepath.Join(c.proj.RootDir(), filepath.FromSlash(spec))
-	b, f, ok := c.readLocalActionMetadataFile(dir)
-	if !ok {
+	b, f, err := c.readLocalActionMetadataFile(dir)
+	if err != nil {
+		c.writeCache(spec, nil) // Remember action could not be read
+		return nil, false, err
+	}
+	if b == nil {
 		c.debug("No action metadata found in %s", dir)
 		// Remember action was not found
 		c.writeCache(spec, nil)
@@ -288,32 +295,56 @@ func (c *LocalActionsCache) FindMetadata(spec string) (*ActionMetadata, bool, er
 	return &meta, false, nil
 }
 
-func (c *LocalActionsCache) readLocalActionMetadataFile(dir string) ([]byte, string, bool) {
+// invalidate removes the cached metadata of the local action in the directory. The directory is a
+// slash-separated path relative to the project root. This method is used to reload the action
+// metadata when it is changed.
+func (c *LocalActionsCache) invalidate(dir string) {
+	c.mu.Lock()
+	for k := range c.cache {
+		if path.Clean(k) == dir {
+			c.debug("Invalidate cache for %s", k)
+			delete(c.cache, k)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// readLocalActionMetadataFile reads the action metadata file in the directory. When no metadata file
+// is found, it returns nil. Errors other than the missing file such as a failure of reading the file
+// from Git index are returned.
+func (c *LocalActionsCache) readLocalActionMetadataFile(dir string) ([]byte, string, error) {
 	for _, f := range []string{"action.yaml", "action.yml"} {
 		p := filepath.Join(dir, f)
-		if b, err := os.ReadFile(p); err == nil {
-			return b, f, true
+		b, err := c.proj.readFile(p)
+		if err == nil {
+			return b, f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+			return nil, "", fmt.Errorf("could not read action metadata in %q: %w", dir, err)
 		}
 	}
 
-	return nil, "", false
+	return nil, "", nil
 }
 
 // LocalActionsCacheFactory is a factory to create LocalActionsCache instances. LocalActionsCache
 // should be created for each repositories. LocalActionsCacheFactory creates new LocalActionsCache
 // instance per repository (project).
 type LocalActionsCacheFactory struct {
+	mu     sync.Mutex
 	caches map[string]*LocalActionsCache
 	dbg    io.Writer
 }
 
 // GetCache returns LocalActionsCache instance for the given project. One LocalActionsCache is
 // created per one repository. Created instances are cached and will be used when caches are
-// requested for the same projects. This method is not thread safe.
+// requested for the same projects. Calling this method is thread-safe.
 func (f *LocalActionsCacheFactory) GetCache(p *Project) *LocalActionsCache {
 	if p == nil {
 		return newNullLocalActionsCache(f.dbg)
 	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	r := p.RootDir()
 	if c, ok := f.caches[r]; ok {
 		return c
@@ -325,5 +356,5 @@ func (f *LocalActionsCacheFactory) GetCache(p *Project) *LocalActionsCache {
 
 // NewLocalActionsCacheFactory creates a new LocalActionsCacheFactory instance.
 func NewLocalActionsCacheFactory(dbg io.Writer) *LocalActionsCacheFactory {
-	return &LocalActionsCacheFactory{map[string]*LocalActionsCache{}, dbg}
+	return &LocalActionsCacheFactory{caches: map[string]*LocalActionsCache{}, dbg: dbg}
 }
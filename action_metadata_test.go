@@ -82,7 +82,7 @@ func testCheckCachedFlag(t *testing.T, want, have bool) {
 
 func TestLocalActionsFindMetadataOK(t *testing.T) {
 	testdir := filepath.Join("testdata", "action_metadata")
-	proj := &Project{testdir, nil}
+	proj := &Project{testdir, nil, false, ""}
 	c := NewLocalActionsCache(proj, nil)
 
 	want := testGetWantedActionMetadata()
@@ -192,7 +192,7 @@ func TestLocalActionsFindMetadataOK(t *testing.T) {
 
 func TestLocalActionsFindConcurrently(t *testing.T) {
 	n := 10
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{filepath.Join("testdata", "action_metadata"), nil, false, ""}
 	c := NewLocalActionsCache(proj, nil)
 	ret := make(chan *ActionMetadata)
 	err := make(chan error)
@@ -245,12 +245,12 @@ func TestLocalActionsParsingSkipped(t *testing.T) {
 		},
 		{
 			what: "not a local action",
-			proj: &Project{"", nil},
+			proj: &Project{"", nil, false, ""},
 			spec: "actions/checkout@v4",
 		},
 		{
 			what: "action does not exist (#25, #40)",
-			proj: &Project{filepath.Join("testdata", "action_metadata"), nil},
+			proj: &Project{filepath.Join("testdata", "action_metadata"), nil, false, ""},
 			spec: "./this-action-does-not-exist",
 		},
 	}
@@ -271,7 +271,7 @@ func TestLocalActionsParsingSkipped(t *testing.T) {
 }
 
 func TestLocalActionsIgnoreRemoteActions(t *testing.T) {
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{filepath.Join("testdata", "action_metadata"), nil, false, ""}
 	c := NewLocalActionsCache(proj, nil)
 	for _, spec := range []string{"actions/checkout@v2", "docker://example.com/foo/bar"} {
 		m, cached, err := c.FindMetadata(spec)
@@ -288,7 +288,7 @@ func TestLocalActionsIgnoreRemoteActions(t *testing.T) {
 func TestLocalActionsLogCacheHit(t *testing.T) {
 	dbg := &bytes.Buffer{}
 	testdir := filepath.Join("testdata", "action_metadata")
-	proj := &Project{testdir, nil}
+	proj := &Project{testdir, nil, false, ""}
 	c := NewLocalActionsCache(proj, dbg)
 
 	want := testGetWantedActionMetadata()
@@ -338,7 +338,7 @@ func TestLocalActionsBrokenMetadata(t *testing.T) {
 		},
 	}
 
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{filepath.Join("testdata", "action_metadata"), nil, false, ""}
 	c := NewLocalActionsCache(proj, nil)
 
 	for _, tc := range tests {
@@ -367,7 +367,7 @@ func TestLocalActionsBrokenMetadata(t *testing.T) {
 }
 
 func TestLocalActionsDuplicateInputsOutputs(t *testing.T) {
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{filepath.Join("testdata", "action_metadata"), nil, false, ""}
 	c := NewLocalActionsCache(proj, nil)
 
 	for _, tc := range []struct {
@@ -399,7 +399,7 @@ func TestLocalActionsDuplicateInputsOutputs(t *testing.T) {
 
 func TestLocalActionsConcurrentFailures(t *testing.T) {
 	n := 10
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{filepath.Join("testdata", "action_metadata"), nil, false, ""}
 	c := NewLocalActionsCache(proj, nil)
 	errC := make(chan error)
 
@@ -433,7 +433,7 @@ func TestLocalActionsConcurrentFailures(t *testing.T) {
 }
 
 func TestLocalActionsConcurrentMultipleMetadataAndFailures(t *testing.T) {
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{filepath.Join("testdata", "action_metadata"), nil, false, ""}
 	c := NewLocalActionsCache(proj, nil)
 
 	inputs := []string{
@@ -691,10 +691,10 @@ inputs:
 
 func TestLocalActionsCacheFactory(t *testing.T) {
 	f := NewLocalActionsCacheFactory(io.Discard)
-	p1 := &Project{"path/to/project1", nil}
+	p1 := &Project{"path/to/project1", nil, false, ""}
 	c1 := f.GetCache(p1)
 
-	p2 := &Project{"path/to/project2", nil}
+	p2 := &Project{"path/to/project2", nil, false, ""}
 	c2 := f.GetCache(p2)
 	if c1 == c2 {
 		t.Errorf("different cache was not created: %v", c1)
@@ -38,8 +38,16 @@ type String struct {
 	Value string
 	// Quoted represents the string is quoted with ' or " in the YAML source.
 	Quoted bool
+	// Literal represents the string is a literal block scalar with "|" in the YAML source. Lines of
+	// the string are put in the source as-is following the header line with the same indentation.
+	Literal bool
 	// Pos is a position of the string in source.
 	Pos *Pos
+	// EndPos is a position of the last character of the string in source. When the string is
+	// quoted, it points to the closing quote. When the string is a block scalar like "|" or ">",
+	// it points to the last character of the block. This value is nil when the end position is
+	// unknown.
+	EndPos *Pos
 }
 
 // ContainsExpression checks if the given string contains a ${{ }} placeholder or not. This function
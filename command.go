@@ -4,9 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
 	"regexp"
 	"runtime"
 	"runtime/debug"
+	"strings"
+	"time"
 )
 
 // These variables might be modified by ldflags on building release binaries by GoReleaser. Do not modify manually
@@ -26,12 +32,21 @@ const (
 	ExitStatusFailure = 3
 )
 
-func printUsageHeader(out io.Writer) {
+// watchInterval is an interval of polling files with -watch flag.
+const watchInterval = 500 * time.Millisecond
+
+// getDocsRef returns the Git ref of the documents for the current version. It is the release tag
+// for released versions and "main" otherwise.
+func getDocsRef() string {
 	v := getCommandVersion()
-	b := "main"
 	if regexp.MustCompile(`^\d+\.\d+\.\d+$`).MatchString(v) {
-		b = "v" + v
+		return "v" + v
 	}
+	return "main"
+}
+
+func printUsageHeader(out io.Writer) {
+	b := getDocsRef()
 
 	fmt.Fprintf(out, `Usage: actionlint [FLAGS] [FILES...] [-]
 
@@ -56,6 +71,14 @@ func printUsageHeader(out io.Writer) {
 
     $ actionlint -format '{{json .}}'
 
+  Some formats are built in. For example, -format junit outputs results in
+  JUnit XML format. Other built-in formats are checkstyle, github, gitlab,
+  rdjson and rdjsonl.
+
+    $ actionlint -format junit
+
+  On GitHub Actions, -format github outputs errors as error annotations.
+
 Documents:
 
   - List of checks: https://github.com/rhysd/actionlint/tree/%s/docs/checks.md
@@ -89,16 +112,102 @@ type Command struct {
 	Stderr io.Writer
 }
 
-func (cmd *Command) runLinter(args []string, opts *LinterOptions, initConfig bool) ([]*Error, error) {
+func (cmd *Command) serve(addr string, opts *LinterOptions) error {
+	s, err := NewServer(opts)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+	go func() {
+		<-sig
+		srv.Close()
+	}()
+
+	fmt.Fprintf(cmd.Stderr, "Serving JSON-RPC API at http://%s\n", ln.Addr())
+	if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// runOptions is the options of the command to decide how to run the linter.
+type runOptions struct {
+	// initConfig generates the default config file instead of checking workflows.
+	initConfig bool
+	// diffBase is the Git revision to compare with to check only changed workflows.
+	diffBase string
+	// diffAllLines reports errors on all lines of the workflows checked with diffBase.
+	diffAllLines bool
+	// staged checks the workflows staged in Git index.
+	staged bool
+	// watch keeps checking the workflows when they are changed.
+	watch bool
+	// profileDir is the directory to write profiles. Profiling is disabled when it is empty.
+	profileDir string
+}
+
+func (cmd *Command) runLinter(args []string, opts *LinterOptions, run *runOptions) ([]*Error, error) {
+	if run.profileDir != "" {
+		opts.Profile = true
+	}
 	l, err := NewLinter(cmd.Stdout, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if initConfig {
+	if run.profileDir == "" {
+		return cmd.lint(l, args, run)
+	}
+
+	w, err := startProfile(run.profileDir)
+	if err != nil {
+		return nil, err
+	}
+	errs, err := cmd.lint(l, args, run)
+	p := l.Profile()
+	if perr := w.finish(p); perr != nil {
+		return nil, perr
+	}
+	if err == nil {
+		p.PrintTable(cmd.Stderr)
+		fmt.Fprintf(cmd.Stderr, "\nProfile was written to %s\n", run.profileDir)
+	}
+	return errs, err
+}
+
+func (cmd *Command) lint(l *Linter, args []string, run *runOptions) ([]*Error, error) {
+	if run.initConfig {
 		return nil, l.GenerateDefaultConfig("")
 	}
 
+	if run.watch {
+		stop := make(chan struct{})
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		defer signal.Stop(sig)
+		go func() {
+			<-sig
+			close(stop)
+		}()
+		return nil, l.Watch("", watchInterval, stop)
+	}
+
+	if run.diffBase != "" {
+		return l.LintChanges("", run.diffBase, run.diffAllLines)
+	}
+
+	if run.staged {
+		return l.LintStaged(args)
+	}
+
 	if len(args) == 0 {
 		return l.LintRepository("")
 	}
@@ -110,6 +219,82 @@ func (cmd *Command) runLinter(args []string, opts *LinterOptions, initConfig boo
 	return l.LintFiles(args, nil)
 }
 
+func printRuleList(out io.Writer, opts *LinterOptions) {
+	rules := BuiltinRuleMetadata()
+	w := len("NAME")
+	for _, r := range rules {
+		w = max(w, len(r.Name))
+	}
+
+	// Create the rules as Linter does to know which rules are disabled by missing commands
+	ctx := &RuleContext{
+		proc:       newConcurrentProcess(1),
+		shellcheck: opts.Shellcheck,
+		pyflakes:   opts.Pyflakes,
+	}
+	created, _ := BuiltinRuleRegistry().Create(ctx)
+	enabled := make(map[string]struct{}, len(created))
+	for _, r := range created {
+		enabled[r.Name()] = struct{}{}
+	}
+
+	fmt.Fprintf(out, "%-*s  %-8s  %-10s  %s\n", w, "NAME", "DEFAULT", "COMMAND", "DESCRIPTION")
+	for _, r := range rules {
+		c := r.Command
+		if c == "" {
+			c = "-"
+		}
+		d := "disabled"
+		if _, ok := enabled[r.Name]; ok {
+			d = "enabled"
+		}
+		fmt.Fprintf(out, "%-*s  %-8s  %-10s  %s\n", w, r.Name, d, c, r.Description)
+	}
+	fmt.Fprintln(out, "\nRules which depend on external commands are disabled when the commands are not found. Plugin rules\nin the config file are not listed since they are enabled only with -enable-plugins.")
+}
+
+func explainRule(out io.Writer, name string) error {
+	var rule *RuleMetadata
+	for _, r := range BuiltinRuleMetadata() {
+		if r.Name == name {
+			rule = r
+			break
+		}
+	}
+	if rule == nil {
+		return fmt.Errorf("rule %q does not exist. see -list-rules for the list of all rules", name)
+	}
+
+	fmt.Fprintf(out, "%s: %s\n", rule.Name, rule.Description)
+	if rule.Command != "" {
+		fmt.Fprintf(out, "\nThis rule depends on %q command. It is disabled when the command is not found.\n", rule.Command)
+	}
+	if rule.Explanation != "" {
+		fmt.Fprintf(out, "\n%s\n", strings.TrimRight(rule.Explanation, "\n"))
+	}
+	for _, e := range []struct {
+		title string
+		src   string
+	}{
+		{"Bad example", rule.BadExample},
+		{"Good example", rule.GoodExample},
+	} {
+		if e.src == "" {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s:\n\n", e.title)
+		for _, l := range strings.Split(strings.TrimRight(e.src, "\n"), "\n") {
+			if l == "" {
+				fmt.Fprintln(out)
+			} else {
+				fmt.Fprintf(out, "  %s\n", l)
+			}
+		}
+	}
+	fmt.Fprintf(out, "\nSee %s for more details.\n", ErrorCodeURL(rule.Name))
+	return nil
+}
+
 type ignorePatternFlags []string
 
 func (i *ignorePatternFlags) String() string {
@@ -127,24 +312,37 @@ func (cmd *Command) Main(args []string) int {
 	var ver bool
 	var opts LinterOptions
 	var ignorePats ignorePatternFlags
-	var initConfig bool
 	var noColor bool
 	var color bool
+	var listRules bool
+	var explain string
+	var serve string
+	var run runOptions
 
 	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
 	flags.SetOutput(cmd.Stderr)
-	flags.Var(&ignorePats, "ignore", "Regular expression matching to error messages you want to ignore. This flag is repeatable")
+	flags.Var(&ignorePats, "ignore", "Regular expression matching to error messages you want to ignore. With \"code:\" prefix, it matches to error codes or rule names instead. This flag is repeatable")
 	flags.StringVar(&opts.Shellcheck, "shellcheck", "shellcheck", "Command name or file path of \"shellcheck\" external command. If empty, shellcheck integration will be disabled")
 	flags.StringVar(&opts.Pyflakes, "pyflakes", "pyflakes", "Command name or file path of \"pyflakes\" external command. If empty, pyflakes integration will be disabled")
 	flags.BoolVar(&opts.Oneline, "oneline", false, "Use one line per one error. Useful for reading error messages from programs")
-	flags.StringVar(&opts.Format, "format", "", "Custom template to format error messages in Go template syntax. See the usage documentation for more details")
+	flags.StringVar(&opts.Format, "format", "", "Custom template to format error messages in Go template syntax or name of built-in format (\"checkstyle\", \"github\", \"gitlab\", \"junit\", \"rdjson\", \"rdjsonl\"). See the usage documentation for more details")
 	flags.StringVar(&opts.ConfigFile, "config-file", "", "File path to config file")
-	flags.BoolVar(&initConfig, "init-config", false, "Generate default config file at .github/actionlint.yaml in current project")
+	flags.BoolVar(&run.initConfig, "init-config", false, "Generate default config file at .github/actionlint.yaml in current project")
 	flags.BoolVar(&noColor, "no-color", false, "Disable colorful output")
 	flags.BoolVar(&color, "color", false, "Always enable colorful output. This is useful to force colorful outputs")
 	flags.BoolVar(&opts.Verbose, "verbose", false, "Enable verbose output")
 	flags.BoolVar(&opts.Debug, "debug", false, "Enable debug output (for development)")
 	flags.BoolVar(&ver, "version", false, "Show version and how this binary was installed")
+	flags.BoolVar(&listRules, "list-rules", false, "Show all built-in rules with their descriptions")
+	flags.StringVar(&explain, "explain", "", "Show the detailed explanation of the rule with examples. The rule is specified by its name such as \"expression\"")
+	flags.StringVar(&run.diffBase, "diff-base", "", "Git revision to compare with. Only workflow files changed since the revision and workflows calling changed local actions or reusable workflows are checked. Errors are reported only on changed lines")
+	flags.BoolVar(&run.diffAllLines, "diff-all-lines", false, "Report errors on all lines of the files checked with -diff-base")
+	flags.BoolVar(&run.staged, "staged", false, "Check workflow files staged in Git index instead of working tree. This is useful for pre-commit hooks")
+	flags.StringVar(&opts.CacheDir, "cache-dir", "", "Directory to cache lint results. Files whose content, config, and local dependencies are unchanged since the previous run are not checked again")
+	flags.BoolVar(&run.watch, "watch", false, "Keep checking workflow files in the repository again when they, local actions, local reusable workflows, or the config file are changed. Press Ctrl+C to stop")
+	flags.StringVar(&serve, "serve", "", "Serve JSON-RPC API over HTTP at the loopback address such as \"127.0.0.1:8123\" to check workflows by a long-running process. See the usage documentation for the API")
+	flags.BoolVar(&opts.EnablePlugins, "enable-plugins", false, "Run external rule plugins listed in \"plugins\" of the config file. Enable this only for trusted repositories since plugins can run arbitrary commands")
+	flags.StringVar(&run.profileDir, "profile", "", "Directory to write CPU and heap profiles in pprof format and the time spent by each rule, on each file, and by each external command. The timing table is also printed to stderr")
 	flags.StringVar(&opts.StdinFileName, "stdin-filename", "<stdin>", "File name when reading input from stdin")
 	flags.Usage = func() {
 		printUsageHeader(cmd.Stderr)
@@ -171,6 +369,52 @@ func (cmd *Command) Main(args []string) int {
 		return ExitStatusSuccessNoProblem
 	}
 
+	if listRules {
+		printRuleList(cmd.Stdout, &opts)
+		return ExitStatusSuccessNoProblem
+	}
+
+	if explain != "" {
+		if err := explainRule(cmd.Stdout, explain); err != nil {
+			fmt.Fprintln(cmd.Stderr, err.Error())
+			return ExitStatusInvalidCommandOption
+		}
+		return ExitStatusSuccessNoProblem
+	}
+
+	if run.diffBase != "" && len(flags.Args()) > 0 {
+		fmt.Fprintln(cmd.Stderr, "-diff-base cannot be used with file arguments")
+		return ExitStatusInvalidCommandOption
+	}
+	if run.staged && run.diffBase != "" {
+		fmt.Fprintln(cmd.Stderr, "-staged cannot be used with -diff-base")
+		return ExitStatusInvalidCommandOption
+	}
+	if run.watch && (len(flags.Args()) > 0 || run.diffBase != "" || run.staged || run.initConfig) {
+		fmt.Fprintln(cmd.Stderr, "-watch cannot be used with file arguments, -diff-base, -staged, or -init-config")
+		return ExitStatusInvalidCommandOption
+	}
+	if serve != "" && (len(flags.Args()) > 0 || run.diffBase != "" || run.staged || run.initConfig || run.watch) {
+		fmt.Fprintln(cmd.Stderr, "-serve cannot be used with file arguments, -diff-base, -staged, -init-config, or -watch")
+		return ExitStatusInvalidCommandOption
+	}
+	if serve != "" && run.profileDir != "" {
+		fmt.Fprintln(cmd.Stderr, "-profile cannot be used with -serve")
+		return ExitStatusInvalidCommandOption
+	}
+	if serve != "" {
+		a, err := localServerAddr(serve)
+		if err != nil {
+			fmt.Fprintln(cmd.Stderr, err.Error())
+			return ExitStatusInvalidCommandOption
+		}
+		serve = a
+	}
+	if run.diffAllLines && run.diffBase == "" {
+		fmt.Fprintln(cmd.Stderr, "-diff-all-lines requires -diff-base")
+		return ExitStatusInvalidCommandOption
+	}
+
 	opts.IgnorePatterns = ignorePats
 	opts.LogWriter = cmd.Stderr
 
@@ -181,7 +425,15 @@ func (cmd *Command) Main(args []string) int {
 		opts.Color = ColorOptionKindNever
 	}
 
-	errs, err := cmd.runLinter(flags.Args(), &opts, initConfig)
+	if serve != "" {
+		if err := cmd.serve(serve, &opts); err != nil {
+			fmt.Fprintln(cmd.Stderr, err.Error())
+			return ExitStatusFailure
+		}
+		return ExitStatusSuccessNoProblem
+	}
+
+	errs, err := cmd.runLinter(flags.Args(), &opts, &run)
 	if err != nil {
 		fmt.Fprintln(cmd.Stderr, err.Error())
 		return ExitStatusFailure
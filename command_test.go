@@ -42,3 +42,207 @@ func TestCommandMain(t *testing.T) {
 		t.Errorf("runner-label rule should be ignored by -ignore but it is included in output: %q", out)
 	}
 }
+
+func TestCommandGitHubFormatOnGitHubActions(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+
+	workflow := filepath.Join("testdata", "format", "test.yaml")
+	testCases := []struct {
+		what string
+		args []string
+		want bool
+	}{
+		{"default", []string{}, false},
+		{"explicit", []string{"-format", "github"}, true},
+		{"oneline", []string{"-oneline"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			// Stdout is redirected to a file as on GitHub Actions
+			stdout, err := os.Create(filepath.Join(t.TempDir(), "stdout.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stdout.Close()
+
+			var stderr bytes.Buffer
+			cmd := Command{
+				Stdin:  os.Stdin,
+				Stdout: stdout,
+				Stderr: &stderr,
+			}
+			args := append([]string{"actionlint", "-shellcheck=", "-pyflakes=", "-no-color"}, tc.args...)
+			if status := cmd.Main(append(args, workflow)); status != 1 {
+				t.Fatalf("exit status should be 1 but got %d: %s", status, stderr.String())
+			}
+
+			b, err := os.ReadFile(stdout.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := string(b)
+			have := strings.HasPrefix(out, "::error file=")
+			if have != tc.want {
+				t.Fatalf("wanted github format enabled=%v but output was %q", tc.want, out)
+			}
+		})
+	}
+}
+
+func TestCommandListRules(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  os.Stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	if status := cmd.Main([]string{"actionlint", "-list-rules"}); status != 0 {
+		t.Fatalf("exit status should be 0 but got %d: %s", status, stderr.String())
+	}
+
+	out := stdout.String()
+	for _, m := range BuiltinRuleMetadata() {
+		if !strings.Contains(out, m.Name) || !strings.Contains(out, m.Description) {
+			t.Errorf("rule %q is not listed: %q", m.Name, out)
+		}
+	}
+	if !strings.Contains(out, "shellcheck  Checks for") {
+		t.Errorf("command of shellcheck rule is not shown: %q", out)
+	}
+}
+
+func TestCommandListRulesDisabledByMissingCommands(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  os.Stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	args := []string{"actionlint", "-list-rules", "-shellcheck=", "-pyflakes=this-command-does-not-exist"}
+	if status := cmd.Main(args); status != 0 {
+		t.Fatalf("exit status should be 0 but got %d: %s", status, stderr.String())
+	}
+
+	states := map[string]string{}
+	for _, l := range strings.Split(stdout.String(), "\n") {
+		if fs := strings.Fields(l); len(fs) >= 2 {
+			states[fs[0]] = fs[1]
+		}
+	}
+	for name, want := range map[string]string{
+		"expression": "enabled",
+		"shellcheck": "disabled",
+		"pyflakes":   "disabled",
+	} {
+		if have := states[name]; have != want {
+			t.Errorf("rule %q should be %s but got %q: %q", name, want, have, stdout.String())
+		}
+	}
+}
+
+func TestCommandExplainRule(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  os.Stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	if status := cmd.Main([]string{"actionlint", "-explain", "if-cond"}); status != 0 {
+		t.Fatalf("exit status should be 0 but got %d: %s", status, stderr.String())
+	}
+
+	out := stdout.String()
+	for _, s := range []string{
+		"if-cond: Checks for if: conditions",
+		"Bad example:\n\n  on: push\n",
+		"Good example:\n\n  on: push\n",
+		"docs/checks.md#if-cond-constant",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output should contain %q: %q", s, out)
+		}
+	}
+}
+
+func TestCommandExplainUnknownRule(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  os.Stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	if status := cmd.Main([]string{"actionlint", "-explain", "this-rule-does-not-exist"}); status != ExitStatusInvalidCommandOption {
+		t.Fatalf("exit status should be %d but got %d", ExitStatusInvalidCommandOption, status)
+	}
+	if msg := stderr.String(); !strings.Contains(msg, `rule "this-rule-does-not-exist" does not exist`) {
+		t.Fatalf("unexpected error message: %q", msg)
+	}
+}
+
+func TestCommandDiffBaseInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		what string
+		args []string
+		want string
+	}{
+		{
+			what: "file arguments",
+			args: []string{"-diff-base", "main", "test.yaml"},
+			want: "-diff-base cannot be used with file arguments",
+		},
+		{
+			what: "staged with diff base",
+			args: []string{"-staged", "-diff-base", "main"},
+			want: "-staged cannot be used with -diff-base",
+		},
+		{
+			what: "all lines without base",
+			args: []string{"-diff-all-lines"},
+			want: "-diff-all-lines requires -diff-base",
+		},
+		{
+			what: "watch with file arguments",
+			args: []string{"-watch", "test.yaml"},
+			want: "-watch cannot be used with file arguments",
+		},
+		{
+			what: "watch with staged",
+			args: []string{"-watch", "-staged"},
+			want: "-watch cannot be used with",
+		},
+		{
+			what: "serve with file arguments",
+			args: []string{"-serve", "127.0.0.1:0", "test.yaml"},
+			want: "-serve cannot be used with file arguments",
+		},
+		{
+			what: "serve at non-loopback address",
+			args: []string{"-serve", "0.0.0.0:8123"},
+			want: "must be a loopback address",
+		},
+		{
+			what: "profile with serve",
+			args: []string{"-serve", "127.0.0.1:0", "-profile", "profile"},
+			want: "-profile cannot be used with -serve",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := Command{
+				Stdin:  os.Stdin,
+				Stdout: &stdout,
+				Stderr: &stderr,
+			}
+			if status := cmd.Main(append([]string{"actionlint"}, tc.args...)); status != ExitStatusInvalidCommandOption {
+				t.Fatalf("exit status should be %d but got %d", ExitStatusInvalidCommandOption, status)
+			}
+			if msg := stderr.String(); !strings.Contains(msg, tc.want) {
+				t.Fatalf("wanted %q in error message but got %q", tc.want, msg)
+			}
+		})
+	}
+}
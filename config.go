@@ -12,14 +12,49 @@ import (
 	"go.yaml.in/yaml/v4"
 )
 
-// IgnorePatterns is a list of regular expressions. These patterns are used for filtering errors by
-// matching the error messages.
-type IgnorePatterns []*regexp.Regexp
+// IgnorePattern is a pattern to ignore errors. It is matched to the error message, or to the error
+// code and the rule name when it was compiled from a string with "code:" prefix. Use
+// CompileIgnorePattern to create an instance.
+type IgnorePattern struct {
+	re *regexp.Regexp
+	// code is true when the pattern is matched to error codes and rule names instead of messages.
+	code bool
+}
+
+// CompileIgnorePattern compiles the pattern to ignore errors. When the pattern starts with "code:",
+// the rest of the pattern must match the whole error code like "expression/untrusted-input" or the
+// whole rule name like "shellcheck". Otherwise the pattern is matched to error messages.
+func CompileIgnorePattern(pat string) (*IgnorePattern, error) {
+	if c, ok := strings.CutPrefix(pat, "code:"); ok {
+		r, err := regexp.Compile("^(?:" + c + ")$")
+		if err != nil {
+			return nil, err
+		}
+		return &IgnorePattern{r, true}, nil
+	}
+	r, err := regexp.Compile(pat)
+	if err != nil {
+		return nil, err
+	}
+	return &IgnorePattern{r, false}, nil
+}
+
+// Match returns whether the given error should be ignored by the pattern.
+func (pat *IgnorePattern) Match(err *Error) bool {
+	if pat.code {
+		return pat.re.MatchString(err.Kind) || err.Code != "" && pat.re.MatchString(err.Code)
+	}
+	return pat.re.MatchString(err.Message)
+}
+
+// IgnorePatterns is a list of patterns. These patterns are used for filtering errors by matching
+// the error messages, or the error codes and the rule names. See IgnorePattern for more details.
+type IgnorePatterns []*IgnorePattern
 
 // Match returns whether the given error should be ignored due to the "ignore" configuration.
 func (pats IgnorePatterns) Match(err *Error) bool {
-	for _, r := range pats {
-		if r.MatchString(err.Message) {
+	for _, p := range pats {
+		if p.Match(err) {
 			return true
 		}
 	}
@@ -31,9 +66,9 @@ func (pats *IgnorePatterns) UnmarshalYAML(n *yaml.Node) error {
 	if n.Kind != yaml.SequenceNode {
 		return fmt.Errorf("yaml: \"ignore\" must be a sequence node at line:%d,col:%d", n.Line, n.Column)
 	}
-	rs := make([]*regexp.Regexp, 0, len(n.Content))
+	rs := make([]*IgnorePattern, 0, len(n.Content))
 	for _, p := range n.Content {
-		r, err := regexp.Compile(p.Value)
+		r, err := CompileIgnorePattern(p.Value)
 		if err != nil {
 			return fmt.Errorf("invalid regular expression %q in \"ignore\" at line%d,col:%d: %w", p.Value, n.Line, n.Column, err)
 		}
@@ -47,10 +82,26 @@ func (pats *IgnorePatterns) UnmarshalYAML(n *yaml.Node) error {
 // in the configuration file.
 type PathConfig struct {
 	// Ignore is a list of patterns. They are used for ignoring errors by matching to the error messages.
-	// It is similar to the "-ignore" command line option.
+	// Patterns with "code:" prefix are matched to the error codes or the rule names. It is similar to
+	// the "-ignore" command line option.
 	Ignore IgnorePatterns `yaml:"ignore"`
 }
 
+// PluginConfig is a configuration of an external rule plugin. This is for elements of the "plugins"
+// array in the configuration file.
+type PluginConfig struct {
+	// Name is the name of the rule implemented by the plugin. It is used as the rule name of the
+	// errors reported by the plugin.
+	Name string `yaml:"name"`
+	// Command is the command to run the plugin. It can be a command name, a file path, or a command
+	// line with arguments. A relative file path is resolved from the repository root.
+	Command string `yaml:"command"`
+}
+
+// pluginNamePattern is the pattern of valid plugin names. It is the same format as the names of
+// built-in rules.
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
 // Config is configuration of actionlint. This struct instance is parsed from "actionlint.yaml"
 // file usually put in ".github" directory.
 type Config struct {
@@ -64,9 +115,29 @@ type Config struct {
 	// listed here as undefined config variables.
 	// https://docs.github.com/en/actions/learn-github-actions/variables
 	ConfigVariables []string `yaml:"config-variables"`
+	// SecretsInherit is configuration for "secrets: inherit" at reusable workflow calls.
+	SecretsInherit struct {
+		// TrustedOwners is a list of owners (users or organizations) of external reusable workflows.
+		// The reusable workflows owned by them are allowed to inherit all secrets with "secrets: inherit".
+		// Owner names are case-insensitive.
+		TrustedOwners []string `yaml:"trusted-owners"`
+		// CheckLocal enables to report "secrets: inherit" at calls of local reusable workflows. Local
+		// reusable workflows are trusted by default.
+		CheckLocal bool `yaml:"check-local"`
+	} `yaml:"secrets-inherit"`
+	// UntrustedInputs is configuration to customize untrusted inputs detected in inline scripts.
+	UntrustedInputs struct {
+		// Untrusted is a list of paths to additional untrusted inputs like "github.event.client_payload.*".
+		Untrusted []string `yaml:"untrusted"`
+		// Safe is a list of paths to inputs which are untrusted by default but known to be safe.
+		Safe []string `yaml:"safe"`
+	} `yaml:"untrusted-inputs"`
 	// Paths is a "paths" mapping in the configuration file. The keys are glob patterns to match file paths.
 	// And the values are corresponding configurations applied to the file paths.
 	Paths map[string]PathConfig `yaml:"paths"`
+	// Plugins is a list of external rule plugins. Plugins are run only when they are enabled by
+	// LinterOptions.EnablePlugins.
+	Plugins []PluginConfig `yaml:"plugins"`
 }
 
 // PathConfigs returns a list of all PathConfig values matching to the given file path. The path must
@@ -86,6 +157,24 @@ func (cfg *Config) PathConfigs(path string) []PathConfig {
 	return ret
 }
 
+// UntrustedInputSearchRoots returns the search roots to detect untrusted inputs. Paths in the
+// "untrusted-inputs" configuration are merged into a copy of BuiltinUntrustedInputs. When nothing is
+// configured, BuiltinUntrustedInputs is returned as-is.
+func (cfg *Config) UntrustedInputSearchRoots() UntrustedInputSearchRoots {
+	if cfg == nil || len(cfg.UntrustedInputs.Untrusted) == 0 && len(cfg.UntrustedInputs.Safe) == 0 {
+		return BuiltinUntrustedInputs
+	}
+	roots := BuiltinUntrustedInputs.DeepCopy()
+	// Paths were validated in `ParseConfig()`
+	for _, p := range cfg.UntrustedInputs.Untrusted {
+		roots.AddPath(p)
+	}
+	for _, p := range cfg.UntrustedInputs.Safe {
+		roots.RemovePath(p)
+	}
+	return roots
+}
+
 // ParseConfig parses the given bytes as an actionlint config file. When deserializing the YAML file
 // or the config validation fails, this function returns an error.
 func ParseConfig(b []byte) (*Config, error) {
@@ -99,6 +188,26 @@ func ParseConfig(b []byte) (*Config, error) {
 			return nil, fmt.Errorf("invalid glob pattern %q in \"paths\"", pat)
 		}
 	}
+	for _, ps := range [][]string{c.UntrustedInputs.Untrusted, c.UntrustedInputs.Safe} {
+		for _, p := range ps {
+			if _, err := splitUntrustedInputPath(p); err != nil {
+				return nil, fmt.Errorf("%w in \"untrusted-inputs\"", err)
+			}
+		}
+	}
+	seen := map[string]struct{}{}
+	for _, p := range c.Plugins {
+		if !pluginNamePattern.MatchString(p.Name) {
+			return nil, fmt.Errorf("invalid plugin name %q in \"plugins\". name must consist of lower case letters, digits, and hyphens like \"my-rule\"", p.Name)
+		}
+		if _, ok := seen[p.Name]; ok {
+			return nil, fmt.Errorf("plugin %q is duplicated in \"plugins\"", p.Name)
+		}
+		seen[p.Name] = struct{}{}
+		if p.Command == "" {
+			return nil, fmt.Errorf("command of plugin %q must not be empty in \"plugins\"", p.Name)
+		}
+	}
 	return &c, nil
 }
 
@@ -143,6 +252,27 @@ func writeDefaultConfigFile(path string) error {
 # Empty array means no configuration variable is allowed.
 config-variables: null
 
+# Configuration for "secrets: inherit" at reusable workflow calls.
+#
+# "trusted-owners" is an array of owners (users or organizations) of external
+# reusable workflows. Only reusable workflows owned by them or by the owner of
+# the repository ("origin" remote) are allowed to inherit all secrets.
+# "check-local" enables to report "secrets: inherit" at local reusable workflow
+# calls with the secrets defined in the called workflows.
+secrets-inherit:
+  trusted-owners: []
+  check-local: false
+
+# Configuration for untrusted inputs detected in inline scripts.
+#
+# "untrusted" is an array of paths to additional untrusted inputs such as
+# "github.event.client_payload.*". '*' matches any property or array element.
+# "safe" is an array of paths to inputs which are untrusted by default but known
+# to be safe in your repository.
+untrusted-inputs:
+  untrusted: []
+  safe: []
+
 # Configuration for file paths. The keys are glob patterns to match to file
 # paths relative to the repository root. The values are the configurations for
 # the file paths. Note that the path separator is always '/'.
@@ -153,6 +283,13 @@ config-variables: null
 paths:
 #  .github/workflows/**/*.yml:
 #    ignore: []
+
+# External rule plugins. "name" is the rule name and "command" is the command to
+# run the plugin. Relative file paths are resolved from the repository root.
+# Plugins are run only when the -enable-plugins flag is given.
+plugins: []
+#  - name: my-rule
+#    command: ./scripts/actionlint-my-rule
 `)
 	if err := os.WriteFile(path, b, 0644); err != nil {
 		return fmt.Errorf("could not write default configuration file at %q: %w", path, err)
@@ -88,6 +88,45 @@ paths:
 `,
 			want: `invalid glob pattern`,
 		},
+		{
+			in: `
+untrusted-inputs:
+  untrusted: [github.event..foo]
+`,
+			want: `invalid path to untrusted input "github.event..foo". property name must not be empty in "untrusted-inputs"`,
+		},
+		{
+			in: `
+untrusted-inputs:
+  safe: ['*.foo']
+`,
+			want: `invalid path to untrusted input "*.foo". path must start with context name in "untrusted-inputs"`,
+		},
+		{
+			in: `
+plugins:
+  - name: My_Rule
+    command: ./rule
+`,
+			want: `invalid plugin name "My_Rule" in "plugins"`,
+		},
+		{
+			in: `
+plugins:
+  - name: my-rule
+    command: ./rule1
+  - name: my-rule
+    command: ./rule2
+`,
+			want: `plugin "my-rule" is duplicated in "plugins"`,
+		},
+		{
+			in: `
+plugins:
+  - name: my-rule
+`,
+			want: `command of plugin "my-rule" must not be empty in "plugins"`,
+		},
 	}
 
 	for _, tc := range tests {
@@ -150,6 +189,45 @@ func TestConfigPathConfigIgnores(t *testing.T) {
 	}
 }
 
+func TestConfigIgnorePatternsMatchErrorCode(t *testing.T) {
+	tests := []struct {
+		pat  string
+		kind string
+		code string
+		want bool
+	}{
+		{"code:expression/untrusted-input", "expression", "expression/untrusted-input", true},
+		{"code:expression/.+", "expression", "expression/untrusted-input", true},
+		{"code:shellcheck/SC(2086|2016)", "shellcheck", "shellcheck/SC2086", true},
+		{"code:shellcheck/SC(2086|2016)", "shellcheck", "shellcheck/SC1000", false},
+		{"code:expression", "expression", "expression/untrusted-input", true},
+		{"code:expression", "expression", "", true},
+		{"code:untrusted-input", "expression", "expression/untrusted-input", false},
+		{"code:expr", "expression", "expression/untrusted-input", false},
+		{"code:pyflakes", "expression", "", false},
+		{"code:a|ab", "ab", "", true},
+		{"code:this is test", "expression", "", false},
+		// Patterns without "code:" prefix are matched only to error messages
+		{"expression", "expression", "expression/untrusted-input", false},
+		{`\w+`, "expression", "expression/untrusted-input", true},
+		{"expression/untrusted-input", "expression", "expression/untrusted-input", false},
+		{`^code:(?:x)|this`, "expression", "expression/untrusted-input", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pat+"_"+tc.code, func(t *testing.T) {
+			var c PathConfig
+			if err := yaml.Unmarshal([]byte("ignore: ['"+tc.pat+"']"), &c); err != nil {
+				t.Fatal(err)
+			}
+			err := &Error{Message: "this is test", Kind: tc.kind, Code: tc.code}
+			if have := c.Ignore.Match(err); have != tc.want {
+				t.Fatalf("wanted %v but got %v for pattern %q and code %q", tc.want, have, tc.pat, tc.code)
+			}
+		})
+	}
+}
+
 func TestConfigIgnoreErrors(t *testing.T) {
 	src := `
 paths:
@@ -0,0 +1,283 @@
+package actionlint
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/execabs"
+)
+
+// lineSet is a set of line numbers in a file. nil means all lines in the file.
+type lineSet map[int]struct{}
+
+func (s lineSet) has(l int) bool {
+	if s == nil {
+		return true
+	}
+	_, ok := s[l]
+	return ok
+}
+
+func (s lineSet) addRange(start, end int) {
+	for l := start; l <= end; l++ {
+		s[l] = struct{}{}
+	}
+}
+
+// gitDiff is the result of `git diff` against a base revision. It maps slash-separated file paths
+// relative to the repository root to the line numbers added or modified in the files. Files which
+// were removed are not included. Untracked files are mapped to nil which means all lines.
+type gitDiff map[string]lineSet
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	return runGitWithInput(dir, nil, args...)
+}
+
+// runGitWithInput is the same as runGit but the input is passed to the stdin of the command.
+func runGitWithInput(dir string, input []byte, args ...string) ([]byte, error) {
+	cmd := execabs.Command("git", args...)
+	cmd.Dir = dir
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("`git %s` failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// readGitDiff runs `git diff` in the repository at the root directory and returns the lines changed
+// since the merge base of the base revision and HEAD. Changes in the working tree are also included.
+// Untracked files in the workflows directory are included as files whose all lines were changed.
+func readGitDiff(root, base string) (gitDiff, error) {
+	b, err := runGit(root, "merge-base", base, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("could not find the merge base of %q and HEAD: %w", base, err)
+	}
+	mb := strings.TrimSpace(string(b))
+
+	b, err = runGit(root, "diff", "--no-color", "--no-ext-diff", "--unified=0", "--find-renames", "--src-prefix=a/", "--dst-prefix=b/", mb, "--")
+	if err != nil {
+		return nil, err
+	}
+	d, err := parseGitDiff(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// `git diff` does not show untracked files
+	b, err = runGit(root, "ls-files", "-z", "--others", "--exclude-standard", "--", ".github/workflows")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range bytes.Split(b, []byte{0}) {
+		if len(f) > 0 {
+			d[string(f)] = nil // All lines were added
+		}
+	}
+
+	return d, nil
+}
+
+var gitDiffHunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// parseGitDiff parses the output of `git diff --unified=0`.
+func parseGitDiff(b []byte) (gitDiff, error) {
+	d := gitDiff{}
+	var cur lineSet
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(nil, 1024*1024)
+	for s.Scan() {
+		l := s.Text()
+		if p, ok := strings.CutPrefix(l, "+++ "); ok {
+			cur = nil
+			if p == "/dev/null" {
+				continue // The file was removed
+			}
+			if strings.HasPrefix(p, `"`) {
+				u, err := strconv.Unquote(p)
+				if err != nil {
+					return nil, fmt.Errorf("could not parse file path %s in git diff: %w", p, err)
+				}
+				p = u
+			}
+			p = strings.TrimPrefix(p, "b/")
+			cur = lineSet{}
+			d[p] = cur
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if m := gitDiffHunkHeader.FindStringSubmatch(l); m != nil {
+			start, _ := strconv.Atoi(m[1])
+			n := 1
+			if m[2] != "" {
+				n, _ = strconv.Atoi(m[2])
+			}
+			// When n is zero, lines were only removed. No line was added in the hunk
+			cur.addRange(start, start+n-1)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("could not read output of git diff: %w", err)
+	}
+	return d, nil
+}
+
+// affects returns true when the file or directory at the slash-separated path relative to the
+// repository root is changed.
+func (d gitDiff) affects(p string) bool {
+	if _, ok := d[p]; ok {
+		return true
+	}
+	for f := range d {
+		if strings.HasPrefix(f, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// localUsesPath returns the slash-separated path relative to the repository root of the local
+// action or the local reusable workflow at "uses:". When it is not local, this function returns
+// an empty string.
+func localUsesPath(uses *String) string {
+	if uses == nil || uses.ContainsExpression() || !strings.HasPrefix(uses.Value, "./") {
+		return ""
+	}
+	return path.Clean(uses.Value)
+}
+
+// lineRangeOf returns the line range of the node which starts at the start line and ends before
+// the start line of the next sibling node.
+func lineRangeOf(start int, siblings []int, last int) (int, int) {
+	end := last
+	for _, s := range siblings {
+		if s > start && s-1 < end {
+			end = s - 1
+		}
+	}
+	return start, end
+}
+
+func jobStartLine(j *Job) int {
+	l := j.Pos.Line
+	if j.ID != nil && j.ID.Pos.Line < l {
+		l = j.ID.Pos.Line
+	}
+	return l
+}
+
+// affectedLines returns the lines of the jobs and steps in the workflow which call the local
+// reusable workflows or the local actions changed in the diff. When the workflow does not call any
+// of them, this function returns nil. Note that only direct calls are considered. Dependencies of
+// the called workflows and actions such as a local action used by a local composite action are not
+// followed.
+func (d gitDiff) affectedLines(w *Workflow, numLines int) lineSet {
+	jobStarts := make([]int, 0, len(w.Jobs))
+	for _, j := range w.Jobs {
+		if j != nil && j.Pos != nil {
+			jobStarts = append(jobStarts, jobStartLine(j))
+		}
+	}
+
+	var lines lineSet
+	add := func(start, end int) {
+		if lines == nil {
+			lines = lineSet{}
+		}
+		lines.addRange(start, end)
+	}
+
+	for _, j := range w.Jobs {
+		if j == nil || j.Pos == nil {
+			continue
+		}
+		js, je := lineRangeOf(jobStartLine(j), jobStarts, numLines)
+
+		if j.WorkflowCall != nil {
+			if p := localUsesPath(j.WorkflowCall.Uses); p != "" && d.affects(p) {
+				add(js, je)
+			}
+			continue
+		}
+
+		stepStarts := make([]int, 0, len(j.Steps))
+		for _, s := range j.Steps {
+			stepStarts = append(stepStarts, s.Pos.Line)
+		}
+		for _, s := range j.Steps {
+			e, ok := s.Exec.(*ExecAction)
+			if !ok {
+				continue
+			}
+			if p := localUsesPath(e.Uses); p != "" && d.affects(p) {
+				add(lineRangeOf(s.Pos.Line, stepStarts, je))
+			}
+		}
+	}
+
+	return lines
+}
+
+// changedWorkflows returns the workflow files in the project which were changed in the diff or
+// which call the changed local reusable workflows or local actions. The returned map is from the
+// absolute file path of the workflow to the changed lines. When allLines is true, the lines are
+// always nil which means all lines.
+func (d gitDiff) changedWorkflows(p *Project, allLines bool) (map[string]lineSet, error) {
+	files, err := collectYAMLFiles(p.WorkflowsDir())
+	if err != nil {
+		return nil, err
+	}
+
+	ret := map[string]lineSet{}
+	for _, f := range files {
+		r, err := filepath.Rel(p.RootDir(), f)
+		if err != nil {
+			return nil, err
+		}
+		r = filepath.ToSlash(r)
+
+		changed, ok := d[r]
+
+		src, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %q: %w", f, err)
+		}
+		var affected lineSet
+		if w, _ := Parse(src); w != nil {
+			affected = d.affectedLines(w, bytes.Count(src, []byte{'\n'})+1)
+		}
+		if !ok && affected == nil {
+			continue
+		}
+
+		var ls lineSet
+		if !allLines && (!ok || changed != nil) {
+			ls = lineSet{}
+			maps.Copy(ls, changed)
+			maps.Copy(ls, affected)
+		}
+		ret[f] = ls
+	}
+
+	return ret, nil
+}
+
+// filterErrorsOnLines returns the errors whose positions are on the given lines.
+func filterErrorsOnLines(errs []*Error, lines lineSet) []*Error {
+	return slices.DeleteFunc(errs, func(e *Error) bool { return !lines.has(e.Line) })
+}
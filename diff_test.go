@@ -0,0 +1,288 @@
+package actionlint
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/sys/execabs"
+)
+
+func TestParseGitDiff(t *testing.T) {
+	out := `diff --git a/.github/workflows/ci.yaml b/.github/workflows/ci.yaml
+index 1111111..2222222 100644
+--- a/.github/workflows/ci.yaml
++++ b/.github/workflows/ci.yaml
+@@ -3 +3 @@ on: push
+-    runs-on: ubuntu-latest
++    runs-on: ubuntu-latst
+@@ -10,0 +11,2 @@ jobs:
++      - run: echo hello
++      - run: echo world
+@@ -20,3 +22,0 @@ jobs:
+-      - run: echo removed
+-      - run: echo removed
+-      - run: echo removed
+diff --git a/new.yaml b/new.yaml
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.yaml
+@@ -0,0 +1,3 @@
++on: push
++jobs:
++  test:
+diff --git a/removed.yaml b/removed.yaml
+deleted file mode 100644
+index 4444444..0000000
+--- a/removed.yaml
++++ /dev/null
+@@ -1 +0,0 @@
+-on: push
+diff --git "a/with \"quote\".yaml" "b/with \"quote\".yaml"
+index 5555555..6666666 100644
+--- "a/with \"quote\".yaml"
++++ "b/with \"quote\".yaml"
+@@ -1 +1 @@
+-on: push
++on: pull_request
+`
+	have, err := parseGitDiff([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := gitDiff{
+		".github/workflows/ci.yaml": lineSet{3: {}, 11: {}, 12: {}},
+		"new.yaml":                  lineSet{1: {}, 2: {}, 3: {}},
+		`with "quote".yaml`:         lineSet{1: {}},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestLineSetHas(t *testing.T) {
+	var all lineSet
+	if !all.has(100) {
+		t.Error("nil line set should have all lines")
+	}
+	s := lineSet{}
+	s.addRange(2, 4)
+	for l, want := range map[int]bool{1: false, 2: true, 3: true, 4: true, 5: false} {
+		if have := s.has(l); have != want {
+			t.Errorf("wanted %v for line %d but got %v", want, l, have)
+		}
+	}
+}
+
+func testRunGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)
+	c := execabs.Command("git", args...)
+	c.Dir = dir
+	if out, err := c.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, out)
+	}
+}
+
+func testWriteFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for p, c := range files {
+		p = filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(c), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLinterLintChanges(t *testing.T) {
+	if _, err := execabs.LookPath("git"); err != nil {
+		t.Skip("git command is not found")
+	}
+
+	dir := t.TempDir()
+	testRunGit(t, dir, "init", "-q", "-b", "main")
+	testWriteFiles(t, dir, map[string]string{
+		".github/workflows/changed.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown_context }}
+`,
+		".github/workflows/caller.yaml": `on: push
+jobs:
+  call:
+    uses: ./.github/workflows/reusable.yaml
+  other:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown_context }}
+`,
+		".github/workflows/reusable.yaml": `on:
+  workflow_call:
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`,
+		".github/workflows/action-user.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown_context }}
+      - uses: ./action
+`,
+		".github/workflows/unrelated.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown_context }}
+`,
+		"action/action.yml": `name: Test
+description: Test action
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`,
+	})
+	testRunGit(t, dir, "add", "-A")
+	testRunGit(t, dir, "commit", "-q", "-m", "initial")
+	testRunGit(t, dir, "checkout", "-q", "-b", "feature")
+
+	// Add an error on line 7 of changed.yaml, add a required input to the reusable workflow, and add
+	// a required input to the local action
+	testWriteFiles(t, dir, map[string]string{
+		".github/workflows/changed.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown_context }}
+      - run: echo ${{ another_unknown_context }}
+`,
+		".github/workflows/reusable.yaml": `on:
+  workflow_call:
+    inputs:
+      name:
+        type: string
+        required: true
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`,
+		"action/action.yml": `name: Test
+description: Test action
+inputs:
+  name:
+    description: Name
+    required: true
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`,
+	})
+	testRunGit(t, dir, "commit", "-q", "-am", "change")
+
+	// Untracked workflow is not shown by `git diff`. All lines of it should be checked
+	testWriteFiles(t, dir, map[string]string{
+		".github/workflows/untracked.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown_context }}
+`,
+	})
+
+	type pos struct {
+		file string
+		line int
+	}
+
+	testCases := []struct {
+		what     string
+		allLines bool
+		want     []pos
+	}{
+		{
+			what: "changed lines",
+			want: []pos{
+				{"action-user.yaml", 7},
+				{"caller.yaml", 4},
+				{"changed.yaml", 7},
+				{"untracked.yaml", 6},
+			},
+		},
+		{
+			what:     "all lines",
+			allLines: true,
+			want: []pos{
+				{"action-user.yaml", 6},
+				{"action-user.yaml", 7},
+				{"caller.yaml", 4},
+				{"caller.yaml", 8},
+				{"changed.yaml", 6},
+				{"changed.yaml", 7},
+				{"untracked.yaml", 6},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			l, err := NewLinter(io.Discard, &LinterOptions{WorkingDir: dir})
+			if err != nil {
+				t.Fatal(err)
+			}
+			errs, err := l.LintChanges(dir, "main", tc.allLines)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			have := []pos{}
+			for _, e := range errs {
+				have = append(have, pos{filepath.Base(e.Filepath), e.Line})
+			}
+			have = slices.Compact(have)
+			if diff := cmp.Diff(tc.want, have, cmp.AllowUnexported(pos{})); diff != "" {
+				t.Fatalf("errors mismatch: %s\n%v", diff, errs)
+			}
+		})
+	}
+}
+
+func TestLinterLintChangesUnknownRevision(t *testing.T) {
+	if _, err := execabs.LookPath("git"); err != nil {
+		t.Skip("git command is not found")
+	}
+
+	dir := t.TempDir()
+	testRunGit(t, dir, "init", "-q")
+	testWriteFiles(t, dir, map[string]string{".github/workflows/test.yaml": "on: push\n"})
+	testRunGit(t, dir, "add", "-A")
+	testRunGit(t, dir, "commit", "-q", "-m", "initial")
+
+	l, err := NewLinter(io.Discard, &LinterOptions{WorkingDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.LintChanges(dir, "this-revision-does-not-exist", false); err == nil {
+		t.Fatal("error did not occur")
+	}
+}
@@ -32,8 +32,60 @@ type Error struct {
 	Line int
 	// Column is a column number where the error occurred. This value is 1-based.
 	Column int
+	// EndLine is a line number where the error range ends. This value is 1-based. When the end
+	// of the range is unknown, this value is 0.
+	EndLine int
+	// EndColumn is a column number where the error range ends. This value is 1-based and the
+	// character at this column is included in the range. When the end of the range is unknown,
+	// this value is 0.
+	EndColumn int
 	// Kind is a string to represent kind of the error. Usually rule name which found the error.
 	Kind string
+	// Code is a stable identifier of the error like "expression/untrusted-input". It consists of
+	// the rule name and the sub-code to distinguish the errors found by the same rule. This value is
+	// empty when the rule does not assign any code to the error.
+	Code string
+	// Suggestions is a list of textual replacements to fix the error. This field is nil when the
+	// rule cannot suggest any fix.
+	Suggestions []*Suggestion
+	// Related is a list of other locations related to the error. For example, an error of duplicate
+	// job ID has the location where the job ID is defined first.
+	Related []RelatedLocation
+}
+
+// RelatedLocation is a location related to an error with a note to explain the location.
+type RelatedLocation struct {
+	// Filepath is a file path of the location. When it is empty, the location is in the same file
+	// as the error.
+	Filepath string `json:"filepath,omitempty"`
+	// Line is a line number of the location. This value is 1-based.
+	Line int `json:"line"`
+	// Column is a column number of the location. This value is 1-based.
+	Column int `json:"column"`
+	// Note is a short message to explain the location.
+	Note string `json:"note"`
+}
+
+// relatedAt creates a new RelatedLocation instance at the position in the same file as the error.
+func relatedAt(pos *Pos, note string) RelatedLocation {
+	return RelatedLocation{Line: pos.Line, Column: pos.Col, Note: note}
+}
+
+// Suggestion is a textual replacement to fix an error. The text in the range from (Line, Column)
+// to (EndLine, EndColumn) in the source is replaced with Text. All positions are 1-based and
+// EndColumn is inclusive.
+type Suggestion struct {
+	// Line is a line number where the replaced range starts.
+	Line int
+	// Column is a column number where the replaced range starts.
+	Column int
+	// EndLine is a line number where the replaced range ends.
+	EndLine int
+	// EndColumn is a column number where the replaced range ends. The character at this column is
+	// also replaced.
+	EndColumn int
+	// Text is a new text to replace the range.
+	Text string
 }
 
 // Error returns summary of the error as string.
@@ -66,27 +118,80 @@ func errorfAt(pos *Pos, kind string, format string, args ...interface{}) *Error
 // GetTemplateFields fields for formatting this error with Go template.
 func (e *Error) GetTemplateFields(source []byte) *ErrorTemplateFields {
 	snippet := ""
-	end := e.Column
+	endLine, end := e.Line, e.Column
+	if e.EndLine > 0 {
+		endLine, end = e.EndLine, e.EndColumn
+	}
 	if len(source) > 0 && e.Line > 0 {
 		if l, ok := e.getLine(source); ok {
 			snippet = l
 			if len(l) >= e.Column-1 {
 				if i := e.getIndicator(l); i != "" {
 					snippet += "\n" + i
-					end = len(i) // Byte length can be used here because this line only contains ASCII
+					if e.EndLine <= 0 {
+						end = len(i) // Byte length can be used here because this line only contains ASCII
+					}
 				}
 			}
 		}
 	}
 
+	var related []RelatedLocation
+	if len(e.Related) > 0 {
+		related = make([]RelatedLocation, 0, len(e.Related))
+		for _, r := range e.Related {
+			if r.Filepath == "" {
+				r.Filepath = e.Filepath
+			}
+			related = append(related, r)
+		}
+	}
+
 	return &ErrorTemplateFields{
 		Message:   e.Message,
 		Filepath:  e.Filepath,
 		Line:      e.Line,
 		Column:    e.Column,
 		Kind:      e.Kind,
+		Code:      e.code(),
+		URL:       ErrorCodeURL(e.code()),
 		Snippet:   snippet,
+		EndLine:   endLine,
 		EndColumn: end,
+		Related:   related,
+	}
+}
+
+// code returns the code of the error. When no code is assigned to the error, the rule name is
+// returned instead.
+func (e *Error) code() string {
+	if e.Code != "" {
+		return e.Code
+	}
+	return e.Kind
+}
+
+// setEnd sets the end position of the error range. The end position before the start position is
+// ignored.
+func (e *Error) setEnd(end *Pos) {
+	if end == nil || end.IsBefore(&Pos{e.Line, e.Column}) {
+		return
+	}
+	e.EndLine = end.Line
+	e.EndColumn = end.Col
+}
+
+// setErrorEndsAtStrings sets the end positions of the errors reported at the start positions of
+// strings. Most rules report errors only with the start positions of the string nodes such as
+// String.Pos. The ends are the end positions of the strings indexed by their start positions.
+func setErrorEndsAtStrings(errs []*Error, ends map[Pos]*Pos) {
+	for _, err := range errs {
+		if err.EndLine > 0 {
+			continue
+		}
+		if end, ok := ends[Pos{err.Line, err.Column}]; ok {
+			err.setEnd(end)
+		}
 	}
 }
 
@@ -103,9 +208,34 @@ func (e *Error) PrettyPrint(w io.Writer, source []byte) {
 	bold.Fprint(w, e.Message)
 	gray.Fprintf(w, " [%s]\n", e.Kind)
 
-	if len(source) == 0 || e.Line <= 0 {
-		return
+	if len(source) > 0 && e.Line > 0 {
+		e.printSnippet(w, source)
 	}
+
+	// Related locations are shown as notes following the error. Snippets are only available for
+	// the locations in the same file
+	for _, r := range e.Related {
+		path, src := r.Filepath, source
+		if path == "" || path == e.Filepath {
+			path = e.Filepath
+		} else {
+			src = nil
+		}
+		yellow.Fprint(w, path)
+		gray.Fprint(w, ":")
+		fmt.Fprint(w, r.Line)
+		gray.Fprint(w, ":")
+		fmt.Fprint(w, r.Column)
+		gray.Fprint(w, ": note: ")
+		fmt.Fprintln(w, r.Note)
+		if len(src) > 0 && r.Line > 0 {
+			n := &Error{Line: r.Line, Column: r.Column}
+			n.printSnippet(w, src)
+		}
+	}
+}
+
+func (e *Error) printSnippet(w io.Writer, source []byte) {
 	line, ok := e.getLine(source)
 	if !ok || len(line) < e.Column-1 {
 		return
@@ -139,6 +269,12 @@ func (e *Error) getIndicator(line string) string {
 
 	start := e.Column - 1 // Column is 1-based
 
+	if e.EndLine >= e.Line {
+		if i := e.getRangeIndicator(line); i != "" {
+			return i
+		}
+	}
+
 	// Count width of non-space characters after '^' for underline
 	uw := 0
 	r := strings.NewReader(line[start:])
@@ -158,6 +294,23 @@ func (e *Error) getIndicator(line string) string {
 	return fmt.Sprintf("%s^%s", strings.Repeat(" ", sw), strings.Repeat("~", uw))
 }
 
+// getRangeIndicator returns the indicator which underlines the range from Column to EndColumn. When
+// the range continues to the following lines, it underlines until the end of the line.
+func (e *Error) getRangeIndicator(line string) string {
+	rs := []rune(line)
+	start := e.Column - 1
+	end := len(rs)
+	if e.EndLine == e.Line && e.EndColumn < end {
+		end = e.EndColumn
+	}
+	if start >= end {
+		return ""
+	}
+	sw := runewidth.StringWidth(string(rs[:start]))
+	uw := runewidth.StringWidth(string(rs[start:end])) - 1 // Decrement for place for '^'
+	return fmt.Sprintf("%s^%s", strings.Repeat(" ", sw), strings.Repeat("~", max(uw, 0)))
+}
+
 func compareErrors(lhs, rhs *Error) int {
 	if c := strings.Compare(lhs.Filepath, rhs.Filepath); c != 0 {
 		return c
@@ -191,12 +344,24 @@ type ErrorTemplateFields struct {
 	Column int `json:"column"`
 	// Kind is a rule name the error belongs to.
 	Kind string `json:"kind"`
+	// Code is a stable identifier of the error like "expression/untrusted-input". When no code is
+	// assigned to the error, it is the same as Kind.
+	Code string `json:"code"`
+	// URL is a URL of the document section which explains the error.
+	URL string `json:"url"`
 	// Snippet is a code snippet and indicator to indicate where the error occurred.
 	// When encoding into JSON, this field may be omitted when the snippet is empty.
 	Snippet string `json:"snippet,omitempty"`
-	// EndColumn is a column number where the error indicator (^~~~~~~) ends. When no indicator
-	// can be shown, EndColumn is equal to Column.
+	// EndLine is a line number where the error range ends. When the range of the error is unknown,
+	// EndLine is equal to Line.
+	EndLine int `json:"end_line"`
+	// EndColumn is a column number where the error range ends at EndLine. When the range of the
+	// error is unknown, it is a column number where the error indicator (^~~~~~~) ends. When no
+	// indicator can be shown, EndColumn is equal to Column.
 	EndColumn int `json:"end_column"`
+	// Related is a list of other locations related to the error. Filepath of each location is
+	// always set. When encoding into JSON, this field may be omitted when no location is related.
+	Related []RelatedLocation `json:"related,omitempty"`
 }
 
 func unescapeBackslash(s string) string {
@@ -306,6 +471,22 @@ func (f *ErrorFormatter) PrintErrors(out io.Writer, errs []*Error, src []byte) e
 	return f.Print(out, t)
 }
 
+// PrintResults prints the errors in the results after formatting them with template. Files which
+// have no error are ignored since the template takes a slice of errors.
+func (f *ErrorFormatter) PrintResults(out io.Writer, results []*FileResult) error {
+	n := 0
+	for _, r := range results {
+		n += len(r.Errors)
+	}
+	t := make([]*ErrorTemplateFields, 0, n)
+	for _, r := range results {
+		for _, err := range r.Errors {
+			t = append(t, err.GetTemplateFields(r.Source))
+		}
+	}
+	return f.Print(out, t)
+}
+
 // RegisterRule registers the rule. Registered rules are used to get description and index of error
 // kinds when you use `kindDescription` or `kindIndex` functions in an error format template. This
 // method can be called multiple times safely in parallel.
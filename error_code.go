@@ -0,0 +1,153 @@
+package actionlint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// errorCodeAnchors is a mapping from error codes to anchors of the sections in docs/checks.md which
+// explain the errors. Keys are the full error codes like "expression/untrusted-input" or the rule
+// names. The rule names are used as fallback when the error has no code or the code is unknown.
+//
+// Note that error codes are stable identifiers. They are used for ignoring specific errors. Do not
+// rename or remove the existing codes.
+var errorCodeAnchors = map[string]string{
+	"syntax-check":                     "check-unexpected-keys",
+	"syntax-check/yaml-error":          "check-unexpected-keys",
+	"syntax-check/unexpected-key":      "check-unexpected-keys",
+	"syntax-check/missing-key":         "check-missing-required-duplicate-keys",
+	"syntax-check/duplicate-key":       "check-missing-required-duplicate-keys",
+	"syntax-check/empty-value":         "check-empty-mapping",
+	"syntax-check/unexpected-node":     "check-mapping-values",
+	"syntax-check/invalid-value":       "check-mapping-values",
+	"syntax-check/expression-expected": "check-mapping-values",
+	"syntax-check/yaml-anchor":         "yaml-anchors",
+	"syntax-check/merge-key":           "yaml-anchors",
+
+	"expression":                           "check-syntax-expression",
+	"expression/syntax-error":              "check-syntax-expression",
+	"expression/type-mismatch":             "check-type-check-expression",
+	"expression/undefined-property":        "check-type-check-expression",
+	"expression/object-in-template":        "check-type-check-expression",
+	"expression/multiple-expressions":      "check-type-check-expression",
+	"expression/undefined-variable":        "check-contexts-and-builtin-func",
+	"expression/undefined-function":        "check-contexts-and-builtin-func",
+	"expression/wrong-argument-count":      "check-contexts-and-builtin-func",
+	"expression/invalid-format-call":       "check-contexts-and-builtin-func",
+	"expression/invalid-json":              "check-contexts-and-builtin-func",
+	"expression/invalid-config-variable":   "check-contexts-and-builtin-func",
+	"expression/undefined-config-variable": "check-contexts-and-builtin-func",
+	"expression/invalid-comparison":        "check-comparison-types",
+	"expression/untrusted-input":           "untrusted-inputs",
+	"expression/unavailable-context":       "ctx-spfunc-availability",
+	"expression/unavailable-function":      "ctx-spfunc-availability",
+	"expression/workflow-call-input":       "check-reusable-workflows",
+	"expression/metadata-error":            "check-reusable-workflows",
+
+	"action":                        "check-action-format",
+	"action/invalid-format":         "check-action-format",
+	"action/undefined-input":        "check-local-action-inputs",
+	"action/missing-required-input": "check-local-action-inputs",
+	"action/outdated-action":        "detect-outdated-popular-actions",
+	"action/deprecated-input":       "deprecated-inputs-usage",
+	"action/metadata-error":         "action-metadata-syntax",
+	"action/invalid-metadata":       "action-metadata-syntax",
+	"action/invalid-runs":           "action-metadata-syntax",
+
+	"events":                                   "check-webhook-events",
+	"events/unknown-event":                     "check-webhook-events",
+	"events/invalid-activity-type":             "check-webhook-events",
+	"events/unavailable-types":                 "check-webhook-events",
+	"events/unavailable-filter":                "check-webhook-events",
+	"events/exclusive-filters":                 "check-webhook-events",
+	"events/missing-workflows":                 "check-webhook-events",
+	"events/unavailable-workflows":             "check-webhook-events",
+	"events/invalid-cron":                      "check-cron-syntax-and-timezone",
+	"events/too-frequent-schedule":             "check-cron-syntax-and-timezone",
+	"events/invalid-timezone":                  "check-cron-syntax-and-timezone",
+	"events/missing-choice-options":            "check-workflow-dispatch-events",
+	"events/duplicate-choice-option":           "check-workflow-dispatch-events",
+	"events/invalid-choice-default":            "check-workflow-dispatch-events",
+	"events/unexpected-options":                "check-workflow-dispatch-events",
+	"events/invalid-workflow-dispatch-default": "check-workflow-dispatch-events",
+	"events/too-many-inputs":                   "check-workflow-dispatch-events",
+	"events/invalid-workflow-call-default":     "check-reusable-workflows",
+	"events/required-input-with-default":       "check-reusable-workflows",
+
+	"matrix":                         "check-matrix-values",
+	"matrix/duplicate-value":         "check-matrix-values",
+	"matrix/exclude-without-matrix":  "check-matrix-values",
+	"matrix/undefined-exclude-key":   "check-matrix-values",
+	"matrix/unmatched-exclude-value": "check-matrix-values",
+
+	"credentials":                     "check-hardcoded-credentials",
+	"credentials/hardcoded-password":  "check-hardcoded-credentials",
+	"credentials/hardcoded-token":     "check-hardcoded-tokens",
+	"credentials/random-credential":   "check-hardcoded-tokens",
+	"shell-name":                      "check-shell-names",
+	"shell-name/invalid-shell":        "check-shell-names",
+	"runner-label":                    "check-runner-labels",
+	"runner-label/unknown-label":      "check-runner-labels",
+	"runner-label/invalid-pattern":    "check-runner-labels",
+	"runner-label/conflicting-labels": "check-runner-labels",
+
+	"job-needs":                   "check-job-deps",
+	"job-needs/duplicate-needs":   "check-job-deps",
+	"job-needs/undefined-job":     "check-job-deps",
+	"job-needs/cyclic-dependency": "check-job-deps",
+	"job-needs/duplicate-job-id":  "check-job-step-ids",
+
+	"env-var":                   "check-env-var-names",
+	"env-var/invalid-name":      "check-env-var-names",
+	"id":                        "check-job-step-ids",
+	"id/duplicate-step-id":      "check-job-step-ids",
+	"id/invalid-id":             "id-naming-convention",
+	"glob":                      "check-glob-pattern",
+	"glob/invalid-pattern":      "check-glob-pattern",
+	"permissions":               "permissions",
+	"permissions/invalid-value": "permissions",
+	"permissions/unknown-scope": "permissions",
+
+	"id-token":                              "id-token-permission",
+	"id-token/untrusted-event":              "id-token-permission",
+	"id-token/unused-id-token":              "id-token-permission",
+	"workflow-call":                         "check-reusable-workflows",
+	"workflow-call/invalid-uses":            "check-reusable-workflows",
+	"workflow-call/metadata-error":          "check-reusable-workflows",
+	"workflow-call/missing-required-input":  "check-reusable-workflows",
+	"workflow-call/undefined-input":         "check-reusable-workflows",
+	"workflow-call/missing-required-secret": "check-reusable-workflows",
+	"workflow-call/undefined-secret":        "check-reusable-workflows",
+	"workflow-call/inherit-secrets":         "inherit-secrets-to-external",
+
+	"deprecated-commands":                    "check-deprecated-workflow-commands",
+	"deprecated-commands/deprecated-command": "check-deprecated-workflow-commands",
+	"env-file":                               "untrusted-env-file-writes",
+	"env-file/untrusted-write":               "untrusted-env-file-writes",
+	"if-cond":                                "if-cond-constant",
+	"if-cond/always-true":                    "if-cond-constant",
+	"if-cond/constant-condition":             "if-cond-constant",
+	"workflow-run":                           "workflow-run-poisoning",
+	"workflow-run/metadata-error":            "workflow-run-poisoning",
+	"workflow-run/poisoning":                 "workflow-run-poisoning",
+
+	"shellcheck": "check-shellcheck-integ",
+	"pyflakes":   "check-pyflakes-integ",
+}
+
+// ErrorCodeURL returns the URL of the section in docs/checks.md which explains the error code. The
+// code may be a rule name. When the code is unknown, the section for the rule is looked up. When no
+// section is found, the URL of the document itself is returned.
+func ErrorCodeURL(code string) string {
+	url := fmt.Sprintf("https://github.com/rhysd/actionlint/blob/%s/docs/checks.md", getDocsRef())
+	a, ok := errorCodeAnchors[code]
+	if !ok {
+		if k, _, found := strings.Cut(code, "/"); found {
+			a, ok = errorCodeAnchors[k]
+		}
+	}
+	if !ok {
+		return url
+	}
+	return url + "#" + a
+}
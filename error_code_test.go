@@ -0,0 +1,127 @@
+package actionlint
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestErrorCodeURL(t *testing.T) {
+	base := "https://github.com/rhysd/actionlint/blob/main/docs/checks.md"
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"expression/untrusted-input", base + "#untrusted-inputs"},
+		{"syntax-check/duplicate-key", base + "#check-missing-required-duplicate-keys"},
+		{"shellcheck/SC2086", base + "#check-shellcheck-integ"},
+		{"shellcheck", base + "#check-shellcheck-integ"},
+		{"expression/unknown-code", base + "#check-syntax-expression"},
+		{"my-own-rule", base},
+		{"my-own-rule/some-code", base},
+		{"", base},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.code, func(t *testing.T) {
+			if have := ErrorCodeURL(tc.code); have != tc.want {
+				t.Fatalf("wanted %q but got %q", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestErrorCodeAnchorsExistInDocs(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("docs", "checks.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	anchors := map[string]struct{}{}
+	for _, m := range regexp.MustCompile(`<a id="([^"]+)"></a>`).FindAllSubmatch(b, -1) {
+		anchors[string(m[1])] = struct{}{}
+	}
+
+	for code, a := range errorCodeAnchors {
+		if _, ok := anchors[a]; !ok {
+			t.Errorf("anchor %q for error code %q does not exist in docs/checks.md", a, code)
+		}
+	}
+}
+
+// Check all error codes reported by the rules with literal strings are registered so that users can
+// find the documentation of the errors.
+func TestErrorCodeAllCodesInRulesAreRegistered(t *testing.T) {
+	files, err := filepath.Glob("rule_*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := 0
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := goparser.ParseFile(token.NewFileSet(), file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name := ""
+		codes := []string{}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CompositeLit:
+				// RuleBase{name: "rule-name", ...}
+				if i, ok := n.Type.(*ast.Ident); !ok || i.Name != "RuleBase" {
+					return true
+				}
+				for _, e := range n.Elts {
+					kv, ok := e.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					if k, ok := kv.Key.(*ast.Ident); ok && k.Name == "name" {
+						if lit, ok := kv.Value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							name, _ = strconv.Unquote(lit.Value)
+						}
+					}
+				}
+			case *ast.CallExpr:
+				// rule.ErrorfWithCode(pos, "code", ...)
+				sel, ok := n.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "ErrorfWithCode" || len(n.Args) < 2 {
+					return true
+				}
+				if lit, ok := n.Args[1].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					c, _ := strconv.Unquote(lit.Value)
+					codes = append(codes, c)
+				}
+			}
+			return true
+		})
+		if len(codes) == 0 {
+			continue
+		}
+		if name == "" {
+			t.Errorf("rule name is not found in %s", file)
+			continue
+		}
+		for _, c := range codes {
+			found++
+			code := name + "/" + c
+			if _, ok := errorCodeAnchors[code]; !ok {
+				t.Errorf("error code %q reported in %s is not registered in errorCodeAnchors", code, file)
+			}
+		}
+	}
+
+	if found == 0 {
+		t.Fatal("no error code was found in rules")
+	}
+}
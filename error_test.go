@@ -227,6 +227,105 @@ func TestErrorPrettyPrint(t *testing.T) {
 	}
 }
 
+func TestErrorPrettyPrintRelatedLocations(t *testing.T) {
+	testCases := []struct {
+		what     string
+		related  []RelatedLocation
+		source   string
+		expected string
+	}{
+		{
+			what:    "related location in the same file",
+			related: []RelatedLocation{{Line: 1, Column: 1, Note: "defined here"}},
+			source:  "foo: 1\nfoo: 2",
+			expected: `filename.txt:2:1: duplicate [kind]
+  |
+2 | foo: 2
+  | ^~~~
+filename.txt:1:1: note: defined here
+  |
+1 | foo: 1
+  | ^~~~
+`,
+		},
+		{
+			what:    "related location in other file",
+			related: []RelatedLocation{{Filepath: "other.txt", Line: 3, Column: 5, Note: "defined here"}},
+			source:  "foo: 1\nfoo: 2",
+			expected: `filename.txt:2:1: duplicate [kind]
+  |
+2 | foo: 2
+  | ^~~~
+other.txt:3:5: note: defined here
+`,
+		},
+		{
+			what: "multiple related locations",
+			related: []RelatedLocation{
+				{Line: 1, Column: 1, Note: "first"},
+				{Filepath: "filename.txt", Line: 2, Column: 6, Note: "second"},
+			},
+			source: "foo: 1\nfoo: 2",
+			expected: `filename.txt:2:1: duplicate [kind]
+  |
+2 | foo: 2
+  | ^~~~
+filename.txt:1:1: note: first
+  |
+1 | foo: 1
+  | ^~~~
+filename.txt:2:6: note: second
+  |
+2 | foo: 2
+  |      ^
+`,
+		},
+		{
+			what:    "no source",
+			related: []RelatedLocation{{Line: 1, Column: 1, Note: "defined here"}},
+			expected: `filename.txt:2:1: duplicate [kind]
+filename.txt:1:1: note: defined here
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			err := errorAt(&Pos{2, 1}, "kind", "duplicate")
+			err.Filepath = "filename.txt"
+			err.Related = tc.related
+
+			var buf bytes.Buffer
+			err.PrettyPrint(&buf, []byte(tc.source))
+
+			if out := buf.String(); out != tc.expected {
+				t.Fatalf("wanted:\n%q\n\nhave:\n%q", tc.expected, out)
+			}
+		})
+	}
+}
+
+func TestErrorGetTemplateFieldsRelatedLocations(t *testing.T) {
+	err := errorAt(&Pos{2, 1}, "kind", "duplicate")
+	err.Filepath = "filename.txt"
+	err.Related = []RelatedLocation{
+		{Line: 1, Column: 1, Note: "in the same file"},
+		{Filepath: "other.txt", Line: 3, Column: 5, Note: "in other file"},
+	}
+
+	have := err.GetTemplateFields(nil).Related
+	want := []RelatedLocation{
+		{Filepath: "filename.txt", Line: 1, Column: 1, Note: "in the same file"},
+		{Filepath: "other.txt", Line: 3, Column: 5, Note: "in other file"},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+	if err.Related[0].Filepath != "" {
+		t.Fatalf("related locations of the error were modified: %v", err.Related)
+	}
+}
+
 func TestErrorSortErrorsByPosition(t *testing.T) {
 	testCases := [][]struct {
 		line int
@@ -395,6 +494,39 @@ func TestErrorGetTemplateFieldsOK(t *testing.T) {
 	}
 }
 
+func TestErrorGetTemplateFieldsCode(t *testing.T) {
+	r := NewRuleBase("expression", "")
+	withCode := r.ErrorfWithCode(&Pos{1, 1}, "untrusted-input", "message %d", 1)
+	if withCode.Code != "expression/untrusted-input" {
+		t.Fatalf("unexpected code: %q", withCode.Code)
+	}
+	if withCode.Message != "message 1" {
+		t.Fatalf("unexpected message: %q", withCode.Message)
+	}
+	r.Errorf(&Pos{1, 1}, "message %d", 2)
+	errs := r.Errs()
+	if len(errs) != 2 || errs[0] != withCode {
+		t.Fatalf("errors are not stored in the rule: %v", errs)
+	}
+
+	for _, tc := range []struct {
+		err  *Error
+		code string
+		url  string
+	}{
+		{errs[0], "expression/untrusted-input", "#untrusted-inputs"},
+		{errs[1], "expression", "#check-syntax-expression"},
+	} {
+		f := tc.err.GetTemplateFields(nil)
+		if f.Code != tc.code {
+			t.Errorf("wanted code %q but have %q", tc.code, f.Code)
+		}
+		if !strings.HasSuffix(f.URL, tc.url) {
+			t.Errorf("wanted URL ending with %q but have %q", tc.url, f.URL)
+		}
+	}
+}
+
 // Regression test for #128
 func TestErrorGetTemplateFieldsColumnIsOutOfBounds(t *testing.T) {
 	err := errorAt(&Pos{1, 9999}, "kind", "this is message")
@@ -718,3 +850,66 @@ func TestErrorFormatterRegisterRuleInParallel(t *testing.T) {
 		t.Fatalf("not all rules were registered. %d rules were registered", len(f.rules))
 	}
 }
+
+func TestErrorGetTemplateFieldsWithRange(t *testing.T) {
+	testCases := []struct {
+		what    string
+		pos     *Pos
+		end     *Pos
+		source  string
+		snippet string
+		endLine int
+		endCol  int
+	}{
+		{
+			what:    "range in line",
+			pos:     &Pos{1, 6},
+			end:     &Pos{1, 11},
+			source:  "this is source",
+			snippet: "this is source\n     ^~~~~~",
+			endLine: 1,
+			endCol:  11,
+		},
+		{
+			what:    "range across lines",
+			pos:     &Pos{1, 9},
+			end:     &Pos{2, 3},
+			source:  "this is source\nfoo bar",
+			snippet: "this is source\n        ^~~~~~",
+			endLine: 2,
+			endCol:  3,
+		},
+		{
+			what:    "range with multi-byte characters",
+			pos:     &Pos{1, 3},
+			end:     &Pos{1, 4},
+			source:  "a あいう b",
+			snippet: "a あいう b\n  ^~~~",
+			endLine: 1,
+			endCol:  4,
+		},
+		{
+			what:    "end is before start",
+			pos:     &Pos{1, 6},
+			end:     &Pos{1, 1},
+			source:  "this is source",
+			snippet: "this is source\n     ^~",
+			endLine: 1,
+			endCol:  7,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			r := NewRuleBase("kind", "")
+			r.ErrorfRange(tc.pos, tc.end, "message")
+			f := r.Errs()[0].GetTemplateFields([]byte(tc.source))
+			if f.Snippet != tc.snippet {
+				t.Fatalf("wanted %q but have %q", tc.snippet, f.Snippet)
+			}
+			if f.EndLine != tc.endLine || f.EndColumn != tc.endCol {
+				t.Fatalf("wanted end %d:%d but have %d:%d", tc.endLine, tc.endCol, f.EndLine, f.EndColumn)
+			}
+		})
+	}
+}
@@ -63,3 +63,34 @@ func ExampleLinter_yourOwnRule() {
 	fmt.Println(len(errs), "lint errors found by actionlint")
 	// Output: 1 lint errors found by actionlint
 }
+
+func ExampleRuleRegistry() {
+	// BuiltinRuleRegistry returns a registry of all built-in rules. Register your own rule with its
+	// factory function. The factory is called on linting each workflow file with RuleContext which
+	// provides the same inputs as built-in rules such as the file path, the project, the config,
+	// and the caches of local actions and local workflows.
+	reg := actionlint.BuiltinRuleRegistry()
+	err := reg.Register("step-name", func(ctx *actionlint.RuleContext) (actionlint.Rule, error) {
+		return NewRuleStepName(), nil
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Built-in rules can be removed from the registry
+	reg.Unregister("runner-label")
+
+	l, err := actionlint.NewLinter(io.Discard, &actionlint.LinterOptions{Rules: reg})
+	if err != nil {
+		panic(err)
+	}
+
+	f := filepath.Join("testdata", "ok", "minimal.yaml")
+	errs, err := l.LintFile(f, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(len(errs), "lint errors found by actionlint")
+	// Output: 1 lint errors found by actionlint
+}
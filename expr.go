@@ -13,6 +13,9 @@ type ExprError struct {
 	Line int
 	// Column is column number position which caused the error. Note that this value is 1-based.
 	Column int
+	// Code is a sub-code to identify the kind of the error like "syntax-error" or
+	// "undefined-property". It does not contain the rule name.
+	Code string
 }
 
 func (e *ExprError) Error() string {
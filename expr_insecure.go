@@ -1,6 +1,7 @@
 package actionlint
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -15,6 +16,18 @@ type UntrustedInputMap struct {
 	Name     string
 	Parent   *UntrustedInputMap
 	Children map[string]*UntrustedInputMap
+	// anyProp is true when '*' node matches to any object property as well as array elements. This
+	// is set to '*' nodes added via UntrustedInputSearchRoots.AddPath.
+	anyProp bool
+	// anyPropChild is a '*' child node which matches to any object property. It is added via
+	// UntrustedInputSearchRoots.AddPath when the path goes through a builtin '*' node which matches
+	// only array elements. The builtin node is kept as-is so that the builtin inputs under it are not
+	// matched to object properties.
+	anyPropChild *UntrustedInputMap
+	// subtree is true when the value of this node and all properties under this node are untrusted.
+	// This is set to the nodes under the path added via UntrustedInputSearchRoots.AddPath when the
+	// path was already in the search tree.
+	subtree bool
 }
 
 func (m *UntrustedInputMap) String() string {
@@ -29,10 +42,24 @@ func (m *UntrustedInputMap) findObjectProp(name string) (*UntrustedInputMap, boo
 		if c, ok := m.Children[name]; ok {
 			return c, true
 		}
+		if c, ok := m.Children["*"]; ok && c.anyProp {
+			return c, true
+		}
+		if m.anyPropChild != nil {
+			return m.anyPropChild, true
+		}
+	}
+	if m != nil && m.subtree {
+		return m, true // All properties under this node are untrusted
 	}
 	return nil, false
 }
 
+// isUntrusted returns whether the value of this node is an untrusted input.
+func (m *UntrustedInputMap) isUntrusted() bool {
+	return m.Children == nil || m.subtree
+}
+
 // Find child array element in this map. This is special case with object filter where its receiver is an array
 func (m *UntrustedInputMap) findArrayElem() (*UntrustedInputMap, bool) {
 	return m.findObjectProp("*")
@@ -47,6 +74,67 @@ func (m *UntrustedInputMap) buildPath(b *strings.Builder) {
 	b.WriteString(m.Name)
 }
 
+func (m *UntrustedInputMap) deepCopy() *UntrustedInputMap {
+	c := &UntrustedInputMap{Name: m.Name, anyProp: m.anyProp, subtree: m.subtree}
+	if m.Children != nil {
+		c.Children = make(map[string]*UntrustedInputMap, len(m.Children))
+		for n, child := range m.Children {
+			child = child.deepCopy()
+			child.Parent = c
+			c.Children[n] = child
+		}
+	}
+	if m.anyPropChild != nil {
+		c.anyPropChild = m.anyPropChild.deepCopy()
+		c.anyPropChild.Parent = c
+	}
+	return c
+}
+
+// markSubtree makes this node and all nodes under it untrusted. The children are kept so that the
+// paths of the builtin inputs under this node are still reported.
+func (m *UntrustedInputMap) markSubtree() {
+	m.subtree = true
+	for _, c := range m.Children {
+		c.markSubtree()
+	}
+	if m.anyPropChild != nil {
+		m.anyPropChild.markSubtree()
+	}
+}
+
+// addPath adds the path of the property names under this node. When the path is empty, the entire
+// subtree of this node becomes untrusted.
+func (m *UntrustedInputMap) addPath(names []string) {
+	if len(names) == 0 {
+		m.markSubtree()
+		return
+	}
+	if m.isUntrusted() {
+		return // This node is already untrusted
+	}
+
+	n, rest := names[0], names[1:]
+	c, ok := m.Children[n]
+	if !ok {
+		c = newUntrustedInputMapChain(names)
+		c.Parent = m
+		m.Children[n] = c
+		return
+	}
+	if n == "*" && !c.anyProp {
+		// The builtin '*' node matches only array elements. Add the path to both the builtin node
+		// for array elements and the separate node for object properties.
+		if m.anyPropChild == nil {
+			m.anyPropChild = newUntrustedInputMapChain(names)
+			m.anyPropChild.Parent = m
+		} else {
+			m.anyPropChild.addPath(rest)
+		}
+	}
+	c.addPath(rest)
+}
+
 // NewUntrustedInputMap creates new instance of UntrustedInputMap. It is used for node of search
 // tree of untrusted input checker.
 func NewUntrustedInputMap(name string, children ...*UntrustedInputMap) *UntrustedInputMap {
@@ -76,6 +164,115 @@ func (ms UntrustedInputSearchRoots) AddRoot(m *UntrustedInputMap) {
 	ms[m.Name] = m
 }
 
+// DeepCopy creates a deep copy of the search roots. This is useful to customize the builtin roots
+// without modifying BuiltinUntrustedInputs.
+func (ms UntrustedInputSearchRoots) DeepCopy() UntrustedInputSearchRoots {
+	ret := make(UntrustedInputSearchRoots, len(ms))
+	for _, m := range ms {
+		ret.AddRoot(m.deepCopy())
+	}
+	return ret
+}
+
+// AddPath adds a path to untrusted input like "github.event.client_payload.*" to the search roots.
+// The path is a context name followed by property names separated with '.'. '*' in the path
+// matches to any array element and any object property. When the path is already a part of the
+// search tree, all inputs under the path become untrusted. When an ancestor of the path is already
+// an untrusted input, this method does nothing. When the path goes through a builtin '*' which
+// matches only array elements, the builtin '*' keeps matching only array elements for the builtin
+// inputs under it.
+func (ms UntrustedInputSearchRoots) AddPath(path string) error {
+	names, err := splitUntrustedInputPath(path)
+	if err != nil {
+		return err
+	}
+
+	r, ok := ms[names[0]]
+	if !ok {
+		ms.AddRoot(newUntrustedInputMapChain(names))
+		return nil
+	}
+	r.addPath(names[1:])
+	return nil
+}
+
+// RemovePath removes a path to untrusted input like "github.head_ref" from the search roots. It
+// is useful to mark the input as safe. The path format is the same as AddPath. Ancestors of the
+// removed node which no longer have any child are also removed. When the path is not found in the
+// search roots, this method does nothing.
+func (ms UntrustedInputSearchRoots) RemovePath(path string) error {
+	names, err := splitUntrustedInputPath(path)
+	if err != nil {
+		return err
+	}
+
+	r, ok := ms[names[0]]
+	if !ok {
+		return nil
+	}
+	// '*' in the path may be both the builtin node for array elements and the node for object
+	// properties added via AddPath
+	nodes := []*UntrustedInputMap{r}
+	for _, n := range names[1:] {
+		var next []*UntrustedInputMap
+		for _, cur := range nodes {
+			if c, ok := cur.Children[n]; ok {
+				next = append(next, c)
+			}
+			if n == "*" && cur.anyPropChild != nil {
+				next = append(next, cur.anyPropChild)
+			}
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		nodes = next
+	}
+
+	for _, cur := range nodes {
+		for cur.Parent != nil {
+			p := cur.Parent
+			if p.anyPropChild == cur {
+				p.anyPropChild = nil
+			} else {
+				delete(p.Children, cur.Name)
+			}
+			if len(p.Children) > 0 || p.anyPropChild != nil {
+				break
+			}
+			cur = p
+		}
+		if cur.Parent == nil {
+			delete(ms, cur.Name)
+		}
+	}
+	return nil
+}
+
+func splitUntrustedInputPath(path string) ([]string, error) {
+	names := strings.Split(strings.ToLower(path), ".")
+	for _, n := range names {
+		if n == "" {
+			return nil, fmt.Errorf("invalid path to untrusted input %q. property name must not be empty", path)
+		}
+	}
+	if names[0] == "*" {
+		return nil, fmt.Errorf("invalid path to untrusted input %q. path must start with context name", path)
+	}
+	return names, nil
+}
+
+func newUntrustedInputMapChain(names []string) *UntrustedInputMap {
+	var m *UntrustedInputMap
+	if len(names) == 1 {
+		m = NewUntrustedInputMap(names[0])
+	} else {
+		m = NewUntrustedInputMap(names[0], newUntrustedInputMapChain(names[1:]))
+	}
+	m.anyProp = m.Name == "*"
+	return m
+}
+
 // TODO: Automatically generate BuiltinUntrustedInputs from https://github.com/github/codeql/blob/main/javascript/ql/src/experimental/Security/CWE-094/ExpressionInjection.ql
 
 // BuiltinUntrustedInputs is list of untrusted inputs. These inputs are detected as untrusted in
@@ -154,6 +351,7 @@ type UntrustedInputChecker struct {
 	start           ExprNode
 	errs            []*ExprError
 	safeCalls       int
+	found           []string
 }
 
 // NewUntrustedInputChecker creates a new UntrustedInputChecker instance. The roots argument is a
@@ -257,6 +455,13 @@ func (u *UntrustedInputChecker) onObjectFilter() {
 				u.cur = append(u.cur, c)
 			}
 		}
+		if c := cur.anyPropChild; c != nil {
+			if first {
+				u.cur[i] = c
+			} else {
+				u.cur = append(u.cur, c)
+			}
+		}
 	}
 	if compact {
 		u.compact()
@@ -266,17 +471,19 @@ func (u *UntrustedInputChecker) onObjectFilter() {
 func (u *UntrustedInputChecker) end() {
 	var inputs []string
 	for _, cur := range u.cur {
-		if cur.Children != nil {
-			continue // When `Children` is nil, the node is a leaf
+		if !cur.isUntrusted() {
+			continue
 		}
 		var b strings.Builder
 		cur.buildPath(&b)
 		inputs = append(inputs, b.String())
 	}
+	u.found = append(u.found, inputs...)
 
 	if len(inputs) == 1 {
 		err := errorfAtExpr(
 			u.start,
+			"untrusted-input",
 			"%q is potentially untrusted. avoid using it directly in inline scripts. instead, pass it through an environment variable. see https://docs.github.com/en/actions/reference/security/secure-use#good-practices-for-mitigating-script-injection-attacks for more details",
 			inputs[0],
 		)
@@ -286,6 +493,7 @@ func (u *UntrustedInputChecker) end() {
 		// filter syntax. Show all properties in error message.
 		err := errorfAtExpr(
 			u.start,
+			"untrusted-input",
 			"object filter extracts potentially untrusted properties %s. avoid using the value directly in inline scripts. instead, pass the value through an environment variable. see https://docs.github.com/en/actions/reference/security/secure-use#good-practices-for-mitigating-script-injection-attacks for more details",
 			sortedQuotes(inputs),
 		)
@@ -343,9 +551,15 @@ func (u *UntrustedInputChecker) Errs() []*ExprError {
 	return u.errs
 }
 
+// foundInputs returns paths to untrusted inputs detected by this checker like "github.event.issue.title".
+func (u *UntrustedInputChecker) foundInputs() []string {
+	return u.found
+}
+
 // Init initializes a state of checker.
 func (u *UntrustedInputChecker) Init() {
 	u.errs = u.errs[:0]
+	u.found = u.found[:0]
 	u.safeCalls = 0
 	u.reset()
 }
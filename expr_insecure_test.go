@@ -737,3 +737,205 @@ func BenchmarkInsecureDetectUntrustedInputs(b *testing.B) {
 		}
 	})
 }
+
+func TestExprInsecureAddRemovePaths(t *testing.T) {
+	testCases := []struct {
+		what   string
+		add    []string
+		remove []string
+		input  string
+		want   string
+	}{
+		{
+			what:  "add new root",
+			add:   []string{"inputs.branch_name"},
+			input: "inputs.branch_name",
+			want:  `"inputs.branch_name"`,
+		},
+		{
+			what:  "add path to existing root",
+			add:   []string{"github.event.client_payload.title"},
+			input: "github.event.client_payload.title",
+			want:  `"github.event.client_payload.title"`,
+		},
+		{
+			what:  "case insensitive",
+			add:   []string{"Inputs.Branch_Name"},
+			input: "inputs.BRANCH_NAME",
+			want:  `"inputs.branch_name"`,
+		},
+		{
+			what:  "wildcard matches object property",
+			add:   []string{"github.event.client_payload.*"},
+			input: "github.event.client_payload.foo",
+			want:  `"github.event.client_payload.*"`,
+		},
+		{
+			what:  "wildcard matches array element",
+			add:   []string{"github.event.client_payload.*"},
+			input: "github.event.client_payload[0]",
+			want:  `"github.event.client_payload.*"`,
+		},
+		{
+			what:  "nested wildcard",
+			add:   []string{"github.event.client_payload.*.name"},
+			input: "github.event.client_payload.user.name",
+			want:  `"github.event.client_payload.*.name"`,
+		},
+		{
+			what:  "builtin input is still detected",
+			add:   []string{"github.event.client_payload.*"},
+			input: "github.event.issue.title",
+			want:  `"github.event.issue.title"`,
+		},
+		{
+			what:  "existing path",
+			add:   []string{"github.event.issue.title", "github.event.issue"},
+			input: "github.event.issue.body",
+			want:  `"github.event.issue.body"`,
+		},
+		{
+			what:  "existing inner path makes the node untrusted",
+			add:   []string{"github.event.issue"},
+			input: "github.event.issue",
+			want:  `"github.event.issue"`,
+		},
+		{
+			what:  "existing inner path makes all properties untrusted",
+			add:   []string{"github.event.pull_request"},
+			input: "github.event.pull_request.head.sha",
+			want:  `"github.event.pull_request.head"`,
+		},
+		{
+			what:  "existing inner path makes array elements untrusted",
+			add:   []string{"github.event.issue"},
+			input: "github.event.issue.labels[0].name",
+			want:  `"github.event.issue"`,
+		},
+		{
+			what:  "ancestor is already untrusted",
+			add:   []string{"github.event.issue", "github.event.issue.foo"},
+			input: "github.event.issue.foo",
+			want:  `"github.event.issue"`,
+		},
+		{
+			what:   "remove builtin input",
+			remove: []string{"github.head_ref"},
+			input:  "github.head_ref",
+		},
+		{
+			what:   "remove all children",
+			remove: []string{"github.event.issue.title", "github.event.issue.body"},
+			input:  "github.event.issue.title",
+		},
+		{
+			what:   "removing all children does not make the parent untrusted",
+			remove: []string{"github.event.issue.title", "github.event.issue.body"},
+			input:  "github.event.issue",
+		},
+		{
+			what:   "remove root",
+			remove: []string{"github"},
+			input:  "github.event.issue.title",
+		},
+		{
+			what:   "remove unknown path",
+			remove: []string{"github.event.foo.bar", "foo"},
+			input:  "github.event.issue.title",
+			want:   `"github.event.issue.title"`,
+		},
+		{
+			what:   "remove added path",
+			add:    []string{"inputs.branch_name"},
+			remove: []string{"inputs.branch_name"},
+			input:  "inputs.branch_name",
+		},
+		{
+			what:  "wildcard in added path matches object property",
+			add:   []string{"github.event.commits.*.foo"},
+			input: "github.event.commits.bar.foo",
+			want:  `"github.event.commits.*.foo"`,
+		},
+		{
+			what:  "wildcard in added path matches array element",
+			add:   []string{"github.event.commits.*.foo"},
+			input: "github.event.commits[0].foo",
+			want:  `"github.event.commits.*.foo"`,
+		},
+		{
+			what:  "builtin wildcard still matches array element after adding path",
+			add:   []string{"github.event.commits.*.foo"},
+			input: "github.event.commits[0].message",
+			want:  `"github.event.commits.*.message"`,
+		},
+		{
+			what:  "builtin wildcard does not match object property after adding path",
+			add:   []string{"github.event.commits.*.foo"},
+			input: "github.event.commits.bar.message",
+		},
+		{
+			what:   "remove wildcard path added to builtin wildcard",
+			add:    []string{"github.event.commits.*.foo"},
+			remove: []string{"github.event.commits.*.foo"},
+			input:  "github.event.commits.bar.foo",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			roots := BuiltinUntrustedInputs.DeepCopy()
+			for _, p := range tc.add {
+				if err := roots.AddPath(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, p := range tc.remove {
+				if err := roots.RemovePath(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c := NewUntrustedInputChecker(roots)
+			testRunTrustedInputsCheckerForNode(t, c, tc.input)
+			errs := c.Errs()
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatalf("wanted no error but got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("1 error was wanted but got %d error(s): %v", len(errs), errs)
+			}
+			if !strings.Contains(errs[0].Error(), tc.want) {
+				t.Fatalf("%q was wanted to be contained in error message %q", tc.want, errs[0].Error())
+			}
+		})
+	}
+
+	// Builtin search roots must not be modified
+	c := NewUntrustedInputChecker(BuiltinUntrustedInputs)
+	testRunTrustedInputsCheckerForNode(t, c, "github.head_ref")
+	if len(c.Errs()) != 1 {
+		t.Fatal("BuiltinUntrustedInputs was modified", c.Errs())
+	}
+	c = NewUntrustedInputChecker(BuiltinUntrustedInputs)
+	testRunTrustedInputsCheckerForNode(t, c, "github.event.commits.foo.message")
+	if len(c.Errs()) != 0 {
+		t.Fatal("builtin wildcard was modified to match object properties", c.Errs())
+	}
+}
+
+func TestExprInsecureInvalidPath(t *testing.T) {
+	for _, p := range []string{"", "github..foo", "github.", "*.foo"} {
+		t.Run(p, func(t *testing.T) {
+			roots := UntrustedInputSearchRoots{}
+			if err := roots.AddPath(p); err == nil {
+				t.Fatal("error did not occur while adding path")
+			}
+			if err := roots.RemovePath(p); err == nil {
+				t.Fatal("error did not occur while removing path")
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ func errorAtToken(t *Token, msg string) *ExprError {
 		Offset:  t.Offset,
 		Line:    t.Line,
 		Column:  t.Column,
+		Code:    "syntax-error",
 	}
 }
 
@@ -392,22 +392,32 @@ func NewExprSemanticsChecker(checkUntrustedInput bool, configVars []string) *Exp
 	return c
 }
 
-func errorAtExpr(e ExprNode, msg string) *ExprError {
+// SetUntrustedInputSearchRoots sets the search roots to detect untrusted inputs. By default,
+// BuiltinUntrustedInputs is used. This method does nothing when the checker was created without
+// checking untrusted inputs.
+func (sema *ExprSemanticsChecker) SetUntrustedInputSearchRoots(roots UntrustedInputSearchRoots) {
+	if sema.untrusted != nil {
+		sema.untrusted = NewUntrustedInputChecker(roots)
+	}
+}
+
+func errorAtExpr(e ExprNode, code, msg string) *ExprError {
 	t := e.Token()
 	return &ExprError{
 		Message: msg,
 		Offset:  t.Offset,
 		Line:    t.Line,
 		Column:  t.Column,
+		Code:    code,
 	}
 }
 
-func errorfAtExpr(e ExprNode, format string, args ...interface{}) *ExprError {
-	return errorAtExpr(e, fmt.Sprintf(format, args...))
+func errorfAtExpr(e ExprNode, code, format string, args ...interface{}) *ExprError {
+	return errorAtExpr(e, code, fmt.Sprintf(format, args...))
 }
 
-func (sema *ExprSemanticsChecker) errorf(e ExprNode, format string, args ...interface{}) {
-	sema.errs = append(sema.errs, errorfAtExpr(e, format, args...))
+func (sema *ExprSemanticsChecker) errorf(e ExprNode, code, format string, args ...interface{}) {
+	sema.errs = append(sema.errs, errorfAtExpr(e, code, format, args...))
 }
 
 func (sema *ExprSemanticsChecker) ensureVarsCopied() {
@@ -539,6 +549,7 @@ func (sema *ExprSemanticsChecker) checkAvailableContext(n *VariableNode) {
 	}
 	sema.errorf(
 		n,
+		"unavailable-context",
 		"context %q is not allowed here. %s. see https://docs.github.com/en/actions/learn-github-actions/contexts#context-availability for more details",
 		n.Name,
 		notes,
@@ -576,6 +587,7 @@ func (sema *ExprSemanticsChecker) checkSpecialFunctionAvailability(n *FuncCallNo
 
 	sema.errorf(
 		n,
+		"unavailable-function",
 		"calling function %q is not allowed here. %q is only available in %s. see https://docs.github.com/en/actions/learn-github-actions/contexts#context-availability for more details",
 		n.Callee,
 		n.Callee,
@@ -602,7 +614,7 @@ func (sema *ExprSemanticsChecker) checkVariable(n *VariableNode) ExprType {
 		for n := range sema.vars {
 			ss = append(ss, n)
 		}
-		sema.errorf(n, "undefined variable %q. available variables are %s", n.Token().Value, sortedQuotes(ss))
+		sema.errorf(n, "undefined-variable", "undefined variable %q. available variables are %s", n.Token().Value, sortedQuotes(ss))
 		return AnyType{}
 	}
 
@@ -625,12 +637,12 @@ func (sema *ExprSemanticsChecker) checkObjectDeref(n *ObjectDerefNode) ExprType
 			return ty.Mapped
 		}
 		if ty.IsStrict() {
-			sema.errorf(n, "property %q is not defined in object type %s", n.Property, ty.String())
+			sema.errorf(n, "undefined-property", "property %q is not defined in object type %s", n.Property, ty.String())
 		}
 		return AnyType{}
 	case *ArrayType:
 		if !ty.Deref {
-			sema.errorf(n, "receiver of object dereference %q must be type of object but got %q", n.Property, ty.String())
+			sema.errorf(n, "type-mismatch", "receiver of object dereference %q must be type of object but got %q", n.Property, ty.String())
 			return AnyType{}
 		}
 		switch et := ty.Elem.(type) {
@@ -645,12 +657,13 @@ func (sema *ExprSemanticsChecker) checkObjectDeref(n *ObjectDerefNode) ExprType
 			} else if et.Mapped != nil {
 				elem = et.Mapped
 			} else if et.IsStrict() {
-				sema.errorf(n, "property %q is not defined in object type %s as element of filtered array", n.Property, et.String())
+				sema.errorf(n, "undefined-property", "property %q is not defined in object type %s as element of filtered array", n.Property, et.String())
 			}
 			return &ArrayType{elem, true}
 		default:
 			sema.errorf(
 				n,
+				"type-mismatch",
 				"property filtered by %q at object filtering must be type of object but got %q",
 				n.Property,
 				ty.Elem.String(),
@@ -658,7 +671,7 @@ func (sema *ExprSemanticsChecker) checkObjectDeref(n *ObjectDerefNode) ExprType
 			return AnyType{}
 		}
 	default:
-		sema.errorf(n, "receiver of object dereference %q must be type of object but got %q", n.Property, ty.String())
+		sema.errorf(n, "type-mismatch", "receiver of object dereference %q must be type of object but got %q", n.Property, ty.String())
 		return AnyType{}
 	}
 }
@@ -668,6 +681,7 @@ func (sema *ExprSemanticsChecker) checkConfigVariables(n *ObjectDerefNode) {
 	if strings.HasPrefix(n.Property, "github_") {
 		sema.errorf(
 			n,
+			"invalid-config-variable",
 			"configuration variable name %q must not start with the GITHUB_ prefix (case insensitive). note: see the convention at https://docs.github.com/en/actions/learn-github-actions/variables#naming-conventions-for-configuration-variables",
 			n.Property,
 		)
@@ -681,6 +695,7 @@ func (sema *ExprSemanticsChecker) checkConfigVariables(n *ObjectDerefNode) {
 		}
 		sema.errorf(
 			n,
+			"invalid-config-variable",
 			"configuration variable name %q can only contain alphabets, decimal numbers, and '_'. note: see the convention at https://docs.github.com/en/actions/learn-github-actions/variables#naming-conventions-for-configuration-variables",
 			n.Property,
 		)
@@ -693,6 +708,7 @@ func (sema *ExprSemanticsChecker) checkConfigVariables(n *ObjectDerefNode) {
 	if len(sema.configVars) == 0 {
 		sema.errorf(
 			n,
+			"undefined-config-variable",
 			"no configuration variable is allowed since the variables list is empty in actionlint.yaml. you may forget adding the variable %q to the list",
 			n.Property,
 		)
@@ -707,6 +723,7 @@ func (sema *ExprSemanticsChecker) checkConfigVariables(n *ObjectDerefNode) {
 
 	sema.errorf(
 		n,
+		"undefined-config-variable",
 		"undefined configuration variable %q. defined configuration variables in actionlint.yaml are %s",
 		n.Property,
 		sortedQuotes(sema.configVars),
@@ -731,7 +748,7 @@ func (sema *ExprSemanticsChecker) checkArrayDeref(n *ArrayDerefNode) ExprType {
 			case *ObjectType:
 				return &ArrayType{mty, true}
 			default:
-				sema.errorf(n, "elements of object at receiver of object filtering `.*` must be type of object but got %q. the type of receiver was %q", mty.String(), ty.String())
+				sema.errorf(n, "type-mismatch", "elements of object at receiver of object filtering `.*` must be type of object but got %q. the type of receiver was %q", mty.String(), ty.String())
 				return AnyType{}
 			}
 		}
@@ -745,13 +762,13 @@ func (sema *ExprSemanticsChecker) checkArrayDeref(n *ArrayDerefNode) ExprType {
 			}
 		}
 		if !found {
-			sema.errorf(n, "object type %q cannot be filtered by object filtering `.*` since it has no object element", ty.String())
+			sema.errorf(n, "type-mismatch", "object type %q cannot be filtered by object filtering `.*` since it has no object element", ty.String())
 			return AnyType{}
 		}
 
 		return &ArrayType{AnyType{}, true}
 	default:
-		sema.errorf(n, "receiver of object filtering `.*` must be type of array or object but got %q", ty.String())
+		sema.errorf(n, "type-mismatch", "receiver of object filtering `.*` must be type of array or object but got %q", ty.String())
 		return AnyType{}
 	}
 }
@@ -771,7 +788,7 @@ func (sema *ExprSemanticsChecker) checkIndexAccess(n *IndexAccessNode) ExprType
 		case AnyType, NumberType:
 			return ty.Elem
 		default:
-			sema.errorf(n.Index, "index access of array must be type of number but got %q", idx.String())
+			sema.errorf(n.Index, "type-mismatch", "index access of array must be type of number but got %q", idx.String())
 			return AnyType{}
 		}
 	case *ObjectType:
@@ -788,7 +805,7 @@ func (sema *ExprSemanticsChecker) checkIndexAccess(n *IndexAccessNode) ExprType
 					return ty.Mapped
 				}
 				if ty.IsStrict() {
-					sema.errorf(n, "property %q is not defined in object type %s", lit.Value, ty.String())
+					sema.errorf(n, "undefined-property", "property %q is not defined in object type %s", lit.Value, ty.String())
 				}
 			}
 			if ty.Mapped != nil {
@@ -796,11 +813,11 @@ func (sema *ExprSemanticsChecker) checkIndexAccess(n *IndexAccessNode) ExprType
 			}
 			return AnyType{} // Fallback
 		default:
-			sema.errorf(n.Index, "property access of object must be type of string but got %q", idx.String())
+			sema.errorf(n.Index, "type-mismatch", "property access of object must be type of string but got %q", idx.String())
 			return AnyType{}
 		}
 	default:
-		sema.errorf(n, "index access operand must be type of object or array but got %q", ty.String())
+		sema.errorf(n, "type-mismatch", "index access operand must be type of object or array but got %q", ty.String())
 		return AnyType{}
 	}
 }
@@ -814,6 +831,7 @@ func checkFuncSignature(n *FuncCallNode, sig *FuncSignature, args []ExprType) *E
 		}
 		return errorfAtExpr(
 			n,
+			"wrong-argument-count",
 			"number of arguments is wrong. function %q takes %s%d parameters but %d arguments are given",
 			sig.String(),
 			atLeast,
@@ -827,6 +845,7 @@ func checkFuncSignature(n *FuncCallNode, sig *FuncSignature, args []ExprType) *E
 		if !p.Assignable(a) {
 			return errorfAtExpr(
 				n.Args[i],
+				"type-mismatch",
 				"%s argument of function call is not assignable. %q cannot be assigned to %q. called function type is %q",
 				ordinal(i+1),
 				a.String(),
@@ -845,6 +864,7 @@ func checkFuncSignature(n *FuncCallNode, sig *FuncSignature, args []ExprType) *E
 			if !p.Assignable(a) {
 				return errorfAtExpr(
 					n.Args[lp+i],
+					"type-mismatch",
 					"%s argument of function call is not assignable. %q cannot be assigned to %q. called function type is %q",
 					ordinal(lp+i+1),
 					a.String(),
@@ -874,14 +894,14 @@ func (sema *ExprSemanticsChecker) checkBuiltinFuncCall(n *FuncCallNode, sig *Fun
 
 		for i := 0; i < l; i++ {
 			if _, ok := holders[i]; !ok {
-				sema.errorf(n, "format string %q does not contain placeholder {%d}. remove argument which is unused in the format string", lit.Value, i)
+				sema.errorf(n, "invalid-format-call", "format string %q does not contain placeholder {%d}. remove argument which is unused in the format string", lit.Value, i)
 				continue
 			}
 			delete(holders, i) // forget it to check unused placeholders
 		}
 
 		for i := range holders {
-			sema.errorf(n, "format string %q contains placeholder {%d} but only %d arguments are given to format", lit.Value, i, l)
+			sema.errorf(n, "invalid-format-call", "format string %q contains placeholder {%d} but only %d arguments are given to format", lit.Value, i, l)
 		}
 	case "fromjson":
 		lit, ok := n.Args[0].(*StringNode)
@@ -894,11 +914,11 @@ func (sema *ExprSemanticsChecker) checkBuiltinFuncCall(n *FuncCallNode, sig *Fun
 			return typeOfJSONValue(v)
 		}
 		if s, ok := err.(*json.SyntaxError); ok {
-			sema.errorf(lit, "broken JSON string is passed to fromJSON() at offset %d: %s", s.Offset, s)
+			sema.errorf(lit, "invalid-json", "broken JSON string is passed to fromJSON() at offset %d: %s", s.Offset, s)
 		}
 	case "case":
 		if len(n.Args)%2 == 0 {
-			sema.errorf(n, "case() requires an odd number of arguments (pred/value pairs + default) but got %d", len(n.Args))
+			sema.errorf(n, "wrong-argument-count", "case() requires an odd number of arguments (pred/value pairs + default) but got %d", len(n.Args))
 		}
 	}
 
@@ -914,7 +934,7 @@ func (sema *ExprSemanticsChecker) checkFuncCall(n *FuncCallNode) ExprType {
 		for n := range sema.funcs {
 			ss = append(ss, n)
 		}
-		sema.errorf(n, "undefined function %q. available functions are %s", n.Callee, sortedQuotes(ss))
+		sema.errorf(n, "undefined-function", "undefined function %q. available functions are %s", n.Callee, sortedQuotes(ss))
 		return AnyType{}
 	}
 
@@ -943,7 +963,7 @@ func (sema *ExprSemanticsChecker) checkFuncCall(n *FuncCallNode) ExprType {
 func (sema *ExprSemanticsChecker) checkNotOp(n *NotOpNode) ExprType {
 	ty := sema.check(n.Operand)
 	if !(BoolType{}).Assignable(ty) {
-		sema.errorf(n, "type of operand of ! operator %q is not assignable to type \"bool\"", ty.String())
+		sema.errorf(n, "type-mismatch", "type of operand of ! operator %q is not assignable to type \"bool\"", ty.String())
 	}
 	return BoolType{}
 }
@@ -1007,7 +1027,7 @@ func (sema *ExprSemanticsChecker) checkCompareOp(n *CompareOpNode) ExprType {
 	r := sema.check(n.Right)
 
 	if !validateCompareOpOperands(n.Kind, l, r) {
-		sema.errorf(n, "%q value cannot be compared to %q value with %q operator", l.String(), r.String(), n.Kind.String())
+		sema.errorf(n, "invalid-comparison", "%q value cannot be compared to %q value with %q operator", l.String(), r.String(), n.Kind.String())
 	}
 
 	return BoolType{}
@@ -0,0 +1,117 @@
+package actionlint
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileResult is a result of linting one file. It is passed to Formatter to format the errors.
+type FileResult struct {
+	// Path is a file path of the linted file. It is the same as Filepath field of the errors.
+	Path string
+	// Source is the content of the linted file. It may be nil when the source is not available.
+	Source []byte
+	// Errors is a list of errors found in the file. It is empty when no error was found.
+	Errors []*Error
+}
+
+// Formatter is an interface to format results of linting files. ErrorFormatter and built-in
+// formatters like JUnitFormatter implement this interface.
+type Formatter interface {
+	// RegisterRule registers the rule which was used for checking files. This method may be called
+	// multiple times in parallel.
+	RegisterRule(r Rule)
+	// PrintResults formats the results of linting files and prints them with the writer. The
+	// results contain files which have no error.
+	PrintResults(out io.Writer, results []*FileResult) error
+}
+
+// builtinFormatters is a mapping from built-in format names to their factory functions.
+var builtinFormatters = map[string]func() Formatter{
+	"checkstyle": func() Formatter { return NewCheckstyleFormatter() },
+	"github":     func() Formatter { return NewGitHubFormatter() },
+	"gitlab":     func() Formatter { return NewGitLabFormatter() },
+	"junit":      func() Formatter { return NewJUnitFormatter() },
+	"rdjson":     func() Formatter { return NewRDJSONFormatter() },
+	"rdjsonl":    func() Formatter { return NewRDJSONLFormatter() },
+}
+
+// BuiltinFormatNames returns names of all built-in formats which can be specified to -format
+// option instead of a template. The returned names are sorted.
+func BuiltinFormatNames() []string {
+	ns := make([]string, 0, len(builtinFormatters))
+	for n := range builtinFormatters {
+		ns = append(ns, n)
+	}
+	sort.Strings(ns)
+	return ns
+}
+
+// NewFormatter creates a new Formatter instance from the format. When the format is a name of
+// built-in format like "junit", the built-in formatter is returned. Otherwise the format is
+// treated as a Go template and ErrorFormatter is returned.
+func NewFormatter(format string) (Formatter, error) {
+	if f, ok := builtinFormatters[format]; ok {
+		return f(), nil
+	}
+	if !strings.Contains(format, "{{") {
+		return nil, fmt.Errorf("%q is not a built-in format name nor a template. built-in formats are %s. template to format error messages must contain at least one {{ }} placeholder", format, quotes(BuiltinFormatNames()))
+	}
+	f, err := NewErrorFormatter(format)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// formatterRules is a set of rules registered to a formatter. It is thread-safe.
+type formatterRules struct {
+	mu    sync.Mutex
+	rules map[string]*ruleTemplateFields
+}
+
+func newFormatterRules() *formatterRules {
+	return &formatterRules{
+		rules: map[string]*ruleTemplateFields{
+			"syntax-check": {"syntax-check", "Checks for GitHub Actions workflow syntax"},
+		},
+	}
+}
+
+func (rs *formatterRules) register(r Rule) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	n := r.Name()
+	if _, ok := rs.rules[n]; !ok {
+		rs.rules[n] = &ruleTemplateFields{n, r.Description()}
+	}
+}
+
+// sorted returns all registered rules sorted by their names. Error kinds which are not registered
+// are also included.
+func (rs *formatterRules) sorted(results []*FileResult) []*ruleTemplateFields {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	ret := make([]*ruleTemplateFields, 0, len(rs.rules))
+	for _, r := range rs.rules {
+		ret = append(ret, r)
+	}
+	for _, res := range results {
+		for _, err := range res.Errors {
+			if _, ok := rs.rules[err.Kind]; ok {
+				continue
+			}
+			if !slices.ContainsFunc(ret, func(r *ruleTemplateFields) bool { return r.Name == err.Kind }) {
+				ret = append(ret, &ruleTemplateFields{err.Kind, ""})
+			}
+		}
+	}
+	slices.SortFunc(ret, compareRuleTemplateByName)
+	return ret
+}
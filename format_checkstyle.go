@@ -0,0 +1,77 @@
+package actionlint
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+type checkstyleFile struct {
+	Name   string             `xml:"name,attr"`
+	Errors []*checkstyleError `xml:"error"`
+}
+
+type checkstyleResult struct {
+	XMLName xml.Name          `xml:"checkstyle"`
+	Version string            `xml:"version,attr"`
+	Files   []*checkstyleFile `xml:"file"`
+}
+
+// CheckstyleFormatter is a formatter to output results in Checkstyle XML format. Each workflow file
+// is reported as one <file> element including files which have no error. It is used with
+// `-format checkstyle`.
+// https://checkstyle.sourceforge.io/
+type CheckstyleFormatter struct{}
+
+// NewCheckstyleFormatter creates a new CheckstyleFormatter instance.
+func NewCheckstyleFormatter() *CheckstyleFormatter {
+	return &CheckstyleFormatter{}
+}
+
+// RegisterRule does nothing since Checkstyle XML format does not contain rule descriptions.
+func (f *CheckstyleFormatter) RegisterRule(r Rule) {}
+
+// PrintResults prints the results in Checkstyle XML format with the writer.
+func (f *CheckstyleFormatter) PrintResults(out io.Writer, results []*FileResult) error {
+	root := &checkstyleResult{
+		Version: "4.3",
+		Files:   make([]*checkstyleFile, 0, len(results)),
+	}
+	for _, res := range results {
+		file := &checkstyleFile{
+			Name:   res.Path,
+			Errors: make([]*checkstyleError, 0, len(res.Errors)),
+		}
+		for _, err := range res.Errors {
+			file.Errors = append(file.Errors, &checkstyleError{
+				Line:     err.Line,
+				Column:   err.Column,
+				Severity: "error",
+				Message:  err.Message,
+				Source:   "actionlint." + err.Kind,
+			})
+		}
+		root.Files = append(root.Files, file)
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return fmt.Errorf("could not write Checkstyle XML header: %w", err)
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return fmt.Errorf("could not encode results into Checkstyle XML: %w", err)
+	}
+	if _, err := io.WriteString(out, "\n"); err != nil {
+		return fmt.Errorf("could not write Checkstyle XML: %w", err)
+	}
+	return nil
+}
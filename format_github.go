@@ -0,0 +1,111 @@
+package actionlint
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Escape rules of workflow commands. Values of properties also need to escape ':' and ','.
+// https://github.com/actions/toolkit/blob/main/packages/core/src/command.ts
+var (
+	githubCommandDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubCommandPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+	githubSummaryCellEscaper     = strings.NewReplacer("|", "\\|", "\r", "", "\n", " ")
+)
+
+// GitHubFormatter is a formatter to output errors as error annotations with workflow commands on
+// GitHub Actions. When $GITHUB_STEP_SUMMARY environment variable is set, a Markdown table of the
+// errors is also appended to the job summary file. It is used with `-format github`.
+// https://docs.github.com/en/actions/reference/workflows-and-actions/workflow-commands#setting-an-error-message
+type GitHubFormatter struct {
+	// summary is a file path of the job summary. When it is empty, no summary is written.
+	summary string
+}
+
+// NewGitHubFormatter creates a new GitHubFormatter instance. The path of job summary file is read
+// from $GITHUB_STEP_SUMMARY environment variable.
+func NewGitHubFormatter() *GitHubFormatter {
+	return &GitHubFormatter{os.Getenv("GITHUB_STEP_SUMMARY")}
+}
+
+// RegisterRule does nothing since workflow commands do not contain rule descriptions.
+func (f *GitHubFormatter) RegisterRule(r Rule) {}
+
+// PrintResults prints the errors as workflow commands with the writer and writes the job summary.
+func (f *GitHubFormatter) PrintResults(out io.Writer, results []*FileResult) error {
+	for _, res := range results {
+		for _, err := range res.Errors {
+			t := err.GetTemplateFields(res.Source)
+			if _, err := fmt.Fprintf(
+				out,
+				"::error file=%s,line=%d,endLine=%d,col=%d,endColumn=%d,title=%s::%s\n",
+				githubCommandPropertyEscaper.Replace(t.Filepath),
+				t.Line,
+				t.EndLine,
+				t.Column,
+				t.EndColumn,
+				githubCommandPropertyEscaper.Replace(t.Kind),
+				githubCommandDataEscaper.Replace(t.Message),
+			); err != nil {
+				return fmt.Errorf("could not write workflow command: %w", err)
+			}
+		}
+	}
+
+	if f.summary == "" {
+		return nil
+	}
+
+	// Job summary file is shared by all steps in the job. Append the summary to the file
+	s, err := os.OpenFile(f.summary, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("could not open job summary file %q: %w", f.summary, err)
+	}
+	defer s.Close()
+	if err := writeGitHubSummary(s, results); err != nil {
+		return fmt.Errorf("could not write job summary to %q: %w", f.summary, err)
+	}
+	return nil
+}
+
+func writeGitHubSummary(out io.Writer, results []*FileResult) error {
+	var b strings.Builder
+
+	n, files := 0, 0
+	for _, res := range results {
+		n += len(res.Errors)
+		if len(res.Errors) > 0 {
+			files++
+		}
+	}
+
+	b.WriteString("## actionlint\n\n")
+	if n == 0 {
+		fmt.Fprintf(&b, "No problem was found in %d files.\n\n", len(results))
+		_, err := io.WriteString(out, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "Found %d errors in %d files.\n\n", n, files)
+	b.WriteString("| File | Line | Column | Rule | Message |\n")
+	b.WriteString("|------|------|--------|------|---------|\n")
+	for _, res := range results {
+		for _, err := range res.Errors {
+			fmt.Fprintf(
+				&b,
+				"| `%s` | %d | %d | `%s` | %s |\n",
+				githubSummaryCellEscaper.Replace(err.Filepath),
+				err.Line,
+				err.Column,
+				err.Kind,
+				githubSummaryCellEscaper.Replace(err.Message),
+			)
+		}
+	}
+	b.WriteByte('\n')
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
@@ -0,0 +1,87 @@
+package actionlint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type gitLabCodeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+type gitLabCodeQualityLocation struct {
+	Path  string                 `json:"path"`
+	Lines gitLabCodeQualityLines `json:"lines"`
+}
+
+type gitLabCodeQualityIssue struct {
+	Description string                    `json:"description"`
+	CheckName   string                    `json:"check_name"`
+	Fingerprint string                    `json:"fingerprint"`
+	Severity    string                    `json:"severity"`
+	Location    gitLabCodeQualityLocation `json:"location"`
+}
+
+// GitLabFormatter is a formatter to output results in GitLab Code Quality report format. It is
+// used with `-format gitlab`.
+// https://docs.gitlab.com/ci/testing/code_quality/#code-quality-report-format
+type GitLabFormatter struct{}
+
+// NewGitLabFormatter creates a new GitLabFormatter instance.
+func NewGitLabFormatter() *GitLabFormatter {
+	return &GitLabFormatter{}
+}
+
+// RegisterRule does nothing since GitLab Code Quality report does not contain rule descriptions.
+func (f *GitLabFormatter) RegisterRule(r Rule) {}
+
+// PrintResults prints the results in GitLab Code Quality report format with the writer.
+func (f *GitLabFormatter) PrintResults(out io.Writer, results []*FileResult) error {
+	issues := []*gitLabCodeQualityIssue{}
+	for _, res := range results {
+		seen := map[string]int{}
+		for _, err := range res.Errors {
+			fp := gitLabFingerprint(res.Path, err.Kind, err.Message)
+			// Fingerprint must be unique in the report. When the same error is reported multiple
+			// times in the file, distinguish them by the number of occurrences
+			if n := seen[fp]; n > 0 {
+				seen[fp]++
+				fp = gitLabFingerprint(res.Path, err.Kind, err.Message, strconv.Itoa(n))
+			} else {
+				seen[fp] = 1
+			}
+			issues = append(issues, &gitLabCodeQualityIssue{
+				Description: err.Message,
+				CheckName:   err.Kind,
+				Fingerprint: fp,
+				Severity:    "major",
+				Location: gitLabCodeQualityLocation{
+					Path:  res.Path,
+					Lines: gitLabCodeQualityLines{err.Line},
+				},
+			})
+		}
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(issues); err != nil {
+		return fmt.Errorf("could not encode results into GitLab Code Quality report: %w", err)
+	}
+	return nil
+}
+
+// gitLabFingerprint calculates a fingerprint of the error. The fingerprint does not depend on the
+// position of the error so that it is stable while lines are added or removed in the file.
+func gitLabFingerprint(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,131 @@
+package actionlint
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+// JUnitFormatter is a formatter to output results in JUnit XML format. Each workflow file is
+// reported as one test suite and each rule is reported as one test case in the suite. Errors found
+// by the rule are reported as a failure of the test case. Files which have no error are reported
+// as passed test suites. It is used with `-format junit`.
+// https://github.com/testmoapp/junitxml
+type JUnitFormatter struct {
+	rules *formatterRules
+}
+
+// NewJUnitFormatter creates a new JUnitFormatter instance.
+func NewJUnitFormatter() *JUnitFormatter {
+	return &JUnitFormatter{newFormatterRules()}
+}
+
+// RegisterRule registers the rule. Each registered rule is reported as a test case of each test
+// suite. This method can be called multiple times safely in parallel.
+func (f *JUnitFormatter) RegisterRule(r Rule) {
+	f.rules.register(r)
+}
+
+// PrintResults prints the results in JUnit XML format with the writer.
+func (f *JUnitFormatter) PrintResults(out io.Writer, results []*FileResult) error {
+	rules := f.rules.sorted(results)
+	root := &junitTestSuites{
+		Name:   "actionlint",
+		Suites: make([]*junitTestSuite, 0, len(results)),
+	}
+
+	for _, res := range results {
+		suite := &junitTestSuite{
+			Name:      res.Path,
+			Tests:     len(rules),
+			TestCases: make([]*junitTestCase, 0, len(rules)),
+		}
+		for _, r := range rules {
+			tc := &junitTestCase{
+				Name:      r.Name,
+				ClassName: res.Path,
+			}
+			var errs []*Error
+			for _, err := range res.Errors {
+				if err.Kind == r.Name {
+					errs = append(errs, err)
+				}
+			}
+			if len(errs) > 0 {
+				tc.File = res.Path
+				tc.Line = errs[0].Line
+				tc.Failure = junitFailureOf(errs, res.Source)
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Suites = append(root.Suites, suite)
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return fmt.Errorf("could not write JUnit XML header: %w", err)
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return fmt.Errorf("could not encode results into JUnit XML: %w", err)
+	}
+	if _, err := io.WriteString(out, "\n"); err != nil {
+		return fmt.Errorf("could not write JUnit XML: %w", err)
+	}
+	return nil
+}
+
+func junitFailureOf(errs []*Error, src []byte) *junitFailure {
+	var b strings.Builder
+	for _, err := range errs {
+		t := err.GetTemplateFields(src)
+		fmt.Fprintf(&b, "%s:%d:%d: %s\n", t.Filepath, t.Line, t.Column, t.Message)
+		if t.Snippet != "" {
+			b.WriteString(t.Snippet)
+			b.WriteByte('\n')
+		}
+	}
+
+	msg := errs[0].Message
+	if len(errs) > 1 {
+		msg = fmt.Sprintf("%d errors found by %q rule", len(errs), errs[0].Kind)
+	}
+	return &junitFailure{
+		Message: msg,
+		Type:    errs[0].Kind,
+		Text:    b.String(),
+	}
+}
@@ -0,0 +1,136 @@
+package actionlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type rdjsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// rdjsonRange is a range in the source. Note that the end position is exclusive.
+type rdjsonRange struct {
+	Start rdjsonPosition `json:"start"`
+	End   rdjsonPosition `json:"end"`
+}
+
+type rdjsonLocation struct {
+	Path  string      `json:"path"`
+	Range rdjsonRange `json:"range"`
+}
+
+type rdjsonCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+type rdjsonSuggestion struct {
+	Range rdjsonRange `json:"range"`
+	Text  string      `json:"text"`
+}
+
+type rdjsonSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type rdjsonDiagnostic struct {
+	Message        string              `json:"message"`
+	Location       rdjsonLocation      `json:"location"`
+	Severity       string              `json:"severity"`
+	Source         *rdjsonSource       `json:"source,omitempty"`
+	Code           rdjsonCode          `json:"code"`
+	Suggestions    []*rdjsonSuggestion `json:"suggestions,omitempty"`
+	OriginalOutput string              `json:"original_output"`
+}
+
+type rdjsonResult struct {
+	Source      rdjsonSource        `json:"source"`
+	Severity    string              `json:"severity"`
+	Diagnostics []*rdjsonDiagnostic `json:"diagnostics"`
+}
+
+var rdjsonActionlintSource = rdjsonSource{
+	Name: "actionlint",
+	URL:  "https://github.com/rhysd/actionlint",
+}
+
+// RDJSONFormatter is a formatter to output results in Reviewdog Diagnostic Format. When lines is
+// false, it outputs one JSON object in rdjson format. When lines is true, it outputs one diagnostic
+// per line in rdjsonl format. Suggestions attached to errors are also output so that reviewers can
+// apply the fixes. It is used with `-format rdjson` or `-format rdjsonl`.
+// https://github.com/reviewdog/reviewdog/tree/master/proto/rdf
+type RDJSONFormatter struct {
+	lines bool
+}
+
+// NewRDJSONFormatter creates a new RDJSONFormatter instance for rdjson format.
+func NewRDJSONFormatter() *RDJSONFormatter {
+	return &RDJSONFormatter{false}
+}
+
+// NewRDJSONLFormatter creates a new RDJSONFormatter instance for rdjsonl format.
+func NewRDJSONLFormatter() *RDJSONFormatter {
+	return &RDJSONFormatter{true}
+}
+
+// RegisterRule does nothing since Reviewdog Diagnostic Format does not contain rule descriptions.
+func (f *RDJSONFormatter) RegisterRule(r Rule) {}
+
+// PrintResults prints the results in rdjson or rdjsonl format with the writer.
+func (f *RDJSONFormatter) PrintResults(out io.Writer, results []*FileResult) error {
+	ds := []*rdjsonDiagnostic{}
+	for _, res := range results {
+		for _, err := range res.Errors {
+			t := err.GetTemplateFields(res.Source)
+			d := &rdjsonDiagnostic{
+				Message: t.Message,
+				Location: rdjsonLocation{
+					Path: t.Filepath,
+					Range: rdjsonRange{
+						Start: rdjsonPosition{t.Line, t.Column},
+						End:   rdjsonPosition{t.EndLine, t.EndColumn + 1},
+					},
+				},
+				Severity:       "ERROR",
+				Code:           rdjsonCode{t.Code, t.URL},
+				OriginalOutput: err.Error(),
+			}
+			for _, s := range err.Suggestions {
+				d.Suggestions = append(d.Suggestions, &rdjsonSuggestion{
+					Range: rdjsonRange{
+						Start: rdjsonPosition{s.Line, s.Column},
+						End:   rdjsonPosition{s.EndLine, s.EndColumn + 1},
+					},
+					Text: s.Text,
+				})
+			}
+			ds = append(ds, d)
+		}
+	}
+
+	enc := json.NewEncoder(out)
+	if f.lines {
+		for _, d := range ds {
+			d.Source = &rdjsonActionlintSource
+			if err := enc.Encode(d); err != nil {
+				return fmt.Errorf("could not encode error into rdjsonl: %w", err)
+			}
+		}
+		return nil
+	}
+
+	enc.SetIndent("", "  ")
+	r := &rdjsonResult{
+		Source:      rdjsonActionlintSource,
+		Severity:    "ERROR",
+		Diagnostics: ds,
+	}
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("could not encode results into rdjson: %w", err)
+	}
+	return nil
+}
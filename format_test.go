@@ -0,0 +1,383 @@
+package actionlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatNewFormatter(t *testing.T) {
+	f, err := NewFormatter("junit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.(*JUnitFormatter); !ok {
+		t.Fatalf("JUnit formatter was not created: %T", f)
+	}
+
+	f, err = NewFormatter("{{json .}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.(*ErrorFormatter); !ok {
+		t.Fatalf("template formatter was not created: %T", f)
+	}
+}
+
+func TestFormatNewFormatterError(t *testing.T) {
+	testCases := []struct {
+		format string
+		want   string
+	}{
+		{"junitxml", "\"junitxml\" is not a built-in format name nor a template. built-in formats are \"checkstyle\", \"github\", \"gitlab\", \"junit\", \"rdjson\", \"rdjsonl\""},
+		{"{{xxx", "template \"{{xxx\" to format error messages could not be parsed"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			_, err := NewFormatter(tc.format)
+			if err == nil {
+				t.Fatal("error did not occur")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("%q is not contained in error message %q", tc.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestFormatJUnitCleanFiles(t *testing.T) {
+	f := NewJUnitFormatter()
+	f.RegisterRule(&RuleBase{name: "rule1", desc: "description for rule1"})
+	f.RegisterRule(&RuleBase{name: "rule2", desc: "description for rule2"})
+
+	results := []*FileResult{
+		{
+			Path:   "clean.yaml",
+			Source: []byte("on: push\n"),
+		},
+		{
+			Path:   "dirty.yaml",
+			Source: []byte("on: push\njobs:\n"),
+			Errors: []*Error{
+				{Message: "error 1", Filepath: "dirty.yaml", Line: 2, Column: 1, Kind: "rule2"},
+				{Message: "error 2", Filepath: "dirty.yaml", Line: 1, Column: 1, Kind: "unknown-rule"},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := f.PrintResults(&b, results); err != nil {
+		t.Fatal(err)
+	}
+
+	var have junitTestSuites
+	if err := xml.Unmarshal(b.Bytes(), &have); err != nil {
+		t.Fatalf("output is not a valid XML: %v: %s", err, b.String())
+	}
+
+	if have.Tests != 8 || have.Failures != 2 {
+		t.Fatalf("wanted 8 tests and 2 failures but got %d tests and %d failures: %s", have.Tests, have.Failures, b.String())
+	}
+	if len(have.Suites) != 2 {
+		t.Fatalf("wanted 2 test suites but got %d: %s", len(have.Suites), b.String())
+	}
+
+	clean := have.Suites[0]
+	if clean.Name != "clean.yaml" || clean.Tests != 4 || clean.Failures != 0 {
+		t.Fatalf("unexpected test suite for clean file: %#v", clean)
+	}
+	for _, tc := range clean.TestCases {
+		if tc.Failure != nil {
+			t.Errorf("test case %q for clean file failed: %#v", tc.Name, tc.Failure)
+		}
+	}
+
+	dirty := have.Suites[1]
+	if dirty.Name != "dirty.yaml" || dirty.Tests != 4 || dirty.Failures != 2 {
+		t.Fatalf("unexpected test suite for file with errors: %#v", dirty)
+	}
+	names := []string{}
+	for _, tc := range dirty.TestCases {
+		names = append(names, tc.Name)
+	}
+	if strings.Join(names, ",") != "rule1,rule2,syntax-check,unknown-rule" {
+		t.Fatalf("unexpected test cases: %v", names)
+	}
+	for i, want := range []string{"", "error 1", "", "error 2"} {
+		tc := dirty.TestCases[i]
+		if want == "" {
+			if tc.Failure != nil {
+				t.Errorf("test case %q should pass but failed: %#v", tc.Name, tc.Failure)
+			}
+			continue
+		}
+		if tc.Failure == nil {
+			t.Errorf("test case %q should fail but passed", tc.Name)
+			continue
+		}
+		if tc.Failure.Message != want || tc.Failure.Type != tc.Name {
+			t.Errorf("unexpected failure for test case %q: %#v", tc.Name, tc.Failure)
+		}
+		if !strings.Contains(tc.Failure.Text, "dirty.yaml:") {
+			t.Errorf("failure text of test case %q does not contain location: %q", tc.Name, tc.Failure.Text)
+		}
+	}
+}
+
+func TestFormatCheckstyleCleanFiles(t *testing.T) {
+	results := []*FileResult{
+		{Path: "clean.yaml"},
+		{
+			Path: "dirty.yaml",
+			Errors: []*Error{
+				{Message: "error 1", Filepath: "dirty.yaml", Line: 2, Column: 3, Kind: "rule1"},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := NewCheckstyleFormatter().PrintResults(&b, results); err != nil {
+		t.Fatal(err)
+	}
+
+	var have checkstyleResult
+	if err := xml.Unmarshal(b.Bytes(), &have); err != nil {
+		t.Fatalf("output is not a valid XML: %v: %s", err, b.String())
+	}
+	if len(have.Files) != 2 {
+		t.Fatalf("wanted 2 files but got %d: %s", len(have.Files), b.String())
+	}
+	if f := have.Files[0]; f.Name != "clean.yaml" || len(f.Errors) != 0 {
+		t.Fatalf("unexpected file element for clean file: %s", b.String())
+	}
+	f := have.Files[1]
+	if f.Name != "dirty.yaml" || len(f.Errors) != 1 {
+		t.Fatalf("unexpected file element for file with errors: %s", b.String())
+	}
+	want := checkstyleError{Line: 2, Column: 3, Severity: "error", Message: "error 1", Source: "actionlint.rule1"}
+	if *f.Errors[0] != want {
+		t.Fatalf("wanted %#v but got %#v", want, f.Errors[0])
+	}
+}
+
+func TestFormatGitLabFingerprint(t *testing.T) {
+	results := []*FileResult{
+		{
+			Path: "a.yaml",
+			Errors: []*Error{
+				{Message: "error 1", Filepath: "a.yaml", Line: 1, Column: 1, Kind: "rule1"},
+				{Message: "error 1", Filepath: "a.yaml", Line: 5, Column: 1, Kind: "rule1"},
+				{Message: "error 1", Filepath: "a.yaml", Line: 9, Column: 1, Kind: "rule1"},
+				{Message: "error 1", Filepath: "a.yaml", Line: 1, Column: 1, Kind: "rule2"},
+			},
+		},
+		{
+			Path: "b.yaml",
+			Errors: []*Error{
+				{Message: "error 1", Filepath: "b.yaml", Line: 1, Column: 1, Kind: "rule1"},
+			},
+		},
+	}
+
+	format := func(rs []*FileResult) []*gitLabCodeQualityIssue {
+		var b bytes.Buffer
+		if err := NewGitLabFormatter().PrintResults(&b, rs); err != nil {
+			t.Fatal(err)
+		}
+		var issues []*gitLabCodeQualityIssue
+		if err := json.Unmarshal(b.Bytes(), &issues); err != nil {
+			t.Fatalf("output is not a valid JSON: %v: %s", err, b.String())
+		}
+		return issues
+	}
+
+	issues := format(results)
+	if len(issues) != 5 {
+		t.Fatalf("wanted 5 issues but got %d", len(issues))
+	}
+	seen := map[string]int{}
+	for i, is := range issues {
+		if j, ok := seen[is.Fingerprint]; ok {
+			t.Errorf("fingerprint of issue %d is the same as issue %d: %s", i, j, is.Fingerprint)
+		}
+		seen[is.Fingerprint] = i
+	}
+
+	// Fingerprint does not change when the error is moved to another line
+	results[0].Errors[0].Line = 3
+	results[1].Errors[0].Line = 10
+	for i, is := range format(results) {
+		if is.Fingerprint != issues[i].Fingerprint {
+			t.Errorf("fingerprint of issue %d changed: %s vs %s", i, issues[i].Fingerprint, is.Fingerprint)
+		}
+	}
+
+	// Empty results are formatted as an empty array
+	if issues := format(nil); len(issues) != 0 {
+		t.Fatalf("wanted no issue but got %#v", issues)
+	}
+}
+
+func TestFormatGitHubEscape(t *testing.T) {
+	results := []*FileResult{
+		{
+			Path: "a,b:c.yaml",
+			Errors: []*Error{
+				{Message: "100% wrong\nvalue", Filepath: "a,b:c.yaml", Line: 1, Column: 2, Kind: "rule1"},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := (&GitHubFormatter{}).PrintResults(&b, results); err != nil {
+		t.Fatal(err)
+	}
+	want := "::error file=a%2Cb%3Ac.yaml,line=1,endLine=1,col=2,endColumn=2,title=rule1::100%25 wrong%0Avalue\n"
+	if have := b.String(); have != want {
+		t.Fatalf("wanted %q but got %q", want, have)
+	}
+}
+
+func TestFormatGitHubSummary(t *testing.T) {
+	summary := filepath.Join(t.TempDir(), "summary.md")
+	if err := os.WriteFile(summary, []byte("previous step\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+
+	f := NewGitHubFormatter()
+	results := []*FileResult{
+		{Path: "clean.yaml"},
+		{
+			Path: "dirty.yaml",
+			Errors: []*Error{
+				{Message: "error | 1", Filepath: "dirty.yaml", Line: 2, Column: 3, Kind: "rule1"},
+				{Message: "error 2", Filepath: "dirty.yaml", Line: 4, Column: 5, Kind: "rule2"},
+			},
+		},
+	}
+	if err := f.PrintResults(io.Discard, results); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.PrintResults(io.Discard, results[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "previous step\n" +
+		"## actionlint\n\n" +
+		"Found 2 errors in 1 files.\n\n" +
+		"| File | Line | Column | Rule | Message |\n" +
+		"|------|------|--------|------|---------|\n" +
+		"| `dirty.yaml` | 2 | 3 | `rule1` | error \\| 1 |\n" +
+		"| `dirty.yaml` | 4 | 5 | `rule2` | error 2 |\n\n" +
+		"## actionlint\n\n" +
+		"No problem was found in 1 files.\n\n"
+	if have := string(b); have != want {
+		t.Fatalf("wanted %q but got %q", want, have)
+	}
+}
+
+func TestFormatRDJSONSuggestion(t *testing.T) {
+	latest, ok := latestPopularActionSpec("actions/checkout")
+	if !ok {
+		t.Fatal("latest version of actions/checkout was not found")
+	}
+	if _, ok := OutdatedPopularActionSpecs[latest]; ok {
+		t.Fatalf("latest version %q is outdated", latest)
+	}
+
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v2
+      - uses: 'actions/checkout@v3'
+`
+	var b bytes.Buffer
+	l, err := NewLinter(&b, &LinterOptions{Format: "rdjson"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{}
+	if _, err := l.Lint("test.yaml", []byte(src), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var have rdjsonResult
+	if err := json.Unmarshal(b.Bytes(), &have); err != nil {
+		t.Fatalf("output is not a valid JSON: %v: %s", err, b.String())
+	}
+	if len(have.Diagnostics) != 2 {
+		t.Fatalf("wanted 2 diagnostics but got %d: %s", len(have.Diagnostics), b.String())
+	}
+
+	for i, want := range []rdjsonSuggestion{
+		{rdjsonRange{rdjsonPosition{6, 15}, rdjsonPosition{6, 34}}, latest},
+		{rdjsonRange{rdjsonPosition{7, 16}, rdjsonPosition{7, 35}}, latest},
+	} {
+		d := have.Diagnostics[i]
+		if d.Code.Value != "action/outdated-action" {
+			t.Errorf("unexpected code of diagnostic %d: %#v", i, d.Code)
+		}
+		if len(d.Suggestions) != 1 {
+			t.Errorf("wanted 1 suggestion for diagnostic %d but got %d", i, len(d.Suggestions))
+			continue
+		}
+		if *d.Suggestions[0] != want {
+			t.Errorf("wanted suggestion %#v for diagnostic %d but got %#v", want, i, d.Suggestions[0])
+		}
+	}
+}
+
+func TestFormatRDJSONLines(t *testing.T) {
+	results := []*FileResult{
+		{Path: "clean.yaml"},
+		{
+			Path: "dirty.yaml",
+			Errors: []*Error{
+				{Message: "error 1", Filepath: "dirty.yaml", Line: 1, Column: 1, Kind: "rule1"},
+				{Message: "error 2", Filepath: "dirty.yaml", Line: 2, Column: 3, Kind: "rule2"},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := NewRDJSONLFormatter().PrintResults(&b, results); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wanted 2 lines but got %d: %q", len(lines), b.String())
+	}
+	for i, l := range lines {
+		var d rdjsonDiagnostic
+		if err := json.Unmarshal([]byte(l), &d); err != nil {
+			t.Fatalf("line %d is not a valid JSON: %v: %q", i+1, err, l)
+		}
+		if d.Source == nil || d.Source.Name != "actionlint" {
+			t.Errorf("source is not set to line %d: %q", i+1, l)
+		}
+		if want := results[1].Errors[i].Message; d.Message != want {
+			t.Errorf("wanted message %q but got %q", want, d.Message)
+		}
+	}
+
+	b.Reset()
+	if err := NewRDJSONLFormatter().PrintResults(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("wanted no output for empty results but got %q", b.String())
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"slices"
 	"sort"
@@ -72,13 +71,16 @@ type LinterOptions struct {
 	// won't run to check scripts in workflow file.
 	Pyflakes string
 	// IgnorePatterns is list of regular expression to filter errors. The pattern is applied to error
-	// messages. When an error is matched, the error is ignored.
+	// messages. A pattern with "code:" prefix like "code:expression/untrusted-input" is applied to
+	// error codes and rule names instead, and must match the whole of them. When an error is matched,
+	// the error is ignored.
 	IgnorePatterns []string
 	// ConfigFile is a path to config file. Empty string means no config file path is given. In
 	// the case, actionlint will try to read config from .github/actionlint.yaml.
 	ConfigFile string
-	// Format is a custom template to format error messages. It must follow Go Template format and
-	// contain at least one {{ }} placeholder. https://pkg.go.dev/text/template
+	// Format is a custom template to format error messages or a name of built-in format such as
+	// "junit". A template must follow Go Template format and contain at least one {{ }} placeholder.
+	// https://pkg.go.dev/text/template
 	Format string
 	// StdinFileName is a file name when reading input from stdin. When this value is empty, "<stdin>"
 	// is used as the default value.
@@ -91,24 +93,47 @@ type LinterOptions struct {
 	// function should return the modified rules.
 	// Note that syntax errors may be reported even if this function returns nil or an empty slice.
 	OnRulesCreated func([]Rule) []Rule
+	// Rules is the registry of rules to check workflow files. When this value is nil, the registry
+	// returned from BuiltinRuleRegistry is used. Register your own rules to the registry to apply
+	// them with the same inputs as built-in rules.
+	Rules *RuleRegistry
+	// CacheDir is a path to the directory to store lint results. When this value is not empty, the
+	// result of each file is cached in the directory and checking the file is skipped when the file,
+	// the config, the versions of actionlint and external commands, and the local actions and local
+	// reusable workflows used by the file are all unchanged. The cache is not used when
+	// OnRulesCreated or Rules is set since they may change the results. Results of shellcheck for
+	// each script are also stored in the directory.
+	CacheDir string
+	// Profile enables collecting the time spent by each rule, on each file, and by each external
+	// command. The collected data can be retrieved with Linter.Profile method.
+	Profile bool
+	// EnablePlugins enables the external rule plugins listed in "plugins" of the config file. Plugins
+	// are disabled by default since a config file in an untrusted repository could run arbitrary
+	// commands. The result cache is not used for the files checked with plugins.
+	EnablePlugins bool
 	// More options will come here
 }
 
 // Linter is struct to lint workflow files.
 type Linter struct {
-	projects       *Projects
-	out            io.Writer
-	logOut         io.Writer
-	logLevel       LogLevel
-	oneline        bool
-	shellcheck     string
-	pyflakes       string
-	ignorePats     IgnorePatterns
-	stdin          string
-	defaultConfig  *Config
-	errFmt         *ErrorFormatter
-	cwd            string
-	onRulesCreated func([]Rule) []Rule
+	projects        *Projects
+	out             io.Writer
+	logOut          io.Writer
+	logLevel        LogLevel
+	oneline         bool
+	shellcheck      string
+	pyflakes        string
+	ignorePats      IgnorePatterns
+	stdin           string
+	defaultConfig   *Config
+	errFmt          Formatter
+	cwd             string
+	onRulesCreated  func([]Rule) []Rule
+	rules           *RuleRegistry
+	cache           *resultCache
+	shellcheckCache *shellcheckCache
+	prof            *profiler
+	plugins         bool
 }
 
 // NewLinter creates a new Linter instance.
@@ -149,18 +174,18 @@ func NewLinter(out io.Writer, opts *LinterOptions) (*Linter, error) {
 		cfg = c
 	}
 
-	ignore := make([]*regexp.Regexp, 0, len(opts.IgnorePatterns))
+	ignore := make(IgnorePatterns, 0, len(opts.IgnorePatterns))
 	for _, s := range opts.IgnorePatterns {
-		r, err := regexp.Compile(s)
+		r, err := CompileIgnorePattern(s)
 		if err != nil {
 			return nil, fmt.Errorf("invalid regular expression for ignore pattern %q: %s", s, err.Error())
 		}
 		ignore = append(ignore, r)
 	}
 
-	var formatter *ErrorFormatter
+	var formatter Formatter
 	if opts.Format != "" {
-		f, err := NewErrorFormatter(opts.Format)
+		f, err := NewFormatter(opts.Format)
 		if err != nil {
 			return nil, err
 		}
@@ -193,12 +218,55 @@ func NewLinter(out io.Writer, opts *LinterOptions) (*Linter, error) {
 		formatter,
 		cwd,
 		opts.OnRulesCreated,
+		opts.Rules,
+		nil,
+		nil,
+		nil,
+		opts.EnablePlugins,
+	}
+
+	if l.rules == nil {
+		l.rules = BuiltinRuleRegistry()
+	}
+
+	if opts.Profile {
+		l.prof = newProfiler()
+	}
+
+	if opts.Shellcheck != "" {
+		l.shellcheckCache = newShellcheckCache(opts.CacheDir, l.debugWriter())
+	}
+
+	if opts.CacheDir != "" {
+		if opts.OnRulesCreated != nil {
+			l.log("Result cache was disabled since OnRulesCreated hook was set")
+		} else if opts.Rules != nil {
+			l.log("Result cache was disabled since rule registry was set")
+		} else {
+			l.cache = newResultCache(opts.CacheDir, opts.Shellcheck, opts.Pyflakes, opts.IgnorePatterns, l.debugWriter())
+		}
 	}
 
 	l.debug("Create a Linter instance with option %#v", opts)
 	return l, nil
 }
 
+// Profile returns the timing data collected while linting so far. It returns nil when the Profile
+// option is not enabled.
+func (l *Linter) Profile() *Profile {
+	if l.prof == nil {
+		return nil
+	}
+	return l.prof.profile()
+}
+
+// newProcess creates a concurrentProcess instance to run external commands while linting.
+func (l *Linter) newProcess() *concurrentProcess {
+	p := newConcurrentProcess(runtime.NumCPU())
+	p.prof = l.prof
+	return p
+}
+
 func (l *Linter) log(args ...interface{}) {
 	if l.logLevel < LogLevelVerbose {
 		return
@@ -283,6 +351,17 @@ func (l *Linter) LintRepository(dir string) ([]*Error, error) {
 
 // LintDir lints all YAML workflow files in the given directory recursively.
 func (l *Linter) LintDir(dir string, project *Project) ([]*Error, error) {
+	files, err := collectYAMLFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	l.log("Collected", len(files), "YAML files")
+	return l.LintFiles(files, project)
+}
+
+// collectYAMLFiles collects all YAML files in the directory recursively. The file paths are sorted
+// to make output deterministic.
+func collectYAMLFiles(dir string) ([]string, error) {
 	files := []string{}
 	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -302,12 +381,103 @@ func (l *Linter) LintDir(dir string, project *Project) ([]*Error, error) {
 	if len(files) == 0 {
 		return nil, fmt.Errorf("no YAML file was found in %q", dir)
 	}
-	l.log("Collected", len(files), "YAML files")
 
-	// To make output deterministic, sort order of file paths
 	sort.Strings(files)
+	return files, nil
+}
 
-	return l.LintFiles(files, project)
+// LintChanges lints workflow files changed since the base Git revision and outputs the errors to
+// given writer. It finds the project based on `dir` and runs `git diff` against the merge base of
+// the base revision and HEAD. Changes in the working tree are also considered. Workflows which call
+// the changed local reusable workflows or local actions are also checked. Errors are filtered to
+// the changed lines, or the jobs and steps calling the changed workflows or actions. When allLines
+// is true, all errors in the checked files are reported. When the directory path is empty, the
+// current working directory will be used instead.
+func (l *Linter) LintChanges(dir, base string, allLines bool) ([]*Error, error) {
+	if dir == "" {
+		dir = l.cwd
+	}
+
+	p, err := l.projects.At(dir)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("no project was found in any parent directories of %q. check workflows directory is put correctly in your Git repository", dir)
+	}
+	l.log("Detected project:", p.RootDir())
+
+	d, err := readGitDiff(p.RootDir(), base)
+	if err != nil {
+		return nil, err
+	}
+	l.log("Found", len(d), "changed files since", base)
+
+	ws, err := d.changedWorkflows(p, allLines)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(ws))
+	for f := range ws {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	l.log("Collected", len(files), "changed or affected workflow files")
+
+	dbg := l.debugWriter()
+	acf := NewLocalActionsCacheFactory(dbg)
+	rwcf := NewLocalReusableWorkflowCacheFactory(l.cwd, dbg)
+	lwcf := NewLocalWorkflowsCacheFactory(dbg)
+	return l.lintFiles(files, p, acf, rwcf, lwcf, ws)
+}
+
+// LintStaged lints workflow files staged in the Git index and outputs the errors to given writer.
+// The contents of the workflow files are read from the index instead of the working tree. Local
+// actions, local reusable workflows, and the config file are also read from the index so that the
+// results match what will be committed. The file paths are the paths in the working tree. When no
+// path is given, all workflow files in the index are checked.
+func (l *Linter) LintStaged(filepaths []string) ([]*Error, error) {
+	p, err := l.projects.At(l.cwd)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("no project was found in any parent directories of %q. check workflows directory is put correctly in your Git repository", l.cwd)
+	}
+	l.log("Detected project:", p.RootDir())
+
+	sp, err := newStagedProject(p.RootDir())
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(filepaths))
+	for _, f := range filepaths {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(l.cwd, f)
+		}
+		files = append(files, f)
+	}
+
+	if len(files) == 0 {
+		fs, err := gitIndexFiles(sp.RootDir(), sp.WorkflowsDir())
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fs {
+			if strings.HasSuffix(f, ".yml") || strings.HasSuffix(f, ".yaml") {
+				files = append(files, f)
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no YAML file was found in %q in Git index", sp.WorkflowsDir())
+		}
+		sort.Strings(files)
+	}
+	l.log("Collected", len(files), "staged YAML files")
+
+	return l.LintFiles(files, sp)
 }
 
 // LintFiles lints YAML workflow files and outputs the errors to given writer. It applies lint
@@ -322,16 +492,73 @@ func (l *Linter) LintFiles(filepaths []string, project *Project) ([]*Error, erro
 		return l.LintFile(filepaths[0], project)
 	}
 
-	l.log("Linting", n, "files")
+	dbg := l.debugWriter()
+	acf := NewLocalActionsCacheFactory(dbg)
+	rwcf := NewLocalReusableWorkflowCacheFactory(l.cwd, dbg)
+	lwcf := NewLocalWorkflowsCacheFactory(dbg)
+	return l.lintFiles(filepaths, project, acf, rwcf, lwcf, nil)
+}
+
+// lintFiles lints the workflow files with the given cache factories and outputs the errors. Caches
+// created by the factories are shared by all the files. When changedLines is not nil, the errors
+// are filtered to the changed lines of each file. See checkFiles for more details.
+func (l *Linter) lintFiles(
+	filepaths []string,
+	project *Project,
+	acf *LocalActionsCacheFactory,
+	rwcf *LocalReusableWorkflowCacheFactory,
+	lwcf *LocalWorkflowsCacheFactory,
+	changedLines map[string]lineSet,
+) ([]*Error, error) {
+	rs, err := l.checkFiles(filepaths, project, acf, rwcf, lwcf, changedLines)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, r := range rs {
+		total += len(r.Errors)
+	}
+
+	all := make([]*Error, 0, total)
+	if l.errFmt != nil {
+		for _, r := range rs {
+			all = append(all, r.Errors...)
+		}
+		if err := l.errFmt.PrintResults(l.out, rs); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, r := range rs {
+			l.printErrors(r.Errors, r.Source)
+			all = append(all, r.Errors...)
+		}
+	}
+
+	l.log("Found", total, "errors in", len(rs), "files")
+
+	return all, nil
+}
+
+// checkFiles checks the workflow files in parallel with the given cache factories and returns the
+// results in the same order as the file paths. Nothing is output. When changedLines is not nil, only
+// the errors on the changed lines of each file are returned. It is a mapping from the file paths in
+// filepaths to the changed lines.
+func (l *Linter) checkFiles(
+	filepaths []string,
+	project *Project,
+	acf *LocalActionsCacheFactory,
+	rwcf *LocalReusableWorkflowCacheFactory,
+	lwcf *LocalWorkflowsCacheFactory,
+	changedLines map[string]lineSet,
+) ([]*FileResult, error) {
+	l.log("Linting", len(filepaths), "files")
 
 	cwd := l.cwd
 	cpus := runtime.NumCPU()
-	proc := newConcurrentProcess(cpus)
+	proc := l.newProcess()
 	sema := semaphore.NewWeighted(int64(cpus))
 	ctx := context.Background()
-	dbg := l.debugWriter()
-	acf := NewLocalActionsCacheFactory(dbg)
-	rwcf := NewLocalReusableWorkflowCacheFactory(cwd, dbg)
 
 	type workspace struct {
 		path string
@@ -360,25 +587,34 @@ func (l *Linter) LintFiles(filepaths []string, project *Project) ([]*Error, erro
 		}
 		ac := acf.GetCache(proj) // #173
 		rwc := rwcf.GetCache(proj)
+		lwc := lwcf.GetCache(proj)
 
 		eg.Go(func() error {
 			// Bound concurrency on reading files to avoid "too many files to open" error (issue #3)
 			sema.Acquire(ctx, 1)
-			src, err := os.ReadFile(w.path)
+			src, err := readProjectFile(proj, w.path)
 			sema.Release(1)
 			if err != nil {
 				return fmt.Errorf("could not read %q: %w", w.path, err)
 			}
 
+			lines, onlyChanges := changedLines[w.path]
 			if cwd != "" {
 				if r, err := filepath.Rel(cwd, w.path); err == nil {
 					w.path = r // Use relative path if possible
 				}
 			}
-			errs, err := l.check(w.path, src, proj, proc, ac, rwc)
+			errs, err := l.check(w.path, src, proj, proc, ac, rwc, lwc)
 			if err != nil {
 				return fmt.Errorf("fatal error while checking %s: %w", w.path, err)
 			}
+			if onlyChanges {
+				n := len(errs)
+				errs = filterErrorsOnLines(errs, lines)
+				if n != len(errs) {
+					l.log("Filtered", n-len(errs), "error(s) on unchanged lines in", w.path)
+				}
+			}
 			w.src = src
 			w.errs = errs
 			return nil
@@ -397,35 +633,12 @@ func (l *Linter) LintFiles(filepaths []string, project *Project) ([]*Error, erro
 	// called safely.
 	proc.wait()
 
-	total := 0
+	rs := make([]*FileResult, 0, len(ws))
 	for i := range ws {
-		total += len(ws[i].errs)
-	}
-
-	all := make([]*Error, 0, total)
-	if l.errFmt != nil {
-		temp := make([]*ErrorTemplateFields, 0, total)
-		for i := range ws {
-			w := &ws[i]
-			for _, err := range w.errs {
-				temp = append(temp, err.GetTemplateFields(w.src))
-			}
-			all = append(all, w.errs...)
-		}
-		if err := l.errFmt.Print(l.out, temp); err != nil {
-			return nil, err
-		}
-	} else {
-		for i := range ws {
-			w := &ws[i]
-			l.printErrors(w.errs, w.src)
-			all = append(all, w.errs...)
-		}
+		w := &ws[i]
+		rs = append(rs, &FileResult{w.path, w.src, w.errs})
 	}
-
-	l.log("Found", total, "errors in", n, "files")
-
-	return all, nil
+	return rs, nil
 }
 
 // LintFile lints one YAML workflow file and outputs the errors to given writer. The project
@@ -439,7 +652,7 @@ func (l *Linter) LintFile(path string, project *Project) ([]*Error, error) {
 		project = p
 	}
 
-	src, err := os.ReadFile(path)
+	src, err := readProjectFile(project, path)
 	if err != nil {
 		return nil, fmt.Errorf("could not read %q: %w", path, err)
 	}
@@ -450,18 +663,21 @@ func (l *Linter) LintFile(path string, project *Project) ([]*Error, error) {
 		}
 	}
 
-	proc := newConcurrentProcess(runtime.NumCPU())
+	proc := l.newProcess()
 	dbg := l.debugWriter()
 	localActions := NewLocalActionsCache(project, dbg)
 	localReusableWorkflows := NewLocalReusableWorkflowCache(project, l.cwd, dbg)
-	errs, err := l.check(path, src, project, proc, localActions, localReusableWorkflows)
+	localWorkflows := NewLocalWorkflowsCache(project, dbg)
+	errs, err := l.check(path, src, project, proc, localActions, localReusableWorkflows, localWorkflows)
 	proc.wait()
 	if err != nil {
 		return nil, err
 	}
 
 	if l.errFmt != nil {
-		l.errFmt.PrintErrors(l.out, errs, src)
+		if err := l.errFmt.PrintResults(l.out, []*FileResult{{path, src, errs}}); err != nil {
+			return nil, err
+		}
 	} else {
 		l.printErrors(errs, src)
 	}
@@ -493,23 +709,56 @@ func (l *Linter) Lint(path string, content []byte, project *Project) ([]*Error,
 			project = p
 		}
 	}
-	proc := newConcurrentProcess(runtime.NumCPU())
+	proc := l.newProcess()
 	dbg := l.debugWriter()
 	localActions := NewLocalActionsCache(project, dbg)
 	localReusableWorkflows := NewLocalReusableWorkflowCache(project, l.cwd, dbg)
-	errs, err := l.check(path, content, project, proc, localActions, localReusableWorkflows)
+	localWorkflows := NewLocalWorkflowsCache(project, dbg)
+	errs, err := l.check(path, content, project, proc, localActions, localReusableWorkflows, localWorkflows)
 	proc.wait()
 	if err != nil {
 		return nil, err
 	}
 	if l.errFmt != nil {
-		l.errFmt.PrintErrors(l.out, errs, content)
+		if err := l.errFmt.PrintResults(l.out, []*FileResult{{path, content, errs}}); err != nil {
+			return nil, err
+		}
 	} else {
 		l.printErrors(errs, content)
 	}
 	return errs, nil
 }
 
+// newBuiltinRules creates the built-in rule instances except for the rules which depend on external
+// commands.
+func newBuiltinRules(path string, localActions *LocalActionsCache, localReusableWorkflows *LocalReusableWorkflowCache, localWorkflows *LocalWorkflowsCache) []Rule {
+	ctx := &RuleContext{
+		Path:                   path,
+		LocalActions:           localActions,
+		LocalReusableWorkflows: localReusableWorkflows,
+		LocalWorkflows:         localWorkflows,
+	}
+	rules := make([]Rule, 0, len(builtinRuleEntries))
+	for _, e := range builtinRuleEntries {
+		r, _ := e.factory(ctx) // Built-in rules never fail
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// BuiltinRuleMetadata returns the metadata of all built-in rules in the order of applying them. The
+// rules which depend on external commands are also included regardless of the availability of the
+// commands.
+func BuiltinRuleMetadata() []*RuleMetadata {
+	rules := newBuiltinRules("", nil, nil, nil)
+	rules = append(rules, newRuleShellcheck(nil), newRulePyflakes(nil))
+	ms := make([]*RuleMetadata, 0, len(rules))
+	for _, r := range rules {
+		ms = append(ms, r.Metadata())
+	}
+	return ms
+}
+
 func (l *Linter) check(
 	path string,
 	content []byte,
@@ -517,12 +766,13 @@ func (l *Linter) check(
 	proc *concurrentProcess,
 	localActions *LocalActionsCache,
 	localReusableWorkflows *LocalReusableWorkflowCache,
+	localWorkflows *LocalWorkflowsCache,
 ) ([]*Error, error) {
 	// Note: This method is called to check multiple files in parallel.
 	// It must be thread safe assuming fields of Linter are not modified while running.
 
 	var start time.Time
-	if l.logLevel >= LogLevelVerbose {
+	if l.logLevel >= LogLevelVerbose || l.prof != nil {
 		start = time.Now()
 	}
 
@@ -544,7 +794,34 @@ func (l *Linter) check(
 		l.debug("No config was found")
 	}
 
-	w, all := Parse(content)
+	var key string
+	if l.cache != nil && l.hasPlugins(cfg) {
+		l.log("Result cache was not used for", path, "since it is checked with plugins")
+	} else if l.cache != nil {
+		k, err := l.cache.key(path, content, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if errs, ok := l.cache.get(k, project); ok {
+			l.log("Found", len(errs), "errors in result cache for", path)
+			if l.errFmt != nil {
+				rules, err := l.newRules(path, project, cfg, proc, localActions, localReusableWorkflows, localWorkflows)
+				if err != nil {
+					return nil, err
+				}
+				for _, r := range rules {
+					l.errFmt.RegisterRule(r)
+				}
+			}
+			if l.prof != nil {
+				l.prof.addFile(path, time.Since(start))
+			}
+			return errs, nil
+		}
+		key = k
+	}
+
+	w, ends, all := parseWorkflow(content)
 
 	if l.logLevel >= LogLevelVerbose {
 		elapsed := time.Since(start)
@@ -554,51 +831,16 @@ func (l *Linter) check(
 	if w != nil {
 		dbg := l.debugWriter()
 
-		rules := []Rule{
-			NewRuleMatrix(),
-			NewRuleCredentials(),
-			NewRuleShellName(),
-			NewRuleRunnerLabel(),
-			NewRuleEvents(),
-			NewRuleJobNeeds(),
-			NewRuleAction(localActions),
-			NewRuleEnvVar(),
-			NewRuleID(),
-			NewRuleGlob(),
-			NewRulePermissions(),
-			NewRuleWorkflowCall(path, localReusableWorkflows),
-			NewRuleExpression(localActions, localReusableWorkflows),
-			NewRuleDeprecatedCommands(),
-			NewRuleIfCond(),
-		}
-		if l.shellcheck != "" {
-			r, err := NewRuleShellcheck(l.shellcheck, proc)
-			if err == nil {
-				rules = append(rules, r)
-			} else {
-				l.log("Rule \"shellcheck\" was disabled:", err)
-			}
-		} else {
-			l.log("Rule \"shellcheck\" was disabled since shellcheck command name was empty")
-		}
-		if l.pyflakes != "" {
-			r, err := NewRulePyflakes(l.pyflakes, proc)
-			if err == nil {
-				rules = append(rules, r)
-			} else {
-				l.log("Rule \"pyflakes\" was disabled:", err)
-			}
-		} else {
-			l.log("Rule \"pyflakes\" was disabled since pyflakes command name was empty")
-		}
-		if l.onRulesCreated != nil {
-			rules = l.onRulesCreated(rules)
+		rules, err := l.newRules(path, project, cfg, proc, localActions, localReusableWorkflows, localWorkflows)
+		if err != nil {
+			return nil, err
 		}
 
 		v := NewVisitor()
 		for _, rule := range rules {
 			v.AddPass(rule)
 		}
+		v.EnableParallel(runtime.NumCPU()) // Check jobs of a large workflow in parallel
 		if dbg != nil {
 			v.EnableDebug(dbg)
 			for _, r := range rules {
@@ -610,15 +852,25 @@ func (l *Linter) check(
 				r.SetConfig(cfg)
 			}
 		}
+		if l.prof != nil {
+			v.enableProfile()
+		}
 
 		if err := v.Visit(w); err != nil {
 			l.debug("Error occurred while visiting workflow syntax tree: %v", err)
 			return nil, err
 		}
 
+		if l.prof != nil {
+			for i, d := range v.passElapsedTimes() {
+				l.prof.addRule(rules[i].Name(), d)
+			}
+		}
+
 		for _, rule := range rules {
 			errs := rule.Errs()
 			l.debug("%s found %d errors", rule.Name(), len(errs))
+			setErrorEndsAtStrings(errs, ends)
 			all = append(all, errs...)
 		}
 
@@ -633,11 +885,24 @@ func (l *Linter) check(
 
 	for _, err := range all {
 		err.Filepath = path // Populate filename in the error
+		for i := range err.Related {
+			if err.Related[i].Filepath == "" {
+				err.Related[i].Filepath = path
+			}
+		}
 	}
 
 	slices.SortFunc(all, compareErrors)
 	all = slices.CompactFunc(all, equalsErrors) // Alias may duplicate errors
 
+	if key != "" {
+		l.cache.put(key, all, w, project)
+	}
+
+	if l.prof != nil {
+		l.prof.addFile(path, time.Since(start))
+	}
+
 	if l.logLevel >= LogLevelVerbose {
 		elapsed := time.Since(start)
 		l.log("Found total", len(all), "errors in", elapsed.Milliseconds(), "ms for", path)
@@ -646,6 +911,74 @@ func (l *Linter) check(
 	return all, nil
 }
 
+// newRules creates the rules to check the workflow file. It returns an error when some plugin in
+// the config could not be enabled.
+func (l *Linter) newRules(
+	path string,
+	project *Project,
+	cfg *Config,
+	proc *concurrentProcess,
+	localActions *LocalActionsCache,
+	localReusableWorkflows *LocalReusableWorkflowCache,
+	localWorkflows *LocalWorkflowsCache,
+) ([]Rule, error) {
+	ctx := &RuleContext{
+		Path:                   path,
+		Project:                project,
+		Config:                 cfg,
+		LocalActions:           localActions,
+		LocalReusableWorkflows: localReusableWorkflows,
+		LocalWorkflows:         localWorkflows,
+		proc:                   proc,
+		shellcheck:             l.shellcheck,
+		pyflakes:               l.pyflakes,
+		shellcheckCache:        l.shellcheckCache,
+	}
+	rules, errs := l.rules.Create(ctx)
+	for _, err := range errs {
+		l.log(err)
+	}
+	if cfg != nil && len(cfg.Plugins) > 0 {
+		if l.plugins {
+			ps, err := newPluginRules(ctx, cfg.Plugins, rules)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, ps...)
+		} else {
+			l.log("Plugins in config were ignored since plugins are not enabled")
+		}
+	}
+	if l.onRulesCreated != nil {
+		rules = l.onRulesCreated(rules)
+	}
+	return rules, nil
+}
+
+// newPluginRules creates the rules of the plugins in the config. Since plugins are enabled
+// explicitly, it returns an error when a plugin cannot be enabled because its name conflicts with
+// the other rules or its command is not found. Skipping the plugin silently would make the check
+// look successful even though the plugin never ran.
+func newPluginRules(ctx *RuleContext, plugins []PluginConfig, others []Rule) ([]Rule, error) {
+	rules := make([]Rule, 0, len(plugins))
+	for i := range plugins {
+		p := &plugins[i]
+		if slices.ContainsFunc(others, func(r Rule) bool { return r.Name() == p.Name }) {
+			return nil, fmt.Errorf("plugin %q could not be enabled since a rule with the same name already exists", p.Name)
+		}
+		r, err := NewRulePlugin(p, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %q could not be enabled: %w", p.Name, err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func (l *Linter) hasPlugins(cfg *Config) bool {
+	return l.plugins && cfg != nil && len(cfg.Plugins) > 0
+}
+
 func (l *Linter) filterErrors(errs []*Error, cfgs []PathConfig) []*Error {
 	if len(l.ignorePats) == 0 && len(cfgs) == 0 {
 		return errs
@@ -367,8 +367,35 @@ func TestLinterFormatErrorMessageOK(t *testing.T) {
 			file:   "test.md",
 			format: "{{range $ := .}}### Error at line {{$.Line}}, col {{$.Column}} of `{{$.Filepath}}`\\n\\n{{$.Message}}\\n\\n```\\n{{$.Snippet}}\\n```\\n\\n{{end}}",
 		},
+		{
+			file:   "test.junit.xml",
+			format: "junit",
+		},
+		{
+			file:   "test.checkstyle.xml",
+			format: "checkstyle",
+		},
+		{
+			file:   "test.gitlab.json",
+			format: "gitlab",
+		},
+		{
+			file:   "test.github.txt",
+			format: "github",
+		},
+		{
+			file:   "test.rdjson",
+			format: "rdjson",
+		},
+		{
+			file:   "test.rdjsonl",
+			format: "rdjsonl",
+		},
 	}
 
+	// Do not write the job summary while running tests on GitHub Actions
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+
 	dir := filepath.Join("testdata", "format")
 	proj := &Project{root: dir}
 	infile := filepath.Join(dir, "test.yaml")
@@ -469,6 +496,267 @@ func TestLinterFormatErrorMessageInSARIF(t *testing.T) {
 	}
 }
 
+func TestLinterErrorRelatedLocations(t *testing.T) {
+	testCases := []struct {
+		what string
+		kind string
+		src  string
+		want []RelatedLocation
+	}{
+		{
+			what: "duplicate key",
+			kind: "syntax-check",
+			src: `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo`,
+			want: []RelatedLocation{
+				{Filepath: "test.yaml", Line: 4, Column: 5, Note: `key "runs-on" is previously defined here`},
+			},
+		},
+		{
+			what: "duplicate step ID",
+			kind: "id",
+			src: `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+        id: foo
+      - run: echo
+        id: FOO`,
+			want: []RelatedLocation{
+				{Filepath: "test.yaml", Line: 7, Column: 13, Note: "the same step ID is previously defined here"},
+			},
+		},
+		{
+			what: "duplicate job ID in needs",
+			kind: "job-needs",
+			src: `on: push
+jobs:
+  a:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+  b:
+    needs: [a, A]
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo`,
+			want: []RelatedLocation{
+				{Filepath: "test.yaml", Line: 8, Column: 13, Note: `job ID "a" is first specified here`},
+			},
+		},
+		{
+			what: "cyclic dependencies in needs",
+			kind: "job-needs",
+			src: `on: push
+jobs:
+  a:
+    needs: c
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+  b:
+    needs: a
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+  c:
+    needs: [b]
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo`,
+			want: []RelatedLocation{
+				{Filepath: "test.yaml", Line: 4, Column: 12, Note: `job "a" needs job "c" here`},
+				{Filepath: "test.yaml", Line: 14, Column: 13, Note: `job "c" needs job "b" here`},
+				{Filepath: "test.yaml", Line: 9, Column: 12, Note: `job "b" needs job "a" here`},
+			},
+		},
+		{
+			what: "exclude not matching matrix",
+			kind: "matrix",
+			src: `on: push
+jobs:
+  test:
+    strategy:
+      matrix:
+        os: [ubuntu-latest, macos-latest]
+        exclude:
+          - os: windows-latest
+    runs-on: ${{ matrix.os }}
+    steps:
+      - run: echo`,
+			want: []RelatedLocation{
+				{Filepath: "test.yaml", Line: 6, Column: 9, Note: `matrix "os" is defined here`},
+			},
+		},
+		{
+			what: "duplicate value in matrix",
+			kind: "matrix",
+			src: `on: push
+jobs:
+  test:
+    strategy:
+      matrix:
+        os: [ubuntu-latest, ubuntu-latest]
+    runs-on: ${{ matrix.os }}
+    steps:
+      - run: echo`,
+			want: []RelatedLocation{
+				{Filepath: "test.yaml", Line: 6, Column: 14, Note: `value "ubuntu-latest" is first defined here`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			l, err := NewLinter(io.Discard, &LinterOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.defaultConfig = &Config{}
+
+			errs, err := l.Lint("test.yaml", []byte(tc.src), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var have []RelatedLocation
+			for _, e := range errs {
+				if e.Kind == tc.kind {
+					have = e.Related
+					break
+				}
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf("related locations mismatch: %s\nerrors: %v", diff, errs)
+			}
+		})
+	}
+}
+
+func TestLinterErrorEndPositions(t *testing.T) {
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    timeout-minutes: 'foo bar'
+    continue-on-error: ${{ 'foo' }}
+    steps:
+      - run: echo hi
+        if: ${{ 42 }}
+      - run: echo hi
+        shell: 'fish shell'
+`
+	l, err := NewLinter(io.Discard, &LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{}
+
+	errs, err := l.Lint("test.yaml", []byte(src), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type span struct {
+		code                       string
+		line, col, endLine, endCol int
+	}
+	want := []span{
+		{"syntax-check/expression-expected", 5, 22, 5, 30},
+		{"expression/type-mismatch", 6, 24, 6, 35},
+		{"if-cond/constant-condition", 9, 13, 9, 21},
+		{"shell-name/invalid-shell", 11, 16, 11, 27},
+	}
+	have := make([]span, 0, len(errs))
+	for _, e := range errs {
+		have = append(have, span{e.Code, e.Line, e.Column, e.EndLine, e.EndColumn})
+	}
+	if diff := cmp.Diff(want, have, cmp.AllowUnexported(span{})); diff != "" {
+		t.Fatalf("ranges of errors mismatch: %s\nerrors: %v", diff, errs)
+	}
+}
+
+func TestLinterErrorRelatedLocationInReusableWorkflow(t *testing.T) {
+	repo := filepath.Join("testdata", "projects", "workflow_call_input_type_check")
+	l, err := NewLinter(io.Discard, &LinterOptions{WorkingDir: repo})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs, err := l.LintFile(filepath.Join(repo, "workflows", "test.yaml"), &Project{root: repo})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var have []RelatedLocation
+	for _, e := range errs {
+		if e.Kind == "expression" && e.Line == 8 {
+			have = e.Related
+			break
+		}
+	}
+	want := []RelatedLocation{
+		{Filepath: filepath.Join("workflows", "reusable.yaml"), Line: 4, Column: 7, Note: `input "str_input" is typed as string here`},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("related locations mismatch: %s\nerrors: %v", diff, errs)
+	}
+}
+
+func TestLinterBuiltinRuleMetadataExamples(t *testing.T) {
+	for _, m := range BuiltinRuleMetadata() {
+		t.Run(m.Name, func(t *testing.T) {
+			if m.Description == "" || m.Explanation == "" || m.BadExample == "" || m.GoodExample == "" {
+				t.Fatalf("metadata is not fully filled: %#v", m)
+			}
+
+			opts := LinterOptions{Shellcheck: "", Pyflakes: ""}
+			if m.Command != "" {
+				p, err := execabs.LookPath(m.Command)
+				if err != nil {
+					t.Skipf("%s command is not found", m.Command)
+				}
+				if m.Command == "shellcheck" {
+					opts.Shellcheck = p
+				} else {
+					opts.Pyflakes = p
+				}
+			}
+			l, err := NewLinter(io.Discard, &opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.defaultConfig = &Config{}
+
+			// The bad example of "workflow-run" rule depends on other workflow in the repository
+			if m.Name != "workflow-run" {
+				errs, err := l.Lint("bad.yaml", []byte(m.BadExample), nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !slices.ContainsFunc(errs, func(e *Error) bool { return e.Kind == m.Name }) {
+					t.Errorf("bad example is not reported by the rule: %v", errs)
+				}
+			}
+
+			errs, err := l.Lint("good.yaml", []byte(m.GoodExample), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(errs) > 0 {
+				t.Errorf("good example is reported: %v", errs)
+			}
+		})
+	}
+}
+
 func TestLinterLintStdinOK(t *testing.T) {
 	for _, f := range []string{"", "foo.yaml"} {
 		l, err := NewLinter(io.Discard, &LinterOptions{StdinFileName: f})
@@ -918,3 +1206,83 @@ func BenchmarkLintRepository(b *testing.B) {
 		}
 	}
 }
+
+func TestLinterLintStaged(t *testing.T) {
+	if _, err := execabs.LookPath("git"); err != nil {
+		t.Skip("git command is not found")
+	}
+
+	dir := t.TempDir()
+	testRunGit(t, dir, "init", "-q")
+	testWriteFiles(t, dir, map[string]string{
+		".github/workflows/test.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ./action
+`,
+		"action/action.yml": `name: Test
+description: Test action
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`,
+	})
+	testRunGit(t, dir, "add", "-A")
+	testRunGit(t, dir, "commit", "-q", "-m", "initial")
+
+	// Stage a required input of the local action and an error in the workflow
+	testWriteFiles(t, dir, map[string]string{
+		".github/workflows/test.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ./action
+      - run: echo ${{ unknown_context }}
+`,
+		"action/action.yml": `name: Test
+description: Test action
+inputs:
+  name:
+    description: Name
+    required: true
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`,
+		".github/actionlint.yaml": `paths:
+  .github/workflows/test.yaml:
+    ignore: ['code:expression/undefined-variable']
+`,
+	})
+	testRunGit(t, dir, "add", "-A")
+
+	// Revert the changes in the working tree. They should not affect the results
+	testRunGit(t, dir, "checkout", "--", ".github/workflows/test.yaml", "action/action.yml")
+	if err := os.Remove(filepath.Join(dir, ".github", "actionlint.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{nil, {filepath.Join(".github", "workflows", "test.yaml")}} {
+		l, err := NewLinter(io.Discard, &LinterOptions{WorkingDir: dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		errs, err := l.LintStaged(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) != 1 {
+			t.Fatalf("wanted exactly one error but got %d errors: %v", len(errs), errs)
+		}
+		if e := errs[0]; e.Code != "action/missing-required-input" || e.Line != 6 || e.Filepath != filepath.Join(".github", "workflows", "test.yaml") {
+			t.Fatalf("unexpected error: %#v", e)
+		}
+	}
+}
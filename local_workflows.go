@@ -0,0 +1,179 @@
+package actionlint
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LocalWorkflow is a workflow file put in ".github/workflows" directory of a project.
+type LocalWorkflow struct {
+	// Path is a file path of the workflow relative to the project root directory. The path
+	// separator is always '/'.
+	Path string
+	// Workflow is a syntax tree of the workflow. Note that the workflow may contain syntax errors.
+	Workflow *Workflow
+}
+
+// Name returns the name of the workflow. When "name:" is omitted in the workflow, GitHub uses the
+// file path of the workflow as its name.
+// https://docs.github.com/en/actions/reference/workflows-and-actions/workflow-syntax#name
+func (w *LocalWorkflow) Name() string {
+	if w.Workflow.Name != nil && !w.Workflow.Name.ContainsExpression() {
+		return w.Workflow.Name.Value
+	}
+	return w.Path
+}
+
+// LocalWorkflowsCache is a cache for workflow files in a project. It avoids finding/reading/parsing
+// the workflow files multiple times when checking relationships across workflows. This cache is
+// dedicated for a single project (repository) indicated by 'proj' field. One LocalWorkflowsCache
+// instance needs to be created per one project.
+type LocalWorkflowsCache struct {
+	mu        sync.Mutex
+	proj      *Project // might be nil
+	workflows []*LocalWorkflow
+	loaded    bool
+	dbg       io.Writer
+}
+
+// NewLocalWorkflowsCache creates new LocalWorkflowsCache instance for the given project.
+func NewLocalWorkflowsCache(proj *Project, dbg io.Writer) *LocalWorkflowsCache {
+	return &LocalWorkflowsCache{
+		proj: proj,
+		dbg:  dbg,
+	}
+}
+
+func newNullLocalWorkflowsCache(dbg io.Writer) *LocalWorkflowsCache {
+	// Null cache. Cache never hits. It is used when project is not found
+	return &LocalWorkflowsCache{dbg: dbg}
+}
+
+func (c *LocalWorkflowsCache) debug(format string, args ...interface{}) {
+	if c.dbg == nil {
+		return
+	}
+	format = "[LocalWorkflowsCache] " + format + "\n"
+	fmt.Fprintf(c.dbg, format, args...)
+}
+
+func (c *LocalWorkflowsCache) load() error {
+	dir := c.proj.WorkflowsDir()
+	names, err := c.proj.listFiles(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.debug("Workflows directory %q does not exist", dir)
+			return nil
+		}
+		return fmt.Errorf("could not read workflows directory %q: %w", dir, err)
+	}
+
+	root := c.proj.RootDir()
+	for _, n := range names {
+		if !(strings.HasSuffix(n, ".yml") || strings.HasSuffix(n, ".yaml")) {
+			continue
+		}
+
+		path := filepath.Join(dir, n)
+		src, err := c.proj.readFile(path)
+		if err != nil {
+			c.debug("Skip workflow %q since it could not be read: %s", path, err)
+			continue
+		}
+		w, _ := Parse(src)
+		if w == nil {
+			c.debug("Skip workflow %q since it could not be parsed", path)
+			continue
+		}
+
+		if r, err := filepath.Rel(root, path); err == nil {
+			path = r
+		}
+		c.workflows = append(c.workflows, &LocalWorkflow{filepath.ToSlash(path), w})
+	}
+
+	sort.Slice(c.workflows, func(i, j int) bool {
+		return c.workflows[i].Path < c.workflows[j].Path
+	})
+	c.debug("Loaded %d workflows in %q", len(c.workflows), dir)
+	return nil
+}
+
+// invalidate discards the loaded workflows. They are loaded again at the next call of FindByName.
+// This method is used when some workflow file is changed.
+func (c *LocalWorkflowsCache) invalidate() {
+	c.mu.Lock()
+	c.loaded = false
+	c.workflows = nil
+	c.mu.Unlock()
+}
+
+// FindByName finds workflows whose names are equal to the given name. Workflow names are compared
+// in case-sensitive. When no project is set to this cache, this method always returns nil. The
+// returned workflows are sorted by their file paths.
+//
+// The workflows directory is read and all workflows in it are parsed at the first call. The error
+// is returned only when the directory could not be read. Workflow files which cannot be read or
+// parsed are skipped.
+//
+// Calling this method is thread-safe.
+func (c *LocalWorkflowsCache) FindByName(name string) ([]*LocalWorkflow, error) {
+	if c.proj == nil {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded {
+		c.loaded = true
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	var ret []*LocalWorkflow
+	for _, w := range c.workflows {
+		if w.Name() == name {
+			ret = append(ret, w)
+		}
+	}
+	return ret, nil
+}
+
+// LocalWorkflowsCacheFactory is a factory to create LocalWorkflowsCache instances. LocalWorkflowsCache
+// should be created for each repositories. LocalWorkflowsCacheFactory creates new LocalWorkflowsCache
+// instance per repository (project).
+type LocalWorkflowsCacheFactory struct {
+	mu     sync.Mutex
+	caches map[string]*LocalWorkflowsCache
+	dbg    io.Writer
+}
+
+// GetCache returns LocalWorkflowsCache instance for the given project. One LocalWorkflowsCache is
+// created per one repository. Created instances are cached and will be used when caches are
+// requested for the same projects. Calling this method is thread-safe.
+func (f *LocalWorkflowsCacheFactory) GetCache(p *Project) *LocalWorkflowsCache {
+	if p == nil {
+		return newNullLocalWorkflowsCache(f.dbg)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := p.RootDir()
+	if c, ok := f.caches[r]; ok {
+		return c
+	}
+	c := NewLocalWorkflowsCache(p, f.dbg)
+	f.caches[r] = c
+	return c
+}
+
+// NewLocalWorkflowsCacheFactory creates a new LocalWorkflowsCacheFactory instance.
+func NewLocalWorkflowsCacheFactory(dbg io.Writer) *LocalWorkflowsCacheFactory {
+	return &LocalWorkflowsCacheFactory{caches: map[string]*LocalWorkflowsCache{}, dbg: dbg}
+}
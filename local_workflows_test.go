@@ -0,0 +1,83 @@
+package actionlint
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalWorkflowsCacheFindByName(t *testing.T) {
+	proj := &Project{filepath.Join("testdata", "local_workflows"), nil, false, ""}
+	c := NewLocalWorkflowsCache(proj, nil)
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"CI", []string{".github/workflows/ci.yaml"}},
+		{"Release", []string{".github/workflows/release.yaml"}},
+		{".github/workflows/no_name.yml", []string{".github/workflows/no_name.yml"}},
+		{"ci", nil},
+		{"Broken", nil},
+		{"Unknown", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ws, err := c.FindByName(tc.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ws) != len(tc.want) {
+				t.Fatalf("wanted %d workflows but got %d workflows: %v", len(tc.want), len(ws), ws)
+			}
+			for i, w := range ws {
+				if w.Path != tc.want[i] {
+					t.Errorf("wanted path %q but got %q", tc.want[i], w.Path)
+				}
+				if w.Workflow == nil {
+					t.Errorf("syntax tree of workflow %q is nil", w.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalWorkflowsCacheNullCache(t *testing.T) {
+	c := newNullLocalWorkflowsCache(nil)
+	ws, err := c.FindByName("CI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws) > 0 {
+		t.Fatal("null cache found some workflow", ws)
+	}
+}
+
+func TestLocalWorkflowsCacheNoWorkflowsDir(t *testing.T) {
+	proj := &Project{filepath.Join("testdata", "local_workflows", "this-dir-does-not-exist"), nil, false, ""}
+	c := NewLocalWorkflowsCache(proj, nil)
+	ws, err := c.FindByName("CI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ws) > 0 {
+		t.Fatal("some workflow was found", ws)
+	}
+}
+
+func TestLocalWorkflowsCacheFactory(t *testing.T) {
+	f := NewLocalWorkflowsCacheFactory(nil)
+	p1 := &Project{filepath.Join("testdata", "local_workflows"), nil, false, ""}
+	p2 := &Project{filepath.Join("testdata", "find_project"), nil, false, ""}
+
+	c1 := f.GetCache(p1)
+	if c1 != f.GetCache(p1) {
+		t.Error("cache was not shared for the same project")
+	}
+	if c1 == f.GetCache(p2) {
+		t.Error("cache was shared for different projects")
+	}
+	if c := f.GetCache(nil); c.proj != nil {
+		t.Error("null cache was not returned for nil project", c.proj)
+	}
+}
@@ -33,9 +33,132 @@ func posAt(n *yaml.Node) *Pos {
 	return &Pos{n.Line, n.Column}
 }
 
-func newString(n *yaml.Node) *String {
+func (p *parser) newString(n *yaml.Node) *String {
 	quoted := n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0
-	return &String{n.Value, quoted, posAt(n)}
+	literal := n.Style&yaml.LiteralStyle != 0
+	s := &String{n.Value, quoted, literal, posAt(n), p.endPosOf(n)}
+	if s.EndPos != nil {
+		if _, ok := p.stringEnds[*s.Pos]; !ok {
+			p.stringEnds[*s.Pos] = s.EndPos
+		}
+	}
+	return s
+}
+
+// endPosOf returns the position of the last character of the scalar node in the source. For quoted
+// strings, it is the position of the closing quote. For block scalars, it is the position of the
+// last character of the last line in the block. nil is returned when the position is unknown.
+func (p *parser) endPosOf(n *yaml.Node) *Pos {
+	if n.Kind != yaml.ScalarNode || n.Line <= 0 || n.Line > len(p.lines) || n.Column <= 0 {
+		return nil
+	}
+	switch {
+	case n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		return p.blockScalarEndPos(n)
+	case n.Style&yaml.SingleQuotedStyle != 0:
+		return p.quotedScalarEndPos(n, '\'')
+	case n.Style&yaml.DoubleQuotedStyle != 0:
+		return p.quotedScalarEndPos(n, '"')
+	default:
+		return p.plainScalarEndPos(n)
+	}
+}
+
+// line returns the runes of the line in the source. The line number is 1-based.
+func (p *parser) line(l int) []rune {
+	return []rune(strings.TrimSuffix(p.lines[l-1], "\r"))
+}
+
+func (p *parser) quotedScalarEndPos(n *yaml.Node, quote rune) *Pos {
+	start := n.Column // Skip the opening quote
+	for l := n.Line; l <= len(p.lines); l++ {
+		rs := p.line(l)
+		for i := start; i < len(rs); i++ {
+			switch rs[i] {
+			case '\\':
+				if quote == '"' {
+					i++ // Skip the escaped character
+				}
+			case quote:
+				if quote == '\'' && i+1 < len(rs) && rs[i+1] == '\'' {
+					i++ // '' is an escaped single quote
+					continue
+				}
+				return &Pos{l, i + 1}
+			}
+		}
+		start = 0
+	}
+	return nil
+}
+
+func (p *parser) plainScalarEndPos(n *yaml.Node) *Pos {
+	v := []rune(n.Value)
+	if len(v) == 0 {
+		return nil
+	}
+	rs := p.line(n.Line)
+	if n.Column-1 > len(rs) {
+		return nil
+	}
+	rs = rs[n.Column-1:]
+	if len(rs) >= len(v) && string(rs[:len(v)]) == n.Value {
+		return &Pos{n.Line, n.Column + len(v) - 1}
+	}
+
+	// Plain scalar spanning multiple lines. Line breaks are folded into spaces
+	rest := strings.TrimPrefix(n.Value, strings.TrimRight(string(rs), " \t"))
+	if rest == n.Value {
+		return nil
+	}
+	for l := n.Line + 1; l <= len(p.lines); l++ {
+		rest = strings.TrimLeft(rest, " \n")
+		t := strings.TrimSpace(string(p.line(l)))
+		if t == "" {
+			continue
+		}
+		if !strings.HasPrefix(rest, t) {
+			return nil
+		}
+		rest = rest[len(t):]
+		if rest == "" {
+			rs := p.line(l)
+			return &Pos{l, len([]rune(strings.TrimRight(string(rs), " \t")))}
+		}
+	}
+	return nil
+}
+
+func (p *parser) blockScalarEndPos(n *yaml.Node) *Pos {
+	if n.Value != "" {
+		indent, end := -1, 0
+		for l := n.Line + 1; l <= len(p.lines); l++ {
+			rs := p.line(l)
+			t := strings.TrimRight(string(rs), " \t")
+			if t == "" {
+				continue // Empty lines are allowed in the block
+			}
+			i := len(t) - len(strings.TrimLeft(t, " "))
+			if indent < 0 {
+				indent = i
+			} else if i < indent {
+				break
+			}
+			end = l
+		}
+		if end > 0 {
+			t := strings.TrimRight(string(p.line(end)), " \t")
+			return &Pos{end, len([]rune(t))}
+		}
+	}
+
+	// Empty block. The end of the header such as "|-" is the end position
+	rs := p.line(n.Line)
+	i := n.Column - 1
+	for i < len(rs) && rs[i] != ' ' && rs[i] != '\t' && rs[i] != '#' {
+		i++
+	}
+	return &Pos{n.Line, i}
 }
 
 // workflowMappingEntry represents a key-value entry in YAML mapping.
@@ -68,24 +191,32 @@ func (l *delayedSprintf) String() string {
 
 type parser struct {
 	errors []*Error
+	// lines is the source split into lines. It is used for calculating the end positions of nodes
+	// since yaml.Node only has the start positions.
+	lines []string
+	// stringEnds is the end positions of the strings in the workflow indexed by their start positions.
+	stringEnds map[Pos]*Pos
 }
 
-func (p *parser) error(n *yaml.Node, m string) {
-	p.errors = append(p.errors, &Error{m, "", n.Line, n.Column, "syntax-check"})
+func (p *parser) error(n *yaml.Node, code, m string) {
+	p.errorAt(posAt(n), code, m).setEnd(p.endPosOf(n))
 }
 
-func (p *parser) errorAt(pos *Pos, m string) {
-	p.errors = append(p.errors, &Error{m, "", pos.Line, pos.Col, "syntax-check"})
+func (p *parser) errorAt(pos *Pos, code, m string) *Error {
+	err := errorAt(pos, "syntax-check", m)
+	err.Code = "syntax-check/" + code
+	p.errors = append(p.errors, err)
+	return err
 }
 
-func (p *parser) errorfAt(pos *Pos, format string, args ...interface{}) {
+func (p *parser) errorfAt(pos *Pos, code, format string, args ...interface{}) {
 	m := fmt.Sprintf(format, args...)
-	p.errorAt(pos, m)
+	p.errorAt(pos, code, m)
 }
 
-func (p *parser) errorf(n *yaml.Node, format string, args ...interface{}) {
+func (p *parser) errorf(n *yaml.Node, code, format string, args ...interface{}) {
 	m := fmt.Sprintf(format, args...)
-	p.error(n, m)
+	p.error(n, code, m)
 }
 
 func (p *parser) resolveAliases(root *yaml.Node) {
@@ -116,7 +247,7 @@ func (p *parser) resolveAliases(root *yaml.Node) {
 				} else {
 					// Don't resolve the recursive alias because it causes stack overflow on parsing the tree as
 					// `RawYAMLValue`. (#610)
-					p.errorf(c, "recursive alias %q is found. anchor was declared at line:%d, column:%d", c.Alias.Anchor, c.Alias.Line, c.Alias.Column)
+					p.errorf(c, "yaml-anchor", "recursive alias %q is found. anchor was declared at line:%d, column:%d", c.Alias.Anchor, c.Alias.Line, c.Alias.Column)
 				}
 			}
 		}
@@ -128,7 +259,7 @@ func (p *parser) resolveAliases(root *yaml.Node) {
 
 	for n, u := range anchors {
 		if !u.used {
-			p.errorf(n, "anchor %q is defined but not used", n.Anchor)
+			p.errorf(n, "yaml-anchor", "anchor %q is defined but not used", n.Anchor)
 		}
 	}
 }
@@ -146,12 +277,12 @@ func (p *parser) unexpectedKey(s *String, sec string, expected []string) {
 	} else {
 		m = fmt.Sprintf("unexpected key %q for %s", s.Value, sec)
 	}
-	p.errorAt(s.Pos, m)
+	p.errorAt(s.Pos, "unexpected-key", m)
 }
 
 func (p *parser) checkNotEmpty(sec string, len int, n *yaml.Node) bool {
 	if len == 0 {
-		p.errorf(n, "%q section should not be empty", sec)
+		p.errorf(n, "empty-value", "%q section should not be empty", sec)
 		return false
 	}
 	return true
@@ -159,7 +290,7 @@ func (p *parser) checkNotEmpty(sec string, len int, n *yaml.Node) bool {
 
 func (p *parser) checkSequence(sec string, n *yaml.Node, allowEmpty bool) bool {
 	if n.Kind != yaml.SequenceNode {
-		p.errorf(n, "%q section must be sequence node but got %s node with %q tag", sec, nodeKindName(n.Kind), n.Tag)
+		p.errorf(n, "unexpected-node", "%q section must be sequence node but got %s node with %q tag", sec, nodeKindName(n.Kind), n.Tag)
 		return false
 	}
 	return allowEmpty || p.checkNotEmpty(sec, len(n.Content), n)
@@ -169,18 +300,18 @@ func (p *parser) checkString(n *yaml.Node, allowEmpty bool) bool {
 	// Do not check n.Tag is !!str because we don't need to check the node is string strictly.
 	// In almost all cases, other nodes (like 42) are handled as string with its string representation.
 	if n.Kind != yaml.ScalarNode {
-		p.errorf(n, "expected scalar node for string value but found %s node with %q tag", nodeKindName(n.Kind), n.Tag)
+		p.errorf(n, "unexpected-node", "expected scalar node for string value but found %s node with %q tag", nodeKindName(n.Kind), n.Tag)
 		return false
 	}
 	if !allowEmpty && n.Value == "" {
-		p.error(n, "string should not be empty")
+		p.error(n, "empty-value", "string should not be empty")
 		return false
 	}
 	return true
 }
 
 func (p *parser) missingExpression(n *yaml.Node, expecting string) {
-	p.errorf(n, "expecting a single ${{...}} expression or %s, but found plain text node", expecting)
+	p.errorf(n, "expression-expected", "expecting a single ${{...}} expression or %s, but found plain text node", expecting)
 }
 
 func (p *parser) parseExpression(n *yaml.Node, expecting string) *String {
@@ -188,7 +319,7 @@ func (p *parser) parseExpression(n *yaml.Node, expecting string) *String {
 		p.missingExpression(n, expecting)
 		return nil
 	}
-	return newString(n)
+	return p.newString(n)
 }
 
 func (p *parser) mayParseExpression(n *yaml.Node) *String {
@@ -198,14 +329,14 @@ func (p *parser) mayParseExpression(n *yaml.Node) *String {
 	if !isExprAssigned(n.Value) {
 		return nil
 	}
-	return newString(n)
+	return p.newString(n)
 }
 
 func (p *parser) parseString(n *yaml.Node, allowEmpty bool) *String {
 	if !p.checkString(n, allowEmpty) {
-		return &String{"", false, posAt(n)}
+		return &String{"", false, false, posAt(n), nil}
 	}
-	return newString(n)
+	return p.newString(n)
 }
 
 func (p *parser) parseStringSequence(sec string, n *yaml.Node, allowEmpty bool, allowElemEmpty bool) []*String {
@@ -237,7 +368,7 @@ func (p *parser) parseStringOrStringSequence(sec string, n *yaml.Node, allowEmpt
 
 func (p *parser) parseBool(n *yaml.Node) *Bool {
 	if n.Kind != yaml.ScalarNode || (n.Tag != "!!bool" && n.Tag != "!!str") {
-		p.errorf(n, "expected bool value but found %s node with %q tag", nodeKindName(n.Kind), n.Tag)
+		p.errorf(n, "unexpected-node", "expected bool value but found %s node with %q tag", nodeKindName(n.Kind), n.Tag)
 		return nil
 	}
 
@@ -257,7 +388,7 @@ func (p *parser) parseBool(n *yaml.Node) *Bool {
 
 func (p *parser) parseInt(n *yaml.Node) *Int {
 	if n.Kind != yaml.ScalarNode || (n.Tag != "!!int" && n.Tag != "!!str") {
-		p.errorf(n, "expected scalar node for integer value but found %s node with %q tag", nodeKindName(n.Kind), n.Tag)
+		p.errorf(n, "unexpected-node", "expected scalar node for integer value but found %s node with %q tag", nodeKindName(n.Kind), n.Tag)
 		return nil
 	}
 
@@ -274,7 +405,7 @@ func (p *parser) parseInt(n *yaml.Node) *Int {
 
 	i, err := strconv.Atoi(n.Value)
 	if err != nil {
-		p.errorf(n, "invalid integer value: %q: %s", n.Value, err.Error())
+		p.errorf(n, "invalid-value", "invalid integer value: %q: %s", n.Value, err.Error())
 		return nil
 	}
 
@@ -286,7 +417,7 @@ func (p *parser) parseInt(n *yaml.Node) *Int {
 
 func (p *parser) parseFloat(n *yaml.Node) *Float {
 	if n.Kind != yaml.ScalarNode || (n.Tag != "!!float" && n.Tag != "!!int" && n.Tag != "!!str") {
-		p.errorf(n, "expected scalar node for float value but found %s node with %q tag", nodeKindName(n.Kind), n.Tag)
+		p.errorf(n, "unexpected-node", "expected scalar node for float value but found %s node with %q tag", nodeKindName(n.Kind), n.Tag)
 		return nil
 	}
 
@@ -303,7 +434,7 @@ func (p *parser) parseFloat(n *yaml.Node) *Float {
 
 	f, err := strconv.ParseFloat(n.Value, 64)
 	if err != nil || math.IsNaN(f) {
-		p.errorf(n, "invalid float value: %q: %s", n.Value, err.Error())
+		p.errorf(n, "invalid-value", "invalid float value: %q: %s", n.Value, err.Error())
 		return nil
 	}
 
@@ -317,13 +448,13 @@ func (p *parser) parseMapping(where delayedSprintf, n *yaml.Node, allowEmpty, ca
 	return func(yield func(workflowMappingEntry) bool) {
 		if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
 			if !allowEmpty {
-				p.errorf(n, "%s should not be empty. please remove this section if it's unnecessary", where.String())
+				p.errorf(n, "empty-value", "%s should not be empty. please remove this section if it's unnecessary", where.String())
 			}
 			return
 		}
 
 		if n.Kind != yaml.MappingNode {
-			p.errorf(n, "%s is %s node but mapping node is expected", where.String(), nodeKindName(n.Kind))
+			p.errorf(n, "unexpected-node", "%s is %s node but mapping node is expected", where.String(), nodeKindName(n.Kind))
 			return
 		}
 
@@ -333,7 +464,7 @@ func (p *parser) parseMapping(where delayedSprintf, n *yaml.Node, allowEmpty, ca
 			k := p.parseString(n.Content[i], false)
 
 			if k.Value == "<<" {
-				p.errorAt(k.Pos, "GitHub Actions does not support YAML merge key \"<<\"")
+				p.errorAt(k.Pos, "merge-key", "GitHub Actions does not support YAML merge key \"<<\"")
 				continue
 			}
 
@@ -352,7 +483,8 @@ func (p *parser) parseMapping(where delayedSprintf, n *yaml.Node, allowEmpty, ca
 				if !caseSensitive {
 					note = ". note that this key is case insensitive"
 				}
-				p.errorfAt(k.Pos, "key %q is duplicated in %s. previously defined at %s%s", k.Value, where.String(), pos.String(), note)
+				p.errorfAt(k.Pos, "duplicate-key", "key %q is duplicated in %s. previously defined at %s%s", k.Value, where.String(), pos.String(), note)
+				p.errors[len(p.errors)-1].Related = []RelatedLocation{relatedAt(pos, fmt.Sprintf("key %q is previously defined here", k.Value))}
 				continue
 			}
 
@@ -365,7 +497,7 @@ func (p *parser) parseMapping(where delayedSprintf, n *yaml.Node, allowEmpty, ca
 		}
 
 		if !allowEmpty && empty {
-			p.errorf(n, "%s should not be empty. please remove this section if it's unnecessary", where.String())
+			p.errorf(n, "empty-value", "%s should not be empty. please remove this section if it's unnecessary", where.String())
 		}
 	}
 }
@@ -436,7 +568,7 @@ func (p *parser) parseWorkflowDispatchEventInput(name *String, n *yaml.Node) *Di
 			case "environment":
 				ret.Type = WorkflowDispatchEventInputTypeEnvironment
 			default:
-				p.errorf(e.val, `input type of workflow_dispatch event must be one of "string", "number", "boolean", "choice", "environment" but got %q`, e.val.Value)
+				p.errorf(e.val, "invalid-value", `input type of workflow_dispatch event must be one of "string", "number", "boolean", "choice", "environment" but got %q`, e.val.Value)
 			}
 		case "options":
 			ret.Options = p.parseStringSequence("options", e.val, false, false)
@@ -564,7 +696,7 @@ func (p *parser) parseWorkflowCallEventInput(id string, name *String, n *yaml.No
 			case "string":
 				ret.Type = WorkflowCallEventInputTypeString
 			default:
-				p.errorf(e.val, "invalid value %q for input type of workflow_call event. it must be one of \"boolean\", \"number\", or \"string\"", e.val.Value)
+				p.errorf(e.val, "invalid-value", "invalid value %q for input type of workflow_call event. it must be one of \"boolean\", \"number\", or \"string\"", e.val.Value)
 			}
 		default:
 			p.unexpectedKey(e.key, "inputs at workflow_call event", []string{"description", "required", "default", "type"})
@@ -572,7 +704,7 @@ func (p *parser) parseWorkflowCallEventInput(id string, name *String, n *yaml.No
 	}
 
 	if !typed {
-		p.errorfAt(name.Pos, "\"type\" is missing at %q input of workflow_call event", name.Value)
+		p.errorfAt(name.Pos, "missing-key", "\"type\" is missing at %q input of workflow_call event", name.Value)
 	}
 
 	return ret
@@ -612,7 +744,7 @@ func (p *parser) parseWorkflowCallEventOutput(name *String, n *yaml.Node) *Workf
 	}
 
 	if output.Value == nil {
-		p.errorfAt(name.Pos, "\"value\" is missing at %q output of workflow_call event", name.Value)
+		p.errorfAt(name.Pos, "missing-key", "\"value\" is missing at %q output of workflow_call event", name.Value)
 	}
 
 	return output
@@ -672,7 +804,7 @@ func (p *parser) parseEventWithNoConfig(n *yaml.Node) Event {
 	case "":
 		return nil
 	case "schedule":
-		p.error(n, "schedule event must be configured with mapping")
+		p.error(n, "unexpected-node", "schedule event must be configured with mapping")
 		return nil
 	case "repository_dispatch":
 		return &RepositoryDispatchEvent{Pos: posAt(n)}
@@ -730,7 +862,7 @@ func (p *parser) parseEvents(n *yaml.Node) []Event {
 
 		return ret
 	default:
-		p.errorf(n, "\"on\" section value is expected to be mapping or sequence but found %s node", nodeKindName(n.Kind))
+		p.errorf(n, "unexpected-node", "\"on\" section value is expected to be mapping or sequence but found %s node", nodeKindName(n.Kind))
 		return nil
 	}
 }
@@ -799,7 +931,7 @@ func (p *parser) parseDefaults(pos *Pos, n *yaml.Node) *Defaults {
 	}
 
 	if ret.Run == nil {
-		p.error(n, "\"defaults\" section should have \"run\" section")
+		p.error(n, "missing-key", "\"defaults\" section should have \"run\" section")
 	}
 
 	return ret
@@ -825,7 +957,7 @@ func (p *parser) parseConcurrency(pos *Pos, n *yaml.Node) *Concurrency {
 		}
 	}
 	if ret.Group == nil {
-		p.errorAt(pos, "group name is missing in \"concurrency\" section")
+		p.errorAt(pos, "missing-key", "group name is missing in \"concurrency\" section")
 	}
 	return ret
 }
@@ -852,7 +984,7 @@ func (p *parser) parseEnvironment(pos *Pos, n *yaml.Node) *Environment {
 		}
 	}
 	if ret.Name == nil {
-		p.errorAt(pos, "name is missing in \"environment\" section")
+		p.errorAt(pos, "missing-key", "name is missing in \"environment\" section")
 	}
 	return ret
 }
@@ -891,7 +1023,7 @@ func (p *parser) parseRawYAMLValue(n *yaml.Node) RawYAMLValue {
 		}
 		return &RawYAMLObject{m, posAt(n)}
 	default:
-		p.errorf(n, "unexpected %s node on parsing value in matrix row", nodeKindName(n.Kind))
+		p.errorf(n, "unexpected-node", "unexpected %s node on parsing value in matrix row", nodeKindName(n.Kind))
 		return nil
 	}
 }
@@ -979,7 +1111,7 @@ func (p *parser) parseMatrix(pos *Pos, n *yaml.Node) *Matrix {
 func (p *parser) parseMaxParallel(n *yaml.Node) *Int {
 	i := p.parseInt(n)
 	if i != nil && i.Expression == nil && i.Value <= 0 {
-		p.errorf(n, "value at \"max-parallel\" must be greater than zero: %v", i.Value)
+		p.errorf(n, "invalid-value", "value at \"max-parallel\" must be greater than zero: %v", i.Value)
 	}
 	return i
 }
@@ -1024,7 +1156,7 @@ func (p *parser) parseCredentials(pos *Pos, n *yaml.Node) *Credentials {
 	}
 
 	if ret.Username == nil || ret.Password == nil {
-		p.errorAt(pos, "both \"username\" and \"password\" must be specified in \"credentials\" section")
+		p.errorAt(pos, "missing-key", "both \"username\" and \"password\" must be specified in \"credentials\" section")
 		return nil
 	}
 
@@ -1084,7 +1216,7 @@ func (p *parser) parseContainer(sec string, pos *Pos, n *yaml.Node) *Container {
 	}
 
 	if ret.Image == nil {
-		p.errorfAt(pos, "\"image\" is missing in %q section", sec)
+		p.errorfAt(pos, "missing-key", "\"image\" is missing in %q section", sec)
 	}
 
 	return ret
@@ -1112,7 +1244,7 @@ func (p *parser) parseServices(n *yaml.Node) *Services {
 func (p *parser) parseTimeoutMinutes(n *yaml.Node) *Float {
 	f := p.parseFloat(n)
 	if f != nil && f.Expression == nil && f.Value <= 0.0 {
-		p.errorf(n, "value at \"timeout-minutes\" must be greater than zero: %v", f.Value)
+		p.errorf(n, "invalid-value", "value at \"timeout-minutes\" must be greater than zero: %v", f.Value)
 	}
 	return f
 }
@@ -1243,7 +1375,7 @@ func (p *parser) parseStep(n *yaml.Node) *Step {
 	case isRun:
 		ret.Exec = p.parseStepExecRun(entries)
 	default:
-		p.error(n, "step must run script with \"run\" section or run action with \"uses\" section")
+		p.error(n, "missing-key", "step must run script with \"run\" section or run action with \"uses\" section")
 	}
 
 	return ret
@@ -1315,11 +1447,11 @@ func (p *parser) parseSnapshot(pos *Pos, n *yaml.Node) *Snapshot {
 			}
 		}
 		if ret.ImageName == nil {
-			p.errorAt(pos, "\"snapshot\" section must have \"image-name\" configuration")
+			p.errorAt(pos, "missing-key", "\"snapshot\" section must have \"image-name\" configuration")
 		}
 		return ret
 	default:
-		p.errorf(n, "\"snapshot\" section value must be string or mapping but found %s node", nodeKindName(n.Kind))
+		p.errorf(n, "unexpected-node", "\"snapshot\" section value must be string or mapping but found %s node", nodeKindName(n.Kind))
 		return nil
 	}
 }
@@ -1414,7 +1546,7 @@ func (p *parser) parseJob(id *String, n *yaml.Node) *Job {
 				if e.val.Value == "inherit" {
 					call.InheritSecrets = true
 				} else {
-					p.errorf(e.val, "expected mapping node for secrets or \"inherit\" string node but found %q node", e.val.Value)
+					p.errorf(e.val, "unexpected-node", "expected mapping node for secrets or \"inherit\" string node but found %q node", e.val.Value)
 				}
 			} else {
 				call.Secrets = map[string]*WorkflowCallSecret{}
@@ -1458,6 +1590,7 @@ func (p *parser) parseJob(id *String, n *yaml.Node) *Job {
 		if stepsOnlyKey != nil {
 			p.errorfAt(
 				stepsOnlyKey.Pos,
+				"unexpected-key",
 				"when a reusable workflow is called with \"uses\", %q is not available. only following keys are allowed: \"name\", \"uses\", \"with\", \"secrets\", \"needs\", \"if\", and \"permissions\" in job %q",
 				stepsOnlyKey.Value,
 				id.Value,
@@ -1468,14 +1601,15 @@ func (p *parser) parseJob(id *String, n *yaml.Node) *Job {
 	} else {
 		// When not a reusable call
 		if ret.Steps == nil {
-			p.errorfAt(id.Pos, "\"steps\" section is missing in job %q", id.Value)
+			p.errorfAt(id.Pos, "missing-key", "\"steps\" section is missing in job %q", id.Value)
 		}
 		if ret.RunsOn == nil {
-			p.errorfAt(id.Pos, "\"runs-on\" section is missing in job %q", id.Value)
+			p.errorfAt(id.Pos, "missing-key", "\"runs-on\" section is missing in job %q", id.Value)
 		}
 		if callOnlyKey != nil {
 			p.errorfAt(
 				callOnlyKey.Pos,
+				"unexpected-key",
 				"%q is only available for a reusable workflow call with \"uses\" but \"uses\" is not found in job %q",
 				callOnlyKey.Value,
 				id.Value,
@@ -1509,7 +1643,7 @@ func (p *parser) parse(n *yaml.Node) *Workflow {
 	}
 
 	if len(n.Content) == 0 {
-		p.error(n, "workflow is empty")
+		p.error(n, "empty-value", "workflow is empty")
 		return w
 	}
 
@@ -1547,10 +1681,10 @@ func (p *parser) parse(n *yaml.Node) *Workflow {
 	}
 
 	if w.On == nil {
-		p.error(n, "\"on\" section is missing in workflow")
+		p.error(n, "missing-key", "\"on\" section is missing in workflow")
 	}
 	if w.Jobs == nil {
-		p.error(n, "\"jobs\" section is missing in workflow")
+		p.error(n, "missing-key", "\"jobs\" section is missing in workflow")
 	}
 
 	return w
@@ -1572,6 +1706,7 @@ func handleYAMLUnmarshalError(err error) []*Error {
 				Line:    e.Line,
 				Column:  e.Column,
 				Kind:    "syntax-check",
+				Code:    "syntax-check/yaml-error",
 			})
 		}
 		return errs
@@ -1590,6 +1725,7 @@ func handleYAMLUnmarshalError(err error) []*Error {
 	return []*Error{&Error{
 		Message: fmt.Sprintf("could not parse as YAML: %s", m),
 		Kind:    "syntax-check",
+		Code:    "syntax-check/yaml-error",
 		Line:    l,
 		Column:  c,
 	}}
@@ -1599,17 +1735,25 @@ func handleYAMLUnmarshalError(err error) []*Error {
 // detected while parsing the input. It means that detecting one error does not stop parsing. Even
 // if one or more errors are detected, parser will try to continue parsing and finding more errors.
 func Parse(b []byte) (*Workflow, []*Error) {
+	w, _, errs := parseWorkflow(b)
+	return w, errs
+}
+
+// parseWorkflow is the same as Parse but it also returns the end positions of the strings in the
+// workflow indexed by their start positions. They are used for setting the end positions of errors
+// reported by rules.
+func parseWorkflow(b []byte) (*Workflow, map[Pos]*Pos, []*Error) {
 	var n yaml.Node
 
 	if err := yaml.Unmarshal(b, &n); err != nil {
-		return nil, handleYAMLUnmarshalError(err)
+		return nil, nil, handleYAMLUnmarshalError(err)
 	}
 
 	// Uncomment for checking YAML tree
 	// dumpYAML(&n, 0)
 
-	p := &parser{}
+	p := &parser{lines: strings.Split(string(b), "\n"), stringEnds: map[Pos]*Pos{}}
 	w := p.parse(&n)
 
-	return w, p.errors
+	return w, p.stringEnds, p.errors
 }
@@ -72,3 +72,41 @@ func BenchmarkParseTestData(b *testing.B) {
 		})
 	}
 }
+
+func TestParseStringEndPos(t *testing.T) {
+	testCases := []struct {
+		what  string
+		input string
+		want  *Pos
+	}{
+		{"plain", "name: hello world", &Pos{1, 17}},
+		{"plain with comment", "name: hello # comment", &Pos{1, 11}},
+		{"plain with multi-byte characters", "name: こんにちは", &Pos{1, 11}},
+		{"plain in multiple lines", "name: hello\n  world\n  foo", &Pos{3, 5}},
+		{"single quoted", "name: 'hello'", &Pos{1, 13}},
+		{"single quoted with escape", "name: 'it''s'  # comment", &Pos{1, 13}},
+		{"double quoted", `name: "hello"`, &Pos{1, 13}},
+		{"double quoted with escape", `name: "a\"b\\"`, &Pos{1, 14}},
+		{"double quoted in multiple lines", "name: \"hello\n  world\"", &Pos{2, 8}},
+		{"literal block", "name: |\n  hello\n\n  world  \nfoo: bar", &Pos{4, 7}},
+		{"folded block", "name: >-\n  hello\n    world\n", &Pos{3, 9}},
+		{"empty block", "name: |-\nfoo: bar", &Pos{1, 8}},
+		{"CRLF", "name: 'hello'\r\nfoo: bar\r\n", &Pos{1, 13}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			w, _ := Parse([]byte(tc.input))
+			if w == nil || w.Name == nil {
+				t.Fatalf("name was not parsed: %#v", w)
+			}
+			have := w.Name.EndPos
+			if have == nil {
+				t.Fatalf("end position is nil. wanted %s", tc.want)
+			}
+			if *have != *tc.want {
+				t.Fatalf("wanted %s but have %s", tc.want, have)
+			}
+		})
+	}
+}
@@ -3,7 +3,11 @@ package actionlint
 import (
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // Pass is an interface to traverse a workflow syntax tree
@@ -20,10 +24,31 @@ type Pass interface {
 	VisitWorkflowPost(node *Workflow) error
 }
 
+// JobLocalPass is a Pass whose callbacks for a job depend only on the job and the state set in
+// VisitWorkflowPre. Visitor can visit jobs concurrently for such passes. Each job is visited by a
+// copy of the pass created by ForkJob, and the copy is merged into the original pass by JoinJob.
+type JobLocalPass interface {
+	Pass
+	// ForkJob creates a copy of the pass to visit one job. VisitJobPre, VisitStep, and VisitJobPost
+	// of the copy are called for the job. This method is called after VisitWorkflowPre.
+	ForkJob() Pass
+	// JoinJob merges the result of the copy created by ForkJob into this pass after the job was
+	// visited. This method is called in the order of job IDs before VisitWorkflowPost.
+	JoinJob(fork Pass)
+}
+
+// visitorParallelJobsThreshold is the minimum number of jobs to visit jobs concurrently. Visiting
+// a few jobs concurrently is slower due to the overhead of forking passes.
+const visitorParallelJobsThreshold = 16
+
 // Visitor visits syntax tree from root in depth-first order
 type Visitor struct {
 	passes []Pass
 	dbg    io.Writer
+	// par is the maximum number of jobs visited concurrently. 0 means jobs are visited serially.
+	par int
+	// elapsed is the total time spent by each pass. It is nil when profiling is disabled.
+	elapsed []time.Duration
 }
 
 // NewVisitor creates Visitor instance
@@ -42,6 +67,39 @@ func (v *Visitor) EnableDebug(w io.Writer) {
 	v.dbg = w
 }
 
+// EnableParallel enables visiting jobs concurrently for passes which implement JobLocalPass
+// interface. The par parameter is the maximum number of jobs visited at once. Other passes visit
+// jobs serially and all passes visit the workflow node in the order of adding them. Jobs are
+// visited concurrently only when the workflow has many jobs.
+func (v *Visitor) EnableParallel(par int) {
+	v.par = par
+}
+
+// enableProfile enables measuring the time spent by each pass. Call this method after adding all
+// passes. The measured times are returned from passElapsedTimes method.
+func (v *Visitor) enableProfile() {
+	v.elapsed = make([]time.Duration, len(v.passes))
+}
+
+// passElapsedTimes returns the time spent by each pass in the same order as the passes were added.
+func (v *Visitor) passElapsedTimes() []time.Duration {
+	return v.elapsed
+}
+
+// startPass returns the start time of calling a pass when profiling is enabled.
+func (v *Visitor) startPass() time.Time {
+	if v.elapsed == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+func (v *Visitor) endPass(i int, start time.Time) {
+	if v.elapsed != nil {
+		v.elapsed[i] += time.Since(start)
+	}
+}
+
 func (v *Visitor) reportElapsedTime(what string, start time.Time) {
 	fmt.Fprintf(v.dbg, "[Visitor] %s took %vms\n", what, time.Since(start).Milliseconds())
 }
@@ -53,10 +111,12 @@ func (v *Visitor) Visit(n *Workflow) error {
 		t = time.Now()
 	}
 
-	for _, p := range v.passes {
+	for i, p := range v.passes {
+		s := v.startPass()
 		if err := p.VisitWorkflowPre(n); err != nil {
 			return err
 		}
+		v.endPass(i, s)
 	}
 
 	if v.dbg != nil {
@@ -64,10 +124,8 @@ func (v *Visitor) Visit(n *Workflow) error {
 		t = time.Now()
 	}
 
-	for _, j := range n.Jobs {
-		if err := v.visitJob(j); err != nil {
-			return err
-		}
+	if err := v.visitJobs(n.Jobs); err != nil {
+		return err
 	}
 
 	if v.dbg != nil {
@@ -75,10 +133,12 @@ func (v *Visitor) Visit(n *Workflow) error {
 		t = time.Now()
 	}
 
-	for _, p := range v.passes {
+	for i, p := range v.passes {
+		s := v.startPass()
 		if err := p.VisitWorkflowPost(n); err != nil {
 			return err
 		}
+		v.endPass(i, s)
 	}
 
 	if v.dbg != nil {
@@ -88,16 +148,106 @@ func (v *Visitor) Visit(n *Workflow) error {
 	return nil
 }
 
+func (v *Visitor) visitJobs(jobs map[string]*Job) error {
+	var local, serial []int
+	if v.par > 0 && len(jobs) >= visitorParallelJobsThreshold {
+		for i, p := range v.passes {
+			if _, ok := p.(JobLocalPass); ok {
+				local = append(local, i)
+			} else {
+				serial = append(serial, i)
+			}
+		}
+	}
+
+	if len(local) == 0 {
+		for _, j := range jobs {
+			if err := v.visitJob(j); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if v.dbg != nil {
+		fmt.Fprintf(v.dbg, "[Visitor] Visiting %d jobs in parallel with %d job-local passes\n", len(jobs), len(local))
+	}
+
+	ids := slices.Sorted(maps.Keys(jobs))
+	forks := make([]*Visitor, len(ids))
+	eg := errgroup.Group{}
+	eg.SetLimit(v.par)
+	for i, id := range ids {
+		f := v.subVisitor(local)
+		for k, p := range f.passes {
+			f.passes[k] = p.(JobLocalPass).ForkJob()
+		}
+		forks[i] = f
+		eg.Go(func() error {
+			return f.visitJob(jobs[id])
+		})
+	}
+
+	// Passes which are not job-local visit all jobs in this goroutine while the forks are running
+	s := v.subVisitor(serial)
+	s.dbg = v.dbg
+	for _, id := range ids {
+		if err := s.visitJob(jobs[id]); err != nil {
+			eg.Wait()
+			return err
+		}
+	}
+	v.mergeElapsed(s, serial)
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	for _, f := range forks {
+		for k, i := range local {
+			v.passes[i].(JobLocalPass).JoinJob(f.passes[k])
+		}
+		v.mergeElapsed(f, local)
+	}
+
+	return nil
+}
+
+// subVisitor creates a visitor with the subset of the passes at the indices. Its elapsed times are
+// merged with mergeElapsed method.
+func (v *Visitor) subVisitor(indices []int) *Visitor {
+	ps := make([]Pass, 0, len(indices))
+	for _, i := range indices {
+		ps = append(ps, v.passes[i])
+	}
+	sub := &Visitor{passes: ps}
+	if v.elapsed != nil {
+		sub.enableProfile()
+	}
+	return sub
+}
+
+func (v *Visitor) mergeElapsed(sub *Visitor, indices []int) {
+	if v.elapsed == nil {
+		return
+	}
+	for k, i := range indices {
+		v.elapsed[i] += sub.elapsed[k]
+	}
+}
+
 func (v *Visitor) visitJob(n *Job) error {
 	var t time.Time
 	if v.dbg != nil {
 		t = time.Now()
 	}
 
-	for _, p := range v.passes {
+	for i, p := range v.passes {
+		s := v.startPass()
 		if err := p.VisitJobPre(n); err != nil {
 			return err
 		}
+		v.endPass(i, s)
 	}
 
 	if v.dbg != nil {
@@ -116,10 +266,12 @@ func (v *Visitor) visitJob(n *Job) error {
 		t = time.Now()
 	}
 
-	for _, p := range v.passes {
+	for i, p := range v.passes {
+		s := v.startPass()
 		if err := p.VisitJobPost(n); err != nil {
 			return err
 		}
+		v.endPass(i, s)
 	}
 
 	if v.dbg != nil {
@@ -135,10 +287,12 @@ func (v *Visitor) visitStep(n *Step) error {
 		t = time.Now()
 	}
 
-	for _, p := range v.passes {
+	for i, p := range v.passes {
+		s := v.startPass()
 		if err := p.VisitStep(n); err != nil {
 			return err
 		}
+		v.endPass(i, s)
 	}
 
 	if v.dbg != nil {
@@ -0,0 +1,187 @@
+package actionlint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testRecordPass records the callbacks called on the pass.
+type testRecordPass struct {
+	mu    *sync.Mutex // Shared with forks
+	calls []string
+}
+
+func (p *testRecordPass) record(s string) error {
+	p.mu.Lock()
+	p.calls = append(p.calls, s)
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *testRecordPass) VisitStep(n *Step) error           { return p.record("step " + n.Name.Value) }
+func (p *testRecordPass) VisitJobPre(n *Job) error          { return p.record("job-pre " + n.ID.Value) }
+func (p *testRecordPass) VisitJobPost(n *Job) error         { return p.record("job-post " + n.ID.Value) }
+func (p *testRecordPass) VisitWorkflowPre(*Workflow) error  { return p.record("workflow-pre") }
+func (p *testRecordPass) VisitWorkflowPost(*Workflow) error { return p.record("workflow-post") }
+
+// testJobLocalPass is a testRecordPass which implements JobLocalPass interface.
+type testJobLocalPass struct {
+	testRecordPass
+}
+
+func (p *testJobLocalPass) ForkJob() Pass {
+	return &testJobLocalPass{testRecordPass{mu: p.mu}}
+}
+
+func (p *testJobLocalPass) JoinJob(fork Pass) {
+	f := fork.(*testJobLocalPass)
+	p.calls = append(p.calls, f.calls...)
+}
+
+func testWorkflowWithJobs(n int) *Workflow {
+	w := &Workflow{Jobs: map[string]*Job{}}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("job%02d", i)
+		w.Jobs[id] = &Job{
+			ID:    &String{Value: id, Pos: &Pos{}},
+			Steps: []*Step{{Name: &String{Value: id + "-step", Pos: &Pos{}}, Pos: &Pos{}}},
+		}
+	}
+	return w
+}
+
+func TestVisitorParallelJobs(t *testing.T) {
+	const numJobs = visitorParallelJobsThreshold + 4
+	mu := &sync.Mutex{}
+	local := &testJobLocalPass{testRecordPass{mu: mu}}
+	serial := &testRecordPass{mu: mu}
+
+	v := NewVisitor()
+	v.AddPass(local)
+	v.AddPass(serial)
+	v.EnableParallel(4)
+	v.enableProfile()
+	if err := v.Visit(testWorkflowWithJobs(numJobs)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"workflow-pre"}
+	for i := 0; i < numJobs; i++ {
+		id := fmt.Sprintf("job%02d", i)
+		want = append(want, "job-pre "+id, "step "+id+"-step", "job-post "+id)
+	}
+	want = append(want, "workflow-post")
+
+	// Forks are joined in the order of job IDs
+	if !slices.Equal(local.calls, want) {
+		t.Errorf("job-local pass was called in unexpected order:\n%s", strings.Join(local.calls, "\n"))
+	}
+	// Passes which are not job-local visit jobs serially in the order of job IDs
+	if !slices.Equal(serial.calls, want) {
+		t.Errorf("serial pass was called in unexpected order:\n%s", strings.Join(serial.calls, "\n"))
+	}
+	if len(v.passElapsedTimes()) != 2 {
+		t.Errorf("elapsed times of passes were not recorded: %v", v.passElapsedTimes())
+	}
+}
+
+func TestVisitorParallelJobsFewJobs(t *testing.T) {
+	p := &testJobLocalPass{testRecordPass{mu: &sync.Mutex{}}}
+	v := NewVisitor()
+	v.AddPass(p)
+	v.EnableParallel(4)
+	if err := v.Visit(testWorkflowWithJobs(2)); err != nil {
+		t.Fatal(err)
+	}
+	// Forks are not created when the number of jobs is under the threshold
+	if len(p.calls) != 8 {
+		t.Fatalf("all callbacks should be called on the pass itself: %v", p.calls)
+	}
+}
+
+func TestVisitorParallelJobsSameErrors(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("on: push\njobs:\n")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&b, `  job%d:
+    runs-on: ubuntu-latest-%d
+    env:
+      'invalid name': foo
+    steps:
+      - run: echo ${{ unknown_context_%d }}
+      - run: echo ::set-output name=foo::bar
+      - uses: actions/checkout@v4
+        with:
+          unknown-input: foo
+`, i, i, i)
+	}
+	w, errs := Parse([]byte(b.String()))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	check := func(par int) []string {
+		rules := newBuiltinRules("test.yaml", nil, nil, nil)
+		v := NewVisitor()
+		for _, r := range rules {
+			v.AddPass(r)
+		}
+		v.EnableParallel(par)
+		if err := v.Visit(w); err != nil {
+			t.Fatal(err)
+		}
+		var es []*Error
+		for _, r := range rules {
+			es = append(es, r.Errs()...)
+		}
+		slices.SortFunc(es, compareErrors)
+		ret := make([]string, 0, len(es))
+		for _, e := range es {
+			ret = append(ret, e.Error())
+		}
+		return ret
+	}
+
+	want := check(0)
+	if len(want) < 200 {
+		t.Fatalf("too few errors were found in serial check: %d", len(want))
+	}
+	for i := 0; i < 3; i++ {
+		have := check(8)
+		if !slices.Equal(have, want) {
+			t.Fatalf("errors found by parallel check are different from serial check:\n%s\n---\n%s", strings.Join(have, "\n"), strings.Join(want, "\n"))
+		}
+	}
+}
+
+func TestBuiltinRulesJobLocal(t *testing.T) {
+	want := []string{
+		"action",
+		"credentials",
+		"deprecated-commands",
+		"env-file",
+		"env-var",
+		"expression",
+		"glob",
+		"id-token",
+		"if-cond",
+		"matrix",
+		"permissions",
+		"runner-label",
+		"shell-name",
+		"workflow-call",
+	}
+	have := []string{}
+	for _, r := range newBuiltinRules("test.yaml", nil, nil, nil) {
+		if _, ok := r.(JobLocalPass); ok {
+			have = append(have, r.Name())
+		}
+	}
+	slices.Sort(have)
+	if !slices.Equal(have, want) {
+		t.Fatalf("wanted job-local rules %v but got %v", want, have)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mattn/go-shellwords"
 	"golang.org/x/sync/errgroup"
@@ -73,6 +74,8 @@ type concurrentProcess struct {
 	ctx  context.Context
 	sema *semaphore.Weighted
 	wg   sync.WaitGroup
+	// prof collects the elapsed time of each execution when it is not nil.
+	prof *profiler
 }
 
 // newConcurrentProcess creates a new ConcurrentProcess instance. The `par` argument represents how
@@ -92,7 +95,11 @@ func (proc *concurrentProcess) run(eg *errgroup.Group, exec *cmdExecution, callb
 		if err := proc.sema.Acquire(proc.ctx, 1); err != nil {
 			return fmt.Errorf("could not acquire semaphore to run %q: %w", exec.cmd, err)
 		}
+		start := time.Now()
 		stdout, err := exec.run()
+		if proc.prof != nil {
+			proc.prof.addCommand(exec.cmd, time.Since(start))
+		}
 		proc.sema.Release(1)
 		return callback(stdout, err)
 	})
@@ -167,3 +174,23 @@ func (cmd *externalCommand) run(args []string, stdin string, callback func([]byt
 func (cmd *externalCommand) wait() error {
 	return cmd.eg.Wait()
 }
+
+// CommandRunner runs an external command from a rule. Processes are run concurrently and the number
+// of processes running at once is bounded across all rules and all files checked by Linter. An
+// instance can be created with RuleContext.NewCommandRunner.
+type CommandRunner struct {
+	cmd *externalCommand
+}
+
+// Run runs the command asynchronously with the arguments and the stdin. The callback is called
+// after the process exits with its stdout and an error while running the process. The callback may
+// be called from other goroutines. An error returned from the callback is reported by Wait method.
+func (r *CommandRunner) Run(args []string, stdin string, callback func(stdout []byte, err error) error) {
+	r.cmd.run(args, stdin, callback)
+}
+
+// Wait waits until all processes run by this instance finish and returns the first error returned
+// from the callbacks. Call this method in VisitWorkflowPost of the rule.
+func (r *CommandRunner) Wait() error {
+	return r.cmd.wait()
+}
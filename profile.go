@@ -0,0 +1,203 @@
+package actionlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ProfileEntry is the time spent by one rule, on one file, or by one external command while
+// linting.
+type ProfileEntry struct {
+	// Name is a rule name, a file path, or an external command name.
+	Name string `json:"name"`
+	// Count is the number of checked files for a rule, always 1 for a file, and the number of
+	// executions for an external command.
+	Count int `json:"count"`
+	// Elapsed is the total elapsed time.
+	Elapsed time.Duration `json:"elapsed_ns"`
+}
+
+// Profile is the timing data collected while linting. Entries in each field are sorted by the
+// elapsed time in descending order so that slow rules, files, and commands come first.
+// Note that the elapsed time of a rule includes the time waiting for external commands and the
+// elapsed times of files checked in parallel overlap.
+type Profile struct {
+	// Rules is the time spent by each rule to visit the workflow syntax trees.
+	Rules []*ProfileEntry `json:"rules"`
+	// Files is the time spent to check each workflow file including parsing it.
+	Files []*ProfileEntry `json:"files"`
+	// Commands is the time spent to run each external command such as shellcheck.
+	Commands []*ProfileEntry `json:"commands"`
+}
+
+func writeProfileTable(out io.Writer, title string, es []*ProfileEntry) {
+	w := len(title)
+	for _, e := range es {
+		w = max(w, len(e.Name))
+	}
+	fmt.Fprintf(out, "%-*s  %8s  %12s\n", w, title, "COUNT", "TIME (ms)")
+	for _, e := range es {
+		fmt.Fprintf(out, "%-*s  %8d  %12.3f\n", w, e.Name, e.Count, float64(e.Elapsed.Microseconds())/1000)
+	}
+}
+
+// PrintTable prints the timing data as tables of rules, files, and commands.
+func (p *Profile) PrintTable(out io.Writer) {
+	writeProfileTable(out, "RULE", p.Rules)
+	fmt.Fprintln(out)
+	writeProfileTable(out, "FILE", p.Files)
+	if len(p.Commands) > 0 {
+		fmt.Fprintln(out)
+		writeProfileTable(out, "COMMAND", p.Commands)
+	}
+}
+
+// profiler collects the elapsed times while linting. This type is thread safe since files are
+// checked in parallel.
+type profiler struct {
+	mu       sync.Mutex
+	rules    map[string]*ProfileEntry
+	files    map[string]*ProfileEntry
+	commands map[string]*ProfileEntry
+}
+
+func newProfiler() *profiler {
+	return &profiler{
+		rules:    map[string]*ProfileEntry{},
+		files:    map[string]*ProfileEntry{},
+		commands: map[string]*ProfileEntry{},
+	}
+}
+
+func addProfileEntry(m map[string]*ProfileEntry, name string, d time.Duration) {
+	e, ok := m[name]
+	if !ok {
+		e = &ProfileEntry{Name: name}
+		m[name] = e
+	}
+	e.Count++
+	e.Elapsed += d
+}
+
+func (p *profiler) addRule(name string, d time.Duration) {
+	p.mu.Lock()
+	addProfileEntry(p.rules, name, d)
+	p.mu.Unlock()
+}
+
+func (p *profiler) addFile(path string, d time.Duration) {
+	p.mu.Lock()
+	addProfileEntry(p.files, path, d)
+	p.mu.Unlock()
+}
+
+func (p *profiler) addCommand(exe string, d time.Duration) {
+	p.mu.Lock()
+	addProfileEntry(p.commands, filepath.Base(exe), d)
+	p.mu.Unlock()
+}
+
+func sortedProfileEntries(m map[string]*ProfileEntry) []*ProfileEntry {
+	es := make([]*ProfileEntry, 0, len(m))
+	for _, e := range m {
+		c := *e
+		es = append(es, &c)
+	}
+	slices.SortFunc(es, func(a, b *ProfileEntry) int {
+		if a.Elapsed != b.Elapsed {
+			if a.Elapsed > b.Elapsed {
+				return -1
+			}
+			return 1
+		}
+		if a.Name < b.Name {
+			return -1
+		}
+		if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	})
+	return es
+}
+
+func (p *profiler) profile() *Profile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &Profile{
+		Rules:    sortedProfileEntries(p.rules),
+		Files:    sortedProfileEntries(p.files),
+		Commands: sortedProfileEntries(p.commands),
+	}
+}
+
+// profileWriter writes the CPU profile, the heap profile, and the timing data to the directory.
+type profileWriter struct {
+	dir string
+	cpu *os.File
+}
+
+// startProfile creates the directory and starts CPU profiling. Call finish method of the returned
+// value to stop the profiling and write the remaining files.
+func startProfile(dir string) (*profileWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create directory for profile: %w", err)
+	}
+	f, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return nil, fmt.Errorf("could not create file for CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("could not start CPU profile: %w", err)
+	}
+	return &profileWriter{dir, f}, nil
+}
+
+func (w *profileWriter) writeFile(name string, write func(io.Writer) error) error {
+	f, err := os.Create(filepath.Join(w.dir, name))
+	if err != nil {
+		return fmt.Errorf("could not create profile file: %w", err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write profile to %q: %w", f.Name(), err)
+	}
+	return f.Close()
+}
+
+// finish stops CPU profiling and writes heap.pprof, timings.json, and timings.txt.
+func (w *profileWriter) finish(p *Profile) error {
+	pprof.StopCPUProfile()
+	if err := w.cpu.Close(); err != nil {
+		return fmt.Errorf("could not write CPU profile: %w", err)
+	}
+
+	runtime.GC() // Get up-to-date statistics of heap
+	if err := w.writeFile("heap.pprof", pprof.WriteHeapProfile); err != nil {
+		return err
+	}
+
+	if p == nil {
+		return nil
+	}
+	if err := w.writeFile("timings.json", func(out io.Writer) error {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	}); err != nil {
+		return err
+	}
+	return w.writeFile("timings.txt", func(out io.Writer) error {
+		p.PrintTable(out)
+		return nil
+	})
+}
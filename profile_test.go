@@ -0,0 +1,146 @@
+package actionlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFindProfileEntry(es []*ProfileEntry, name string) *ProfileEntry {
+	for _, e := range es {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestLinterProfile(t *testing.T) {
+	exe, _ := testFakeShellcheck(t)
+	dir := t.TempDir()
+	testWriteFiles(t, dir, map[string]string{
+		"a.yaml": "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: |\n          echo\n          a\n",
+		"b.yaml": "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: |\n          echo\n          b\n",
+	})
+
+	l, err := NewLinter(io.Discard, &LinterOptions{Shellcheck: exe, Profile: true, WorkingDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")}
+	if _, err := l.LintFiles(files, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	p := l.Profile()
+	if p == nil {
+		t.Fatal("profile should be collected")
+	}
+	for _, r := range []string{"expression", "shellcheck", "runner-label"} {
+		e := testFindProfileEntry(p.Rules, r)
+		if e == nil {
+			t.Fatalf("rule %q is not in profile", r)
+		}
+		if e.Count != 2 {
+			t.Errorf("rule %q should be counted for 2 files but got %d", r, e.Count)
+		}
+	}
+	for _, f := range []string{"a.yaml", "b.yaml"} {
+		if e := testFindProfileEntry(p.Files, f); e == nil || e.Count != 1 {
+			t.Errorf("file %q is not in profile correctly: %+v", f, e)
+		}
+	}
+	// Scripts in each workflow are checked in one batch
+	if e := testFindProfileEntry(p.Commands, "shellcheck"); e == nil || e.Count != 2 {
+		t.Errorf("shellcheck should run once per file: %+v", e)
+	}
+	for i := 1; i < len(p.Rules); i++ {
+		if p.Rules[i-1].Elapsed < p.Rules[i].Elapsed {
+			t.Fatalf("rules are not sorted by elapsed time: %+v and %+v", p.Rules[i-1], p.Rules[i])
+		}
+	}
+}
+
+func TestLinterProfileDisabled(t *testing.T) {
+	l, err := NewLinter(io.Discard, &LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Lint("test.yaml", []byte("on: push\n"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if p := l.Profile(); p != nil {
+		t.Fatalf("profile should not be collected: %v", p)
+	}
+}
+
+func TestProfilePrintTable(t *testing.T) {
+	p := &Profile{
+		Rules: []*ProfileEntry{
+			{"expression", 3, 1500 * time.Microsecond},
+			{"shellcheck", 3, 250 * time.Microsecond},
+		},
+		Files: []*ProfileEntry{
+			{".github/workflows/ci.yaml", 1, 2 * time.Millisecond},
+		},
+	}
+	var b bytes.Buffer
+	p.PrintTable(&b)
+	want := `RULE           COUNT     TIME (ms)
+expression         3         1.500
+shellcheck         3         0.250
+
+FILE                          COUNT     TIME (ms)
+.github/workflows/ci.yaml         1         2.000
+`
+	if have := b.String(); have != want {
+		t.Fatalf("wanted:\n%s\nbut got:\n%s", want, have)
+	}
+}
+
+func TestCommandProfile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "profile")
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  os.Stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	workflow := filepath.Join("testdata", "ok", "minimal.yaml")
+	if status := cmd.Main([]string{"actionlint", "-shellcheck=", "-pyflakes=", "-profile", dir, workflow}); status != 0 {
+		t.Fatalf("exit status should be 0 but got %d: %s", status, stdout.String())
+	}
+
+	for _, f := range []string{"cpu.pprof", "heap.pprof", "timings.json", "timings.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("profile file %q was not written: %v", f, err)
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "timings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p Profile
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatal(err)
+	}
+	if testFindProfileEntry(p.Files, workflow) == nil {
+		t.Errorf("%q is not in timings: %s", workflow, b)
+	}
+	if testFindProfileEntry(p.Rules, "expression") == nil {
+		t.Errorf("expression rule is not in timings: %s", b)
+	}
+
+	out := stderr.String()
+	for _, s := range []string{"RULE", "FILE", "Profile was written to " + dir} {
+		if !strings.Contains(out, s) {
+			t.Errorf("stderr should contain %q: %q", s, out)
+		}
+	}
+}
@@ -1,15 +1,26 @@
 package actionlint
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // Project represents one GitHub project. One Git repository corresponds to one project.
 type Project struct {
 	root   string
 	config *Config
+	// staged is true when files in the project are read from the Git index instead of the working tree
+	staged bool
+	// owner is the owner of the repository on GitHub. It is empty when the owner is unknown
+	owner string
 }
 
 func absPath(path string) string {
@@ -45,7 +56,66 @@ func NewProject(root string) (*Project, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Project{root, c}, nil
+	return &Project{root, c, false, gitRemoteOwner(root)}, nil
+}
+
+// newStagedProject creates a new instance which reads files from the Git index of the repository
+// at the root directory. The config file is also read from the Git index.
+func newStagedProject(root string) (*Project, error) {
+	p := &Project{root, nil, true, gitRemoteOwner(root)}
+	for _, f := range []string{"actionlint.yaml", "actionlint.yml"} {
+		path := filepath.Join(root, ".github", f)
+		b, err := p.readFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		c, err := ParseConfig(b)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse config file %q: %w", path, err)
+		}
+		p.config = c
+		break
+	}
+	return p, nil
+}
+
+// gitRemoteOwner returns the owner of the repository from the URL of "origin" remote. It returns an
+// empty string when the remote is not found or its URL is not in the form of "{host}/{owner}/{repo}".
+func gitRemoteOwner(root string) string {
+	b, err := runGit(root, "remote", "get-url", "origin")
+	if err != nil {
+		return ""
+	}
+	return parseGitRemoteOwner(strings.TrimSpace(string(b)))
+}
+
+// parseGitRemoteOwner parses the owner from the remote URL such as "https://github.com/owner/repo.git"
+// or "git@github.com:owner/repo.git". Local paths and "file://" URLs don't have any owner.
+func parseGitRemoteOwner(remote string) string {
+	var p string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil || u.Scheme == "file" || u.Host == "" {
+			return ""
+		}
+		p = strings.TrimPrefix(u.Path, "/")
+	} else {
+		// scp-like syntax "[user@]host:path". The host part must not contain any slash
+		i := strings.IndexByte(remote, ':')
+		if i <= 0 || strings.ContainsRune(remote[:i], '/') {
+			return ""
+		}
+		p = remote[i+1:]
+	}
+
+	owner, repo, ok := strings.Cut(strings.TrimSuffix(p, "/"), "/")
+	if !ok || owner == "" || repo == "" || strings.ContainsRune(repo, '/') {
+		return ""
+	}
+	return owner
 }
 
 // RootDir returns a root directory path of the GitHub project repository.
@@ -59,6 +129,99 @@ func (p *Project) WorkflowsDir() string {
 	return filepath.Join(p.root, ".github", "workflows")
 }
 
+// readFile reads the file in the project. When the project is staged, the content is read from the
+// Git index. When the file is not in the index, the returned error wraps fs.ErrNotExist. Other
+// errors such as a failure of running git command are returned as-is.
+func (p *Project) readFile(path string) ([]byte, error) {
+	if !p.staged {
+		return os.ReadFile(path)
+	}
+	r, err := filepath.Rel(p.root, path)
+	if err != nil || strings.HasPrefix(r, "..") {
+		return nil, fmt.Errorf("file %q is not in the repository %q", path, p.root)
+	}
+	r = filepath.ToSlash(r)
+
+	if strings.ContainsRune(r, '\n') {
+		return nil, fmt.Errorf("file %q cannot be read from Git index since its path contains a newline", path)
+	}
+
+	// Unlike `git show`, `git cat-file --batch` tells whether the file is not in the index or not with
+	// its output. The output is "{object} {type} {size}\n{content}\n" or "{name} missing\n".
+	b, err := runGitWithInput(p.root, []byte(":"+r+"\n"), "cat-file", "--batch")
+	if err != nil {
+		return nil, fmt.Errorf("could not read %q from Git index: %w", path, err)
+	}
+	header, content, _ := bytes.Cut(b, []byte{'\n'})
+	if bytes.HasSuffix(header, []byte(" missing")) {
+		return nil, &fs.PathError{Op: "read from Git index", Path: path, Err: fs.ErrNotExist}
+	}
+	fields := strings.Fields(string(header))
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("could not read %q from Git index: unexpected output of `git cat-file`: %q", path, header)
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil || size > len(content) {
+		return nil, fmt.Errorf("could not read %q from Git index: unexpected output of `git cat-file`: %q", path, header)
+	}
+	return content[:size], nil
+}
+
+// listFiles returns the names of files directly in the directory in the project. When the project
+// is staged, the files are listed from the Git index. The error wraps fs.ErrNotExist when the
+// directory does not exist.
+func (p *Project) listFiles(dir string) ([]string, error) {
+	if !p.staged {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			if !e.IsDir() {
+				names = append(names, e.Name())
+			}
+		}
+		return names, nil
+	}
+
+	files, err := gitIndexFiles(p.root, dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, f := range files {
+		if filepath.Dir(f) == dir {
+			names = append(names, filepath.Base(f))
+		}
+	}
+	return names, nil
+}
+
+// gitIndexFiles returns the absolute paths of the files in the Git index under the directory.
+func gitIndexFiles(root, dir string) ([]string, error) {
+	b, err := runGit(root, "ls-files", "-z", "--full-name", "--", dir)
+	if err != nil {
+		return nil, err
+	}
+	ret := []string{}
+	for _, f := range bytes.Split(b, []byte{0}) {
+		if len(f) > 0 {
+			ret = append(ret, filepath.Join(root, filepath.FromSlash(string(f))))
+		}
+	}
+	return ret, nil
+}
+
+// readProjectFile reads the file via the project. When the project is nil, the file is read from
+// the file system.
+func readProjectFile(p *Project, path string) ([]byte, error) {
+	if p == nil {
+		return os.ReadFile(path)
+	}
+	return p.readFile(path)
+}
+
 // Knows returns true when the project knows the given file. When a file is included in the
 // project's directory, the project knows the file.
 func (p *Project) Knows(path string) bool {
@@ -74,9 +237,21 @@ func (p *Project) Config() *Config {
 	return p.config
 }
 
+// reloadConfig reads the config file of the repository again. This method is not thread safe. It
+// must not be called while checking workflows in the project.
+func (p *Project) reloadConfig() error {
+	c, err := loadRepoConfig(p.root)
+	if err != nil {
+		return err
+	}
+	p.config = c
+	return nil
+}
+
 // Projects represents set of projects. It caches Project instances which was created previously
 // and reuses them.
 type Projects struct {
+	mu    sync.Mutex
 	known []*Project
 }
 
@@ -86,8 +261,11 @@ func NewProjects() *Projects {
 }
 
 // At returns the Project instance which the path belongs to. It returns nil if no project is found
-// from the path.
+// from the path. Calling this method is thread-safe.
 func (ps *Projects) At(path string) (*Project, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
 	for _, p := range ps.known {
 		if p.Knows(path) {
 			return p, nil
@@ -1,10 +1,14 @@
 package actionlint
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"golang.org/x/sys/execabs"
 )
 
 // Create `.git` directory since actionlint finds the directory to detect the repository root.
@@ -149,3 +153,97 @@ func TestProjectsLoadingBrokenProjectConfig(t *testing.T) {
 		t.Fatalf("wanted error %q but have error %q", want, msg)
 	}
 }
+
+func TestProjectReadFileFromGitIndex(t *testing.T) {
+	if _, err := execabs.LookPath("git"); err != nil {
+		t.Skip("git command is not found")
+	}
+
+	dir := t.TempDir()
+	testRunGit(t, dir, "init", "-q")
+	testWriteFiles(t, dir, map[string]string{
+		"staged.txt":   "staged",
+		"unstaged.txt": "unstaged",
+	})
+	testRunGit(t, dir, "add", "staged.txt")
+	p := &Project{dir, nil, true, ""}
+
+	b, err := p.readFile(filepath.Join(dir, "staged.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "staged" {
+		t.Fatalf("unexpected content: %q", b)
+	}
+
+	for _, f := range []string{"unstaged.txt", "not-found.txt"} {
+		if _, err := p.readFile(filepath.Join(dir, f)); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("error for %q should wrap fs.ErrNotExist but got %v", f, err)
+		}
+	}
+
+	// Errors other than a missing file in the index should not be treated as fs.ErrNotExist
+	t.Setenv("GIT_DIR", filepath.Join(dir, "not-a-git-dir"))
+	_, err = p.readFile(filepath.Join(dir, "staged.txt"))
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("failure of git command should not be treated as missing file: %v", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "could not read") {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+}
+
+func TestProjectParseGitRemoteOwner(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"https://github.com/owner/repo.git", "owner"},
+		{"https://github.com/owner/repo", "owner"},
+		{"https://user@github.example.com/owner/repo.git", "owner"},
+		{"ssh://git@github.com/owner/repo.git", "owner"},
+		{"git@github.com:owner/repo.git", "owner"},
+		{"github.com:owner/repo", "owner"},
+		{"https://github.com/owner", ""},
+		{"https://github.com/owner/repo/extra", ""},
+		{"git@github.com:repo.git", ""},
+		{"file:///path/to/repo", ""},
+		{"/path/to/repo", ""},
+		{"../repo", ""},
+		{"", ""},
+	}
+
+	for _, tc := range tests {
+		if have := parseGitRemoteOwner(tc.remote); have != tc.want {
+			t.Errorf("wanted owner %q for remote %q but got %q", tc.want, tc.remote, have)
+		}
+	}
+}
+
+func TestProjectOwnerFromGitRemote(t *testing.T) {
+	if _, err := execabs.LookPath("git"); err != nil {
+		t.Skip("git command is not found")
+	}
+
+	dir := t.TempDir()
+	testRunGit(t, dir, "init", "-q")
+	p, err := NewProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.owner != "" {
+		t.Fatalf("owner should be empty without remote but got %q", p.owner)
+	}
+
+	testRunGit(t, dir, "remote", "add", "origin", "https://github.com/owner/repo.git")
+	p, err = NewProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.owner != "owner" {
+		t.Fatalf("wanted owner %q but got %q", "owner", p.owner)
+	}
+}
@@ -0,0 +1,251 @@
+package actionlint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"golang.org/x/sys/execabs"
+)
+
+// resultCacheFormat is a version of the format of cache files. Bump this value when the format or
+// the way to compute cache keys is changed.
+const resultCacheFormat = "1"
+
+// resultCache is an on-disk cache of lint results. The result of each workflow file is stored in a
+// file in the cache directory. The key of the result is a hash of the inputs which affect the result:
+// the file path and content, the resolved config, the ignore patterns, actionlint version, and
+// versions of shellcheck and pyflakes. Contents of local actions and reusable workflows used by the
+// workflow are recorded in the cache file and verified on reading it. When all of them are
+// unchanged, checking the workflow file can be skipped entirely.
+type resultCache struct {
+	dir        string
+	shellcheck string
+	pyflakes   string
+	ignore     []string
+	dbg        io.Writer
+	once       sync.Once
+	base       string
+}
+
+func newResultCache(dir, shellcheck, pyflakes string, ignore []string, dbg io.Writer) *resultCache {
+	return &resultCache{
+		dir:        dir,
+		shellcheck: shellcheck,
+		pyflakes:   pyflakes,
+		ignore:     ignore,
+		dbg:        dbg,
+	}
+}
+
+func (c *resultCache) debug(format string, args ...interface{}) {
+	if c.dbg == nil {
+		return
+	}
+	format = "[ResultCache] " + format + "\n"
+	fmt.Fprintf(c.dbg, format, args...)
+}
+
+// commandVersion returns the version output of the external command. When the command is not
+// available, it returns an empty string.
+func commandVersion(exe string) string {
+	if exe == "" {
+		return ""
+	}
+	p, err := execabs.LookPath(exe)
+	if err != nil {
+		return ""
+	}
+	out, err := execabs.Command(p, "--version").Output()
+	if err != nil {
+		return p
+	}
+	return p + "\n" + string(out)
+}
+
+// baseKey returns the part of cache keys which is common to all files. External commands are run
+// only once to get their versions.
+func (c *resultCache) baseKey() string {
+	c.once.Do(func() {
+		h := sha256.New()
+		fmt.Fprintf(h, "format:%s\x00version:%s\x00", resultCacheFormat, getCommandVersion())
+		fmt.Fprintf(h, "shellcheck:%s\x00", commandVersion(c.shellcheck))
+		fmt.Fprintf(h, "pyflakes:%s\x00", commandVersion(c.pyflakes))
+		for _, p := range c.ignore {
+			fmt.Fprintf(h, "ignore:%s\x00", p)
+		}
+		c.base = hex.EncodeToString(h.Sum(nil))
+		c.debug("Base key: %s", c.base)
+	})
+	return c.base
+}
+
+// localDependencies returns the file paths of local actions and local reusable workflows used by
+// the workflow. When the workflow is triggered by "workflow_run" event, all workflow files in the
+// project are also included since the workflow refers them. The returned paths are sorted.
+func localDependencies(w *Workflow, proj *Project) []string {
+	if w == nil || proj == nil {
+		return nil
+	}
+
+	deps := map[string]struct{}{}
+	root := proj.RootDir()
+	for _, j := range w.Jobs {
+		if j == nil {
+			continue
+		}
+		if j.WorkflowCall != nil {
+			if p := localUsesPath(j.WorkflowCall.Uses); p != "" {
+				deps[filepath.Join(root, filepath.FromSlash(p))] = struct{}{}
+			}
+		}
+		for _, s := range j.Steps {
+			if e, ok := s.Exec.(*ExecAction); ok {
+				if p := localUsesPath(e.Uses); p != "" {
+					d := filepath.Join(root, filepath.FromSlash(p))
+					deps[filepath.Join(d, "action.yaml")] = struct{}{}
+					deps[filepath.Join(d, "action.yml")] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for _, e := range w.On {
+		if e, ok := e.(*WebhookEvent); ok && e.Hook.Value == "workflow_run" {
+			dir := proj.WorkflowsDir()
+			if names, err := proj.listFiles(dir); err == nil {
+				for _, n := range names {
+					deps[filepath.Join(dir, n)] = struct{}{}
+				}
+			}
+			break
+		}
+	}
+
+	ret := make([]string, 0, len(deps))
+	for p := range deps {
+		ret = append(ret, p)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// key computes the cache key of the result of checking the workflow file. Local dependencies of
+// the workflow are not included in the key since parsing the workflow is necessary to know them.
+// They are stored in the cache entry and verified on getting the entry instead.
+func (c *resultCache) key(path string, src []byte, cfg *Config) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "base:%s\x00path:%s\x00", c.baseKey(), filepath.ToSlash(path))
+	h.Write(src)
+	h.Write([]byte{0})
+
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("could not serialize config for cache key: %w", err)
+	}
+	h.Write(b)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// resultCacheDep is a file which the cached result depends on. Hash is empty when the file did not
+// exist.
+type resultCacheDep struct {
+	Path string `json:"path"`
+	Hash string `json:"hash"`
+}
+
+// resultCacheEntry is the content of a cache file.
+type resultCacheEntry struct {
+	Deps   []resultCacheDep `json:"deps"`
+	Errors []*Error         `json:"errors"`
+}
+
+func hashProjectFile(proj *Project, path string) string {
+	b, err := proj.readFile(path)
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func (c *resultCache) file(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// get returns the cached errors for the key. The second return value is false when the cache
+// does not exist, cannot be read, or any of its dependencies was changed.
+func (c *resultCache) get(key string, proj *Project) ([]*Error, bool) {
+	b, err := os.ReadFile(c.file(key))
+	if err != nil {
+		return nil, false
+	}
+	var e resultCacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		c.debug("Broken cache file for key %s: %s", key, err)
+		return nil, false
+	}
+	for _, d := range e.Deps {
+		if proj == nil || hashProjectFile(proj, d.Path) != d.Hash {
+			c.debug("Dependency %q of cache %s was changed", d.Path, key)
+			return nil, false
+		}
+	}
+	return e.Errors, true
+}
+
+// put stores the errors for the key. Failing to write the cache is not fatal.
+func (c *resultCache) put(key string, errs []*Error, w *Workflow, proj *Project) {
+	deps := localDependencies(w, proj)
+	e := &resultCacheEntry{
+		Deps:   make([]resultCacheDep, 0, len(deps)),
+		Errors: errs,
+	}
+	for _, d := range deps {
+		e.Deps = append(e.Deps, resultCacheDep{d, hashProjectFile(proj, d)})
+	}
+	if err := c.write(key, e); err != nil {
+		c.debug("Could not write cache for key %s: %s", key, err)
+	}
+}
+
+func (c *resultCache) write(key string, e *resultCacheEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.file(key), b)
+}
+
+// writeFileAtomic writes the content to the file via a temporary file in the same directory so
+// that concurrent processes never read a partially written file. Parent directories are created
+// when they do not exist.
+func writeFileAtomic(path string, b []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
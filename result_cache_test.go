@@ -0,0 +1,174 @@
+package actionlint
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testReadResultCacheEntries(t *testing.T, dir string) map[string]*resultCacheEntry {
+	t.Helper()
+	fs, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := map[string]*resultCacheEntry{}
+	for _, f := range fs {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e resultCacheEntry
+		if err := json.Unmarshal(b, &e); err != nil {
+			t.Fatalf("broken cache file %s: %v", f, err)
+		}
+		ret[f] = &e
+	}
+	return ret
+}
+
+func TestLinterResultCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := filepath.Join(dir, "cache")
+	workflow := filepath.Join(dir, ".github", "workflows", "test.yaml")
+	testWriteFiles(t, dir, map[string]string{
+		".git/HEAD": "ref: refs/heads/main\n",
+		".github/workflows/test.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ./action
+      - run: echo ${{ unknown_context }}
+`,
+		"action/action.yml": `name: Test
+description: Test action
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`,
+	})
+
+	lint := func() []*Error {
+		t.Helper()
+		l, err := NewLinter(io.Discard, &LinterOptions{CacheDir: cache})
+		if err != nil {
+			t.Fatal(err)
+		}
+		errs, err := l.LintFiles([]string{workflow}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return errs
+	}
+
+	errs := lint()
+	if len(errs) != 1 || errs[0].Code != "expression/undefined-variable" {
+		t.Fatalf("unexpected errors at first run: %v", errs)
+	}
+
+	entries := testReadResultCacheEntries(t, cache)
+	if len(entries) != 1 {
+		t.Fatalf("wanted one cache file but got %d: %v", len(entries), entries)
+	}
+
+	// Replace the cached result to confirm checking the file is skipped on the next run
+	for f, e := range entries {
+		if len(e.Deps) != 2 {
+			t.Fatalf("wanted action.yml and action.yaml as dependencies but got %v", e.Deps)
+		}
+		e.Errors = []*Error{{Message: "cached", Filepath: workflow, Line: 1, Column: 1, Kind: "test"}}
+		b, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs = lint()
+	if len(errs) != 1 || errs[0].Message != "cached" {
+		t.Fatalf("cached result was not used: %v", errs)
+	}
+
+	// Changing the local action invalidates the cache
+	testWriteFiles(t, dir, map[string]string{
+		"action/action.yml": `name: Test
+description: Test action
+inputs:
+  name:
+    description: Name
+    required: true
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`,
+	})
+
+	errs = lint()
+	if len(errs) != 2 {
+		t.Fatalf("wanted 2 errors after changing the local action but got %v", errs)
+	}
+	if errs[0].Code != "action/missing-required-input" {
+		t.Fatalf("unexpected error after changing the local action: %v", errs[0])
+	}
+
+	// Changing the workflow content invalidates the cache
+	testWriteFiles(t, dir, map[string]string{
+		".github/workflows/test.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ./action
+        with:
+          name: foo
+`,
+	})
+
+	if errs := lint(); len(errs) != 0 {
+		t.Fatalf("wanted no error after fixing the workflow but got %v", errs)
+	}
+	if n := len(testReadResultCacheEntries(t, cache)); n != 2 {
+		t.Fatalf("wanted 2 cache files but got %d", n)
+	}
+}
+
+func TestLinterResultCacheDisabledByOnRulesCreated(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "cache")
+	o := &LinterOptions{
+		CacheDir:       cache,
+		OnRulesCreated: func(rules []Rule) []Rule { return rules },
+	}
+	l, err := NewLinter(io.Discard, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Lint("test.yaml", []byte("on: push\n"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cache); !os.IsNotExist(err) {
+		t.Fatalf("cache directory should not be created: %v", err)
+	}
+}
+
+func TestResultCacheBrokenFile(t *testing.T) {
+	dir := t.TempDir()
+	c := newResultCache(dir, "", "", nil, nil)
+	if err := os.WriteFile(c.file("broken"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if errs, ok := c.get("broken", nil); ok {
+		t.Fatalf("broken cache file should be a cache miss: %v", errs)
+	}
+	if _, ok := c.get("missing", nil); ok {
+		t.Fatal("missing cache file should be a cache miss")
+	}
+}
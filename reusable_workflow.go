@@ -3,7 +3,7 @@ package actionlint
 import (
 	"fmt"
 	"io"
-	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -29,6 +29,9 @@ type ReusableWorkflowMetadataInput struct {
 	Required bool
 	// Type is a type of the input. When the input type is unknown, 'any' type is set.
 	Type ExprType
+	// Pos is a position of the input definition in the reusable workflow file. This value is nil
+	// when the position is unknown.
+	Pos *Pos
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler.
@@ -78,6 +81,7 @@ func (inputs *ReusableWorkflowMetadataInputs) UnmarshalYAML(n *yaml.Node) error
 			return err
 		}
 		m.Name = k.Value
+		m.Pos = posAt(k)
 		if m.Type == nil {
 			m.Type = AnyType{} // Reach here when `v` is null node
 		}
@@ -160,6 +164,9 @@ type ReusableWorkflowMetadata struct {
 	Inputs  ReusableWorkflowMetadataInputs  `yaml:"inputs"`
 	Outputs ReusableWorkflowMetadataOutputs `yaml:"outputs"`
 	Secrets ReusableWorkflowMetadataSecrets `yaml:"secrets"`
+	// Path is a file path of the reusable workflow. It is relative to the current working directory
+	// when possible. This value is empty when the path is unknown.
+	Path string `yaml:"-"`
 }
 
 // LocalReusableWorkflowCache is a cache for local reusable workflow metadata files. It avoids find/read/parse
@@ -215,7 +222,7 @@ func (c *LocalReusableWorkflowCache) FindMetadata(spec string) (*ReusableWorkflo
 	}
 
 	file := filepath.Join(c.proj.RootDir(), filepath.FromSlash(spec))
-	src, err := os.ReadFile(file)
+	src, err := c.proj.readFile(file)
 	if err != nil {
 		c.writeCache(spec, nil) // Remember the workflow file was not found
 		return nil, fmt.Errorf("could not read reusable workflow file for %q: %w", spec, err)
@@ -228,11 +235,37 @@ func (c *LocalReusableWorkflowCache) FindMetadata(spec string) (*ReusableWorkflo
 		return nil, fmt.Errorf("error while parsing reusable workflow %q: %s", spec, msg)
 	}
 
+	m.Path = c.relPath(file)
 	c.debug("New reusable workflow metadata at %s: %v", file, m)
 	c.writeCache(spec, m)
 	return m, nil
 }
 
+// invalidate removes the cached metadata of the reusable workflow file. The file is a
+// slash-separated path relative to the project root. This method is used to reload the metadata
+// when the workflow file is changed.
+func (c *LocalReusableWorkflowCache) invalidate(file string) {
+	c.mu.Lock()
+	for k := range c.cache {
+		if path.Clean(k) == file {
+			c.debug("Invalidate cache for %s", k)
+			delete(c.cache, k)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// relPath converts the absolute file path to the path relative to the current working directory
+// as file paths of errors.
+func (c *LocalReusableWorkflowCache) relPath(p string) string {
+	if c.cwd != "" {
+		if r, err := filepath.Rel(c.cwd, p); err == nil {
+			return r
+		}
+	}
+	return p
+}
+
 func (c *LocalReusableWorkflowCache) convWorkflowPathToSpec(p string) (string, bool) {
 	if c.proj == nil {
 		return "", false
@@ -276,10 +309,15 @@ func (c *LocalReusableWorkflowCache) WriteWorkflowCallEvent(wpath string, event
 		return
 	}
 
+	p := wpath
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(c.cwd, p)
+	}
 	m := &ReusableWorkflowMetadata{
 		Inputs:  ReusableWorkflowMetadataInputs{},
 		Outputs: ReusableWorkflowMetadataOutputs{},
 		Secrets: ReusableWorkflowMetadataSecrets{},
+		Path:    c.relPath(p),
 	}
 
 	for _, i := range event.Inputs {
@@ -296,6 +334,7 @@ func (c *LocalReusableWorkflowCache) WriteWorkflowCallEvent(wpath string, event
 			Type:     t,
 			Required: i.Required != nil && i.Required.Value && i.Default == nil,
 			Name:     i.Name.Value,
+			Pos:      i.Name.Pos,
 		}
 	}
 
@@ -387,6 +426,7 @@ func newNullLocalReusableWorkflowCache(dbg io.Writer) *LocalReusableWorkflowCach
 // LocalReusableWorkflowCacheFactory is a factory object to create a LocalReusableWorkflowCache
 // instance per project.
 type LocalReusableWorkflowCacheFactory struct {
+	mu     sync.Mutex
 	caches map[string]*LocalReusableWorkflowCache
 	cwd    string
 	dbg    io.Writer
@@ -394,16 +434,18 @@ type LocalReusableWorkflowCacheFactory struct {
 
 // NewLocalReusableWorkflowCacheFactory creates a new LocalReusableWorkflowCacheFactory instance.
 func NewLocalReusableWorkflowCacheFactory(cwd string, dbg io.Writer) *LocalReusableWorkflowCacheFactory {
-	return &LocalReusableWorkflowCacheFactory{map[string]*LocalReusableWorkflowCache{}, cwd, dbg}
+	return &LocalReusableWorkflowCacheFactory{caches: map[string]*LocalReusableWorkflowCache{}, cwd: cwd, dbg: dbg}
 }
 
 // GetCache returns a new or existing LocalReusableWorkflowCache instance per project. When a instance
 // was already created for the project, this method returns the existing instance. Otherwise it creates
-// a new instance and returns it.
+// a new instance and returns it. Calling this method is thread-safe.
 func (f *LocalReusableWorkflowCacheFactory) GetCache(p *Project) *LocalReusableWorkflowCache {
 	if p == nil {
 		return newNullLocalReusableWorkflowCache(f.dbg)
 	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	r := p.RootDir()
 	if c, ok := f.caches[r]; ok {
 		return c
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestReusableWorkflowUnmarshalOK(t *testing.T) {
@@ -32,7 +33,7 @@ func TestReusableWorkflowUnmarshalOK(t *testing.T) {
 			`,
 			want: &ReusableWorkflowMetadata{
 				Inputs: ReusableWorkflowMetadataInputs{
-					"i": {"i", false, StringType{}},
+					"i": {"i", false, StringType{}, nil},
 				},
 				Outputs: ReusableWorkflowMetadataOutputs{
 					"o": {"o"},
@@ -100,15 +101,15 @@ func TestReusableWorkflowUnmarshalOK(t *testing.T) {
 			`,
 			want: &ReusableWorkflowMetadata{
 				Inputs: ReusableWorkflowMetadataInputs{
-					"a": {"a", false, StringType{}},
-					"b": {"b", false, NumberType{}},
-					"c": {"c", false, BoolType{}},
-					"d": {"d", false, AnyType{}},
-					"e": {"e", false, StringType{}},
-					"f": {"f", true, StringType{}},
-					"g": {"g", false, StringType{}},
-					"h": {"h", false, StringType{}},
-					"i": {"i", true, AnyType{}},
+					"a": {"a", false, StringType{}, nil},
+					"b": {"b", false, NumberType{}, nil},
+					"c": {"c", false, BoolType{}, nil},
+					"d": {"d", false, AnyType{}, nil},
+					"e": {"e", false, StringType{}, nil},
+					"f": {"f", true, StringType{}, nil},
+					"g": {"g", false, StringType{}, nil},
+					"h": {"h", false, StringType{}, nil},
+					"i": {"i", true, AnyType{}, nil},
 				},
 				Outputs: nil,
 				Secrets: nil,
@@ -130,7 +131,7 @@ func TestReusableWorkflowUnmarshalOK(t *testing.T) {
 			`,
 			want: &ReusableWorkflowMetadata{
 				Inputs: ReusableWorkflowMetadataInputs{
-					"i": {"i", false, StringType{}},
+					"i": {"i", false, StringType{}, nil},
 				},
 				Outputs: ReusableWorkflowMetadataOutputs{
 					"o": {"o"},
@@ -217,8 +218,8 @@ func TestReusableWorkflowUnmarshalOK(t *testing.T) {
 			`,
 			want: &ReusableWorkflowMetadata{
 				Inputs: ReusableWorkflowMetadataInputs{
-					"my_input1": {"MY_INPUT1", false, StringType{}},
-					"my_input2": {"MY_INPUT2", false, NumberType{}},
+					"my_input1": {"MY_INPUT1", false, StringType{}, nil},
+					"my_input2": {"MY_INPUT2", false, NumberType{}, nil},
 				},
 				Outputs: ReusableWorkflowMetadataOutputs{
 					"my_output1": {"MY_OUTPUT1"},
@@ -242,7 +243,7 @@ func TestReusableWorkflowUnmarshalOK(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			if diff := cmp.Diff(m, tc.want); diff != "" {
+			if diff := cmp.Diff(m, tc.want, testReusableWorkflowIgnorePos); diff != "" {
 				t.Fatal("Parse result is unexpected. diff:\n" + diff)
 			}
 		})
@@ -341,10 +342,17 @@ func TestReusableWorkflowUnmarshalEventNotFound(t *testing.T) {
 	}
 }
 
+// Positions and file paths of metadata depend on how the workflow is written. They are checked by
+// dedicated tests.
+var testReusableWorkflowIgnorePos = cmp.Options{
+	cmpopts.IgnoreFields(ReusableWorkflowMetadataInput{}, "Pos"),
+	cmpopts.IgnoreFields(ReusableWorkflowMetadata{}, "Path"),
+}
+
 var testReusableWorkflowWantedMetadata *ReusableWorkflowMetadata = &ReusableWorkflowMetadata{
 	Inputs: ReusableWorkflowMetadataInputs{
-		"input1": {"input1", false, StringType{}},
-		"input2": {"input2", true, BoolType{}},
+		"input1": {"input1", false, StringType{}, nil},
+		"input2": {"input2", true, BoolType{}, nil},
 	},
 	Outputs: ReusableWorkflowMetadataOutputs{
 		"output1": {"output1"},
@@ -356,14 +364,14 @@ var testReusableWorkflowWantedMetadata *ReusableWorkflowMetadata = &ReusableWork
 }
 
 func TestReusableWorkflowCacheFindMetadataOK(t *testing.T) {
-	proj := &Project{filepath.Join("testdata", "reusable_workflow_metadata"), nil}
+	proj := &Project{filepath.Join("testdata", "reusable_workflow_metadata"), nil, false, ""}
 	c := NewLocalReusableWorkflowCache(proj, "", nil)
 
 	m, err := c.FindMetadata("./ok.yaml")
 	if err != nil {
 		t.Fatal(err)
 	}
-	if diff := cmp.Diff(m, testReusableWorkflowWantedMetadata); diff != "" {
+	if diff := cmp.Diff(m, testReusableWorkflowWantedMetadata, testReusableWorkflowIgnorePos); diff != "" {
 		t.Fatal(diff)
 	}
 
@@ -416,7 +424,7 @@ func TestReusableWorkflowCacheFindMetadataError(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.what, func(t *testing.T) {
-			proj := &Project{filepath.Join("testdata", "reusable_workflow_metadata"), nil}
+			proj := &Project{filepath.Join("testdata", "reusable_workflow_metadata"), nil, false, ""}
 			c := NewLocalReusableWorkflowCache(proj, "", nil)
 			_, err := c.FindMetadata(tc.spec)
 			if err == nil {
@@ -439,7 +447,7 @@ func TestReusableWorkflowCacheFindMetadataError(t *testing.T) {
 }
 
 func TestReusableWorkflowCacheFindMetadataSkipParsing(t *testing.T) {
-	p := &Project{filepath.Join("testdata", "reusable_workflow_metadata"), nil}
+	p := &Project{filepath.Join("testdata", "reusable_workflow_metadata"), nil, false, ""}
 	tests := []struct {
 		what string
 		proj *Project
@@ -484,7 +492,7 @@ func TestReusableWorkflowCacheFindMetadataSkipParsing(t *testing.T) {
 }
 
 func TestReusableWorkflowConvertWorkflowPathToSpec(t *testing.T) {
-	p := &Project{filepath.Join("path", "to", "project"), nil}
+	p := &Project{filepath.Join("path", "to", "project"), nil, false, ""}
 	cwd := filepath.Join("path", "to", "project", "cwd")
 	tests := []struct {
 		what string
@@ -521,7 +529,7 @@ func TestReusableWorkflowConvertWorkflowPathToSpec(t *testing.T) {
 		},
 		{
 			what: "other project",
-			proj: &Project{filepath.Join("path", "to", "other-project"), nil},
+			proj: &Project{filepath.Join("path", "to", "other-project"), nil, false, ""},
 			ok:   false,
 		},
 	}
@@ -555,10 +563,10 @@ func TestReusableWorkflowMetadataFromASTNodeInputs(t *testing.T) {
 				"unknown_input": {},
 			},
 			want: ReusableWorkflowMetadataInputs{
-				"string_input":  {"string_input", false, StringType{}},
-				"number_input":  {"number_input", false, NumberType{}},
-				"bool_input":    {"bool_input", false, BoolType{}},
-				"unknown_input": {"unknown_input", false, AnyType{}},
+				"string_input":  {"string_input", false, StringType{}, nil},
+				"number_input":  {"number_input", false, NumberType{}, nil},
+				"bool_input":    {"bool_input", false, BoolType{}, nil},
+				"unknown_input": {"unknown_input", false, AnyType{}, nil},
 			},
 		},
 		{
@@ -579,11 +587,11 @@ func TestReusableWorkflowMetadataFromASTNodeInputs(t *testing.T) {
 				},
 			},
 			want: ReusableWorkflowMetadataInputs{
-				"unspecified":          {"unspecified", false, AnyType{}},
-				"not_required":         {"not_required", false, AnyType{}},
-				"required":             {"required", true, AnyType{}},
-				"required_but_default": {"required_but_default", false, AnyType{}},
-				"expression":           {"expression", false, AnyType{}},
+				"unspecified":          {"unspecified", false, AnyType{}, nil},
+				"not_required":         {"not_required", false, AnyType{}, nil},
+				"required":             {"required", true, AnyType{}, nil},
+				"required_but_default": {"required_but_default", false, AnyType{}, nil},
+				"expression":           {"expression", false, AnyType{}, nil},
 			},
 		},
 		{
@@ -597,7 +605,7 @@ func TestReusableWorkflowMetadataFromASTNodeInputs(t *testing.T) {
 				"MY_INPUT": {Type: WorkflowCallEventInputTypeString},
 			},
 			want: ReusableWorkflowMetadataInputs{
-				"my_input": {"MY_INPUT", false, StringType{}},
+				"my_input": {"MY_INPUT", false, StringType{}, nil},
 			},
 		},
 		{
@@ -606,7 +614,7 @@ func TestReusableWorkflowMetadataFromASTNodeInputs(t *testing.T) {
 				"MY_INPUT": {},
 			},
 			want: ReusableWorkflowMetadataInputs{
-				"my_input": {"MY_INPUT", false, AnyType{}},
+				"my_input": {"MY_INPUT", false, AnyType{}, nil},
 			},
 		},
 	}
@@ -614,7 +622,7 @@ func TestReusableWorkflowMetadataFromASTNodeInputs(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.what, func(t *testing.T) {
 			cwd := filepath.Join("path", "to", "project")
-			proj := &Project{cwd, nil}
+			proj := &Project{cwd, nil, false, ""}
 			c := NewLocalReusableWorkflowCache(proj, cwd, nil)
 			e := &WorkflowCallEvent{Inputs: []*WorkflowCallEventInput{}}
 			for n, i := range tc.inputs {
@@ -630,7 +638,7 @@ func TestReusableWorkflowMetadataFromASTNodeInputs(t *testing.T) {
 				t.Fatal("Event was not converted to event")
 			}
 
-			if diff := cmp.Diff(m.Inputs, tc.want); diff != "" {
+			if diff := cmp.Diff(m.Inputs, tc.want, testReusableWorkflowIgnorePos); diff != "" {
 				t.Fatal(diff)
 			}
 			if len(m.Outputs) != 0 {
@@ -653,7 +661,7 @@ func TestReusableWorkflowMetadataFromASTNodeOutputs(t *testing.T) {
 	for _, outputs := range tests {
 		t.Run(fmt.Sprintf("%s", outputs), func(t *testing.T) {
 			cwd := filepath.Join("path", "to", "project")
-			proj := &Project{cwd, nil}
+			proj := &Project{cwd, nil, false, ""}
 			c := NewLocalReusableWorkflowCache(proj, cwd, nil)
 			e := &WorkflowCallEvent{Outputs: map[string]*WorkflowCallEventOutput{}}
 			for _, o := range outputs {
@@ -708,7 +716,7 @@ func TestReusableWorkflowMetadataFromASTNodeSecrets(t *testing.T) {
 	for _, secrets := range tests {
 		t.Run(fmt.Sprintf("%s", secrets), func(t *testing.T) {
 			cwd := filepath.Join("path", "to", "project")
-			proj := &Project{cwd, nil}
+			proj := &Project{cwd, nil, false, ""}
 			c := NewLocalReusableWorkflowCache(proj, cwd, nil)
 			e := &WorkflowCallEvent{Secrets: map[string]*WorkflowCallEventSecret{}}
 			for n, r := range secrets {
@@ -755,7 +763,7 @@ func TestReusableWorkflowMetadataFromASTNodeDoNothing(t *testing.T) {
 		t.Fatal("Metadata created:", m)
 	}
 
-	proj := &Project{cwd, nil}
+	proj := &Project{cwd, nil, false, ""}
 	c = NewLocalReusableWorkflowCache(proj, filepath.Join("path", "to", "another-project"), nil)
 	c.WriteWorkflowCallEvent("workflow.yaml", &WorkflowCallEvent{})
 	m, ok = c.readCache("./workflow.yaml")
@@ -779,7 +787,7 @@ func TestReusableWorkflowMetadataFromASTNodeDoNothing(t *testing.T) {
 func TestReusableWorkflowMetadataCacheFindOneMetadataConcurrently(t *testing.T) {
 	n := 10
 	cwd := filepath.Join("testdata", "reusable_workflow_metadata")
-	proj := &Project{cwd, nil}
+	proj := &Project{cwd, nil, false, ""}
 	c := NewLocalReusableWorkflowCache(proj, cwd, nil)
 	ret := make(chan *ReusableWorkflowMetadata)
 	err := make(chan error)
@@ -811,7 +819,7 @@ func TestReusableWorkflowMetadataCacheFindOneMetadataConcurrently(t *testing.T)
 	}
 
 	for _, m := range ms {
-		if diff := cmp.Diff(testReusableWorkflowWantedMetadata, m); diff != "" {
+		if diff := cmp.Diff(testReusableWorkflowWantedMetadata, m, testReusableWorkflowIgnorePos); diff != "" {
 			t.Fatal(diff)
 		}
 	}
@@ -831,7 +839,7 @@ func TestReusableWorkflowMetadataCacheFindOneMetadataConcurrently(t *testing.T)
 func TestReusableWorkflowMetadataCacheWriteFromFileAndASTNodeConcurrently(t *testing.T) {
 	n := 10
 	cwd := filepath.Join("testdata", "reusable_workflow_metadata")
-	proj := &Project{cwd, nil}
+	proj := &Project{cwd, nil, false, ""}
 	c := NewLocalReusableWorkflowCache(proj, cwd, nil)
 	ret := make(chan struct{})
 	err := make(chan error)
@@ -901,10 +909,10 @@ func TestReusableWorkflowCacheFactory(t *testing.T) {
 	cwd := filepath.Join("path", "to", "project1")
 	f := NewLocalReusableWorkflowCacheFactory(cwd, nil)
 
-	p1 := &Project{cwd, nil}
+	p1 := &Project{cwd, nil, false, ""}
 	c1 := f.GetCache(p1)
 
-	p2 := &Project{filepath.Join("path", "to", "project2"), nil}
+	p2 := &Project{filepath.Join("path", "to", "project2"), nil, false, ""}
 	c2 := f.GetCache(p2)
 	if c1 == c2 {
 		t.Errorf("Different cache was not created: %v", c1)
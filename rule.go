@@ -5,6 +5,24 @@ import (
 	"io"
 )
 
+// RuleMetadata is metadata of a rule to explain what the rule checks. It is shown by -list-rules and
+// -explain flags of actionlint command.
+type RuleMetadata struct {
+	// Name is the name of the rule.
+	Name string `json:"name"`
+	// Description is the one-line description of the rule.
+	Description string `json:"description"`
+	// Explanation is the extended description of the rule. It may consist of multiple lines.
+	Explanation string `json:"explanation,omitempty"`
+	// BadExample is an example of workflow which is reported by the rule.
+	BadExample string `json:"bad_example,omitempty"`
+	// GoodExample is the fixed version of BadExample which is not reported by the rule.
+	GoodExample string `json:"good_example,omitempty"`
+	// Command is the name of the external command which the rule depends on. It is empty when the
+	// rule does not need any external command. The rule is disabled when the command is not found.
+	Command string `json:"command,omitempty"`
+}
+
 // RuleBase is a struct to be a base of rule structs. Embed this struct to define default methods
 // automatically
 type RuleBase struct {
@@ -49,8 +67,42 @@ func (r *RuleBase) Error(pos *Pos, msg string) {
 // Errorf reports a new error with the source position and the formatted error message and stores it
 // in the rule instance. The errors can be accessed by Errs method.
 func (r *RuleBase) Errorf(pos *Pos, format string, args ...interface{}) {
+	r.errorf(pos, format, args...)
+}
+
+func (r *RuleBase) errorf(pos *Pos, format string, args ...interface{}) *Error {
 	err := errorfAt(pos, r.name, format, args...)
 	r.errs = append(r.errs, err)
+	return err
+}
+
+// ErrorfRange reports a new error with the range of the source and the formatted error message and
+// stores it in the rule instance. The end position is inclusive. When end is nil, the error is
+// reported only with the start position as Errorf.
+func (r *RuleBase) ErrorfRange(start, end *Pos, format string, args ...interface{}) {
+	r.errorf(start, format, args...).setEnd(end)
+}
+
+// ErrorfWithSuggestion is the same as Errorf but it also attaches a suggestion to fix the error.
+// Formatters such as "rdjson" can output the suggestion so that tools can apply the fix.
+func (r *RuleBase) ErrorfWithSuggestion(pos *Pos, s *Suggestion, format string, args ...interface{}) {
+	r.errorf(pos, format, args...).Suggestions = []*Suggestion{s}
+}
+
+// ErrorfWithRelated is the same as Errorf but it also attaches other locations related to the
+// error. For example, a duplicate ID error can point to the location where the ID is first defined.
+func (r *RuleBase) ErrorfWithRelated(pos *Pos, related []RelatedLocation, format string, args ...interface{}) {
+	r.errorf(pos, format, args...).Related = related
+}
+
+// ErrorfWithCode reports a new error with the sub-code as Errorf. The code is a stable identifier
+// of the error within the rule like "untrusted-input". The full code of the error is prefixed with
+// the rule name like "expression/untrusted-input". The reported error is returned so that the caller
+// can attach more information to the error such as its range, suggestions, or related locations.
+func (r *RuleBase) ErrorfWithCode(pos *Pos, code string, format string, args ...interface{}) *Error {
+	err := r.errorf(pos, format, args...)
+	err.Code = r.name + "/" + code
+	return err
 }
 
 // Debug prints debug log to the output. The output is specified by the argument of EnableDebug method.
@@ -68,6 +120,34 @@ func (r *RuleBase) Errs() []*Error {
 	return r.errs
 }
 
+// forkRule creates a shallow copy of the rule to check one job. This is a helper to implement
+// ForkJob method of JobLocalPass interface. The copy starts with no error and shares all other
+// fields with the original rule. Rules using this function must follow the contract: fields set in
+// VisitWorkflowPre must not be modified while visiting jobs, fields modified while visiting a job
+// must be assigned new values in VisitJobPre, and shared caches must be safe for concurrent use.
+func forkRule[R any, P interface {
+	*R
+	resetErrs()
+}](rule P) P {
+	r := *rule
+	f := P(&r)
+	f.resetErrs()
+	return f
+}
+
+func (r *RuleBase) resetErrs() {
+	r.errs = nil
+}
+
+// JoinJob appends the errors found by the copy of the rule created by ForkJob method. This is the
+// default implementation of JoinJob method of JobLocalPass interface. Rules which can check each job
+// independently implement ForkJob method to satisfy the interface.
+func (r *RuleBase) JoinJob(fork Pass) {
+	if f, ok := fork.(Rule); ok {
+		r.errs = append(r.errs, f.Errs()...)
+	}
+}
+
 // Name returns the name of the rule.
 func (r *RuleBase) Name() string {
 	return r.name
@@ -78,6 +158,15 @@ func (r *RuleBase) Description() string {
 	return r.desc
 }
 
+// Metadata returns the metadata of the rule. By default, it only contains the name and the
+// description. Override this method to explain the rule in more details.
+func (r *RuleBase) Metadata() *RuleMetadata {
+	return &RuleMetadata{
+		Name:        r.name,
+		Description: r.desc,
+	}
+}
+
 // EnableDebug enables debug output from the rule. Given io.Writer instance is used to print debug
 // information to console. Setting nil means disabling debug output.
 func (r *RuleBase) EnableDebug(out io.Writer) {
@@ -102,6 +191,7 @@ type Rule interface {
 	Errs() []*Error
 	Name() string
 	Description() string
+	Metadata() *RuleMetadata
 	EnableDebug(out io.Writer)
 	SetConfig(cfg *Config)
 	Config() *Config
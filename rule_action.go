@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -314,6 +315,40 @@ func NewRuleAction(cache *LocalActionsCache) *RuleAction {
 	}
 }
 
+// ForkJob implements JobLocalPass interface. The copy shares the cache of local actions, which is
+// safe for concurrent use.
+func (rule *RuleAction) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleAction) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Actions at "uses:" are checked. The format of "uses:" is validated. Inputs of
+popular actions and local actions are checked with their metadata. Undefined
+inputs, missing required inputs, deprecated inputs, and outdated versions of
+popular actions whose runtime is no longer available are reported.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-dept: 0
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+`
+	return m
+}
+
 // VisitStep is callback when visiting Step node.
 func (rule *RuleAction) VisitStep(n *Step) error {
 	e, ok := n.Exec.(*ExecAction)
@@ -375,7 +410,25 @@ func (rule *RuleAction) checkRepoAction(spec string, exec *ExecAction) {
 	meta, ok := PopularActions[spec]
 	if !ok {
 		if _, ok := OutdatedPopularActionSpecs[spec]; ok {
-			rule.Errorf(exec.Uses.Pos, "the runner of %q action is too old to run on GitHub Actions. update the action's version to fix this issue", spec)
+			msg := "the runner of %q action is too old to run on GitHub Actions. update the action's version to fix this issue"
+			// Suggest the latest version known by actionlint as a fix
+			if latest, ok := latestPopularActionSpec(owner + "/" + s); ok && !strings.Contains(exec.Uses.Value, "\n") {
+				col := exec.Uses.Pos.Col
+				if exec.Uses.Quoted {
+					col++
+				}
+				fix := &Suggestion{
+					Line:      exec.Uses.Pos.Line,
+					Column:    col,
+					EndLine:   exec.Uses.Pos.Line,
+					EndColumn: col + len(spec) - 1,
+					Text:      latest,
+				}
+				err := rule.ErrorfWithCode(exec.Uses.Pos, "outdated-action", msg, spec)
+				err.Suggestions = []*Suggestion{fix}
+				return
+			}
+			rule.ErrorfWithCode(exec.Uses.Pos, "outdated-action", msg, spec)
 			return
 		}
 		rule.Debug("This action is not found in popular actions data set: %s", spec)
@@ -392,11 +445,11 @@ func (rule *RuleAction) checkRepoAction(spec string, exec *ExecAction) {
 }
 
 func (rule *RuleAction) invalidActionFormat(pos *Pos, spec string, why string) {
-	rule.Errorf(pos, "specifying action %q in invalid format because %s. available formats are \"{owner}/{repo}@{ref}\" or \"{owner}/{repo}/{path}@{ref}\"", spec, why)
+	rule.ErrorfWithCode(pos, "invalid-format", "specifying action %q in invalid format because %s. available formats are \"{owner}/{repo}@{ref}\" or \"{owner}/{repo}/{path}@{ref}\"", spec, why)
 }
 
 func (rule *RuleAction) missingRunsProp(pos *Pos, prop, ty, action, path string) {
-	rule.Errorf(pos, `%q is required in "runs" section because %q is a %s action. the action is defined at %q`, prop, action, ty, path)
+	rule.ErrorfWithCode(pos, "invalid-runs", `%q is required in "runs" section because %q is a %s action. the action is defined at %q`, prop, action, ty, path)
 }
 
 func (rule *RuleAction) checkInvalidRunsProps(pos *Pos, r *ActionMetadataRuns, ty, action, path string, props []string) {
@@ -415,7 +468,7 @@ func (rule *RuleAction) checkInvalidRunsProps(pos *Pos, r *ActionMetadataRuns, t
 			prop == "env" && r.Env != nil
 
 		if invalid {
-			rule.Errorf(pos, `%q is not allowed in "runs" section because %q is a %s action. the action is defined at %q`, prop, action, ty, path)
+			rule.ErrorfWithCode(pos, "invalid-runs", `%q is not allowed in "runs" section because %q is a %s action. the action is defined at %q`, prop, action, ty, path)
 		}
 	}
 }
@@ -427,7 +480,7 @@ func (rule *RuleAction) checkRunsFileExists(file, dir, prop, name string, pos *P
 	}
 	p := filepath.Join(dir, f)
 	if _, err := os.Stat(p); errors.Is(err, os.ErrNotExist) {
-		rule.Errorf(pos, `file %q does not exist in %q. it is specified at %q key in "runs" section in %q action`, f, dir, prop, name)
+		rule.ErrorfWithCode(pos, "invalid-runs", `file %q does not exist in %q. it is specified at %q key in "runs" section in %q action`, f, dir, prop, name)
 	}
 }
 
@@ -438,7 +491,7 @@ func (rule *RuleAction) checkLocalDockerActionRuns(r *ActionMetadataRuns, dir, n
 	} else if !isImageOnDockerRegistry(r.Image) {
 		rule.checkRunsFileExists(r.Image, dir, "image", name, pos)
 		if filepath.Base(filepath.FromSlash(r.Image)) != "Dockerfile" {
-			rule.Errorf(pos, `the local file %q referenced from "image" key must be named "Dockerfile" in %q action. the action is defined at %q`, r.Image, name, dir)
+			rule.ErrorfWithCode(pos, "invalid-runs", `the local file %q referenced from "image" key must be named "Dockerfile" in %q action. the action is defined at %q`, r.Image, name, dir)
 		}
 	}
 	rule.checkRunsFileExists(r.PreEntrypoint, dir, "pre-entrypoint", name, pos)
@@ -465,12 +518,12 @@ func (rule *RuleAction) checkLocalJavaScriptActionRuns(r *ActionMetadataRuns, di
 
 	rule.checkRunsFileExists(r.Pre, dir, "pre", name, pos)
 	if r.Pre == "" && r.PreIf != "" {
-		rule.Errorf(pos, `"pre" is required when "pre-if" is specified in "runs" section in %q action. the action is defined at %q`, name, dir)
+		rule.ErrorfWithCode(pos, "invalid-runs", `"pre" is required when "pre-if" is specified in "runs" section in %q action. the action is defined at %q`, name, dir)
 	}
 
 	rule.checkRunsFileExists(r.Post, dir, "post", name, pos)
 	if r.Post == "" && r.PostIf != "" {
-		rule.Errorf(pos, `"post" is required when "post-if" is specified in "runs" section in %q action. the action is defined at %q`, name, dir)
+		rule.ErrorfWithCode(pos, "invalid-runs", `"post" is required when "post-if" is specified in "runs" section in %q action. the action is defined at %q`, name, dir)
 	}
 
 	rule.checkInvalidRunsProps(pos, r, "JavaScript", name, dir, []string{"steps", "image", "pre-entrypoint", "entrypoint", "post-entrypoint", "args", "env"})
@@ -479,8 +532,9 @@ func (rule *RuleAction) checkLocalJavaScriptActionRuns(r *ActionMetadataRuns, di
 func (rule *RuleAction) checkLocalActionInputs(meta *ActionMetadata, pos *Pos) {
 	for _, i := range meta.Inputs {
 		if i.Deprecated && i.DeprecationMessage == "" {
-			rule.Errorf(
+			rule.ErrorfWithCode(
 				pos,
+				"invalid-metadata",
 				"input %q is deprecated but \"deprecationMessage\" is empty in metadata of %q action at %q",
 				i.Name,
 				meta.Name,
@@ -494,7 +548,7 @@ func (rule *RuleAction) checkLocalActionInputs(meta *ActionMetadata, pos *Pos) {
 func (rule *RuleAction) checkLocalActionRuns(meta *ActionMetadata, pos *Pos) {
 	switch r := &meta.Runs; r.Using {
 	case "":
-		rule.Errorf(pos, `"runs.using" is missing in local action %q defined at %q`, meta.Name, meta.Dir())
+		rule.ErrorfWithCode(pos, "invalid-runs", `"runs.using" is missing in local action %q defined at %q`, meta.Name, meta.Dir())
 	case "docker":
 		rule.checkLocalDockerActionRuns(r, meta.Dir(), meta.Name, pos)
 	case "composite":
@@ -502,7 +556,7 @@ func (rule *RuleAction) checkLocalActionRuns(meta *ActionMetadata, pos *Pos) {
 	case "node20", "node24":
 		rule.checkLocalJavaScriptActionRuns(r, meta.Dir(), meta.Name, pos)
 	default:
-		rule.Errorf(pos, `invalid runner name %q at runs.using in %q action defined at %q. valid runners are "composite", "docker", "node20", and "node24". see https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions#runs`, r.Using, meta.Name, meta.Dir())
+		rule.ErrorfWithCode(pos, "invalid-runs", `invalid runner name %q at runs.using in %q action defined at %q. valid runners are "composite", "docker", "node20", and "node24". see https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions#runs`, r.Using, meta.Name, meta.Dir())
 
 		// Probably invalid version of Node.js runner. Assume it is JavaScript action to find as many errors as possible
 		if strings.HasPrefix(r.Using, "node") {
@@ -525,8 +579,9 @@ func (rule *RuleAction) checkDockerAction(uri string, exec *ExecAction) {
 	}
 
 	if _, err := url.Parse(uri); err != nil {
-		rule.Errorf(
+		rule.ErrorfWithCode(
 			exec.Uses.Pos,
+			"invalid-format",
 			"URI for Docker container %q is invalid: %s (tag=%s)",
 			uri,
 			err.Error(),
@@ -535,22 +590,23 @@ func (rule *RuleAction) checkDockerAction(uri string, exec *ExecAction) {
 	}
 
 	if tagExists && tag == "" {
-		rule.Errorf(exec.Uses.Pos, "tag of Docker action should not be empty: %q", uri)
+		rule.ErrorfWithCode(exec.Uses.Pos, "invalid-format", "tag of Docker action should not be empty: %q", uri)
 	}
 }
 
 // https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions
 func (rule *RuleAction) checkLocalActionMetadata(meta *ActionMetadata, action *ExecAction) {
 	if meta.Name == "" {
-		rule.Errorf(action.Uses.Pos, "name is required in action metadata %q", meta.Path())
+		rule.ErrorfWithCode(action.Uses.Pos, "invalid-metadata", "name is required in action metadata %q", meta.Path())
 	}
 	if meta.Description == "" {
-		rule.Errorf(action.Uses.Pos, "description is required in metadata of %q action at %q", meta.Name, meta.Path())
+		rule.ErrorfWithCode(action.Uses.Pos, "invalid-metadata", "description is required in metadata of %q action at %q", meta.Name, meta.Path())
 	}
 	if meta.Branding.Icon != "" {
 		if _, ok := BrandingIcons[strings.ToLower(meta.Branding.Icon)]; !ok {
-			rule.Errorf(
+			rule.ErrorfWithCode(
 				action.Uses.Pos,
+				"invalid-metadata",
 				"incorrect icon name %q at branding.icon in metadata of %q action at %q. see the official document to know the exhaustive list of supported icons: https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions#brandingicon",
 				meta.Branding.Icon,
 				meta.Name,
@@ -560,8 +616,9 @@ func (rule *RuleAction) checkLocalActionMetadata(meta *ActionMetadata, action *E
 	}
 	if meta.Branding.Color != "" {
 		if _, ok := BrandingColors[strings.ToLower(meta.Branding.Color)]; !ok {
-			rule.Errorf(
+			rule.ErrorfWithCode(
 				action.Uses.Pos,
+				"invalid-metadata",
 				"incorrect color %q at branding.icon in metadata of %q action at %q. see the official document to know the exhaustive list of supported colors: https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions#brandingcolor",
 				meta.Branding.Color,
 				meta.Name,
@@ -577,7 +634,7 @@ func (rule *RuleAction) checkLocalActionMetadata(meta *ActionMetadata, action *E
 func (rule *RuleAction) checkLocalAction(spec string, action *ExecAction) {
 	meta, cached, err := rule.cache.FindMetadata(spec)
 	if err != nil {
-		rule.Error(action.Uses.Pos, err.Error())
+		rule.ErrorfWithCode(action.Uses.Pos, "metadata-error", "%s", err.Error())
 		return
 	}
 	if meta == nil {
@@ -605,8 +662,9 @@ func (rule *RuleAction) checkAction(meta *ActionMetadata, exec *ExecAction, desc
 			for _, i := range meta.Inputs {
 				ns = append(ns, i.Name)
 			}
-			rule.Errorf(
+			rule.ErrorfWithCode(
 				i.Name.Pos,
+				"undefined-input",
 				"input %q is not defined in action %s. available inputs are %s",
 				i.Name.Value,
 				describe(meta),
@@ -624,7 +682,7 @@ func (rule *RuleAction) checkAction(meta *ActionMetadata, exec *ExecAction, desc
 			if d != "" {
 				msg += ": " + d
 			}
-			rule.Error(i.Name.Pos, msg)
+			rule.ErrorfWithCode(i.Name.Pos, "deprecated-input", "%s", msg)
 		}
 	}
 
@@ -638,8 +696,9 @@ func (rule *RuleAction) checkAction(meta *ActionMetadata, exec *ExecAction, desc
 						ns = append(ns, i.Name)
 					}
 				}
-				rule.Errorf(
+				rule.ErrorfWithCode(
 					exec.Uses.Pos,
+					"missing-required-input",
 					"missing input %q which is required by action %s. all required inputs are %s",
 					i.Name,
 					describe(meta),
@@ -649,3 +708,41 @@ func (rule *RuleAction) checkAction(meta *ActionMetadata, exec *ExecAction, desc
 		}
 	}
 }
+
+// latestPopularActionSpec returns the spec of the latest version of the action in the popular
+// actions data set. The name is in "{owner}/{repo}" or "{owner}/{repo}/{path}" format.
+func latestPopularActionSpec(name string) (string, bool) {
+	prefix := name + "@"
+	latest := ""
+	var latestVer []int
+	for spec := range PopularActions {
+		if !strings.HasPrefix(spec, prefix) {
+			continue
+		}
+		if _, ok := OutdatedPopularActionSpecs[spec]; ok {
+			continue
+		}
+		v := actionRefVersion(spec[len(prefix):])
+		if latest == "" || slices.Compare(v, latestVer) > 0 || slices.Compare(v, latestVer) == 0 && spec > latest {
+			latest, latestVer = spec, v
+		}
+	}
+	return latest, latest != ""
+}
+
+var actionRefVersionPattern = regexp.MustCompile(`\d+`)
+
+// actionRefVersion extracts version numbers from the ref of action. For example, "v1.2.3" and
+// "releases/v1.2.3" are converted to [1, 2, 3].
+func actionRefVersion(ref string) []int {
+	ms := actionRefVersionPattern.FindAllString(ref, -1)
+	ret := make([]int, 0, len(ms))
+	for _, m := range ms {
+		i, err := strconv.Atoi(m)
+		if err != nil {
+			break
+		}
+		ret = append(ret, i)
+	}
+	return ret
+}
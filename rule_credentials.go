@@ -2,8 +2,70 @@ package actionlint
 
 import (
 	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
 )
 
+type credentialPattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// Patterns of well-known credentials. They are detected wherever they are put in workflows.
+// https://docs.github.com/en/code-security/secret-scanning/introduction/supported-secret-scanning-patterns
+var credentialPatterns = []credentialPattern{
+	{"GitHub personal access token", regexp.MustCompile(`\bghp_[A-Za-z0-9]{36}\b`)},
+	{"GitHub fine-grained personal access token", regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9]{22}_[A-Za-z0-9]{59}\b`)},
+	{"GitHub OAuth access token", regexp.MustCompile(`\bgho_[A-Za-z0-9]{36}\b`)},
+	{"GitHub App token", regexp.MustCompile(`\bgh[us]_[A-Za-z0-9]{36}\b`)},
+	{"GitHub refresh token", regexp.MustCompile(`\bghr_[A-Za-z0-9]{36}\b`)},
+	{"AWS access key ID", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z-]{10,}`)},
+	{"Slack webhook URL", regexp.MustCompile(`https://hooks\.slack\.com/services/T[0-9A-Za-z]+/B[0-9A-Za-z]+/[0-9A-Za-z]+`)},
+	{"private key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----`)},
+}
+
+// Names of env vars, inputs, and matrix values which likely hold credentials. Values of them are
+// checked with their entropy.
+var credentialNamePattern = regexp.MustCompile(`(?i)password|passwd|secret|token|api[-_]?key|access[-_]?key|private[-_]?key|credential`)
+
+// Values which can be random strings generated as credentials.
+var credentialValuePattern = regexp.MustCompile(`^[A-Za-z0-9+/=_.-]{20,}$`)
+
+const credentialEntropyThreshold = 3.5
+
+// shannonEntropy calculates Shannon entropy of the given string in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	e := 0.0
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		e -= p * math.Log2(p)
+	}
+	return e
+}
+
+// looksLikeRandomCredential returns whether the given value looks like a random string generated
+// as credential such as API key.
+func looksLikeRandomCredential(v string) bool {
+	if !credentialValuePattern.MatchString(v) {
+		return false
+	}
+	if !strings.ContainsAny(v, "0123456789") || strings.IndexFunc(v, func(r rune) bool {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+	}) < 0 {
+		return false
+	}
+	return shannonEntropy(v) >= credentialEntropyThreshold
+}
+
 // RuleCredentials is a rule to check credentials in workflows
 type RuleCredentials struct {
 	RuleBase
@@ -14,13 +76,59 @@ func NewRuleCredentials() *RuleCredentials {
 	return &RuleCredentials{
 		RuleBase: RuleBase{
 			name: "credentials",
-			desc: "Checks for credentials in \"services:\" configuration",
+			desc: "Checks for hard-coded credentials in workflows",
 		},
 	}
 }
 
+// ForkJob implements JobLocalPass interface.
+func (rule *RuleCredentials) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleCredentials) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Credentials put in workflow files directly are exposed to everyone who can read
+the repository. Passwords of "container:" and "services:", well-known token
+formats such as GitHub personal access tokens, and random-looking values of
+credential-like names are reported. Pass credentials via secrets instead.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    container:
+      image: example.com/owner/image
+      credentials:
+        username: user
+        password: pass123
+    steps:
+      - run: echo hello
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    container:
+      image: example.com/owner/image
+      credentials:
+        username: user
+        password: ${{ secrets.PASSWORD }}
+    steps:
+      - run: echo hello
+`
+	return m
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleCredentials) VisitWorkflowPre(n *Workflow) error {
+	rule.checkEnv(n.Env)
+	return nil
+}
+
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RuleCredentials) VisitJobPre(n *Job) error {
+	rule.checkEnv(n.Env)
 	if n.Container != nil {
 		rule.checkContainer("\"container\" section", n.Container)
 	}
@@ -29,16 +137,183 @@ func (rule *RuleCredentials) VisitJobPre(n *Job) error {
 			rule.checkContainer(fmt.Sprintf("%q service", s.Name.Value), s.Container)
 		}
 	}
+	if n.Strategy != nil && n.Strategy.Matrix != nil {
+		rule.checkMatrix(n.Strategy.Matrix)
+	}
+	if n.WorkflowCall != nil {
+		for _, i := range n.WorkflowCall.Inputs {
+			rule.checkString(i.Value, fmt.Sprintf("input %q", i.Name.Value), i.Name.Value)
+		}
+		for _, s := range n.WorkflowCall.Secrets {
+			rule.checkString(s.Value, fmt.Sprintf("secret %q", s.Name.Value), s.Name.Value)
+		}
+	}
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleCredentials) VisitStep(n *Step) error {
+	rule.checkEnv(n.Env)
+	switch e := n.Exec.(type) {
+	case *ExecRun:
+		rule.checkString(e.Run, "\"run:\" script", "")
+	case *ExecAction:
+		for _, i := range e.Inputs {
+			rule.checkString(i.Value, fmt.Sprintf("input %q", i.Name.Value), i.Name.Value)
+		}
+	}
 	return nil
 }
 
 func (rule *RuleCredentials) checkContainer(where string, n *Container) {
+	rule.checkEnv(n.Env)
+
 	if n.Credentials == nil || n.Credentials.Password == nil {
 		return
 	}
 
 	p := n.Credentials.Password
 	if !p.IsExpressionAssigned() {
-		rule.Errorf(p.Pos, "\"password\" section in %s should be specified via secrets. do not put password value directly", where)
+		rule.ErrorfWithCode(p.Pos, "hardcoded-password", "\"password\" section in %s should be specified via secrets. do not put password value directly", where)
+	}
+}
+
+func (rule *RuleCredentials) checkEnv(n *Env) {
+	if n == nil {
+		return
+	}
+	for _, v := range n.Vars {
+		rule.checkString(v.Value, fmt.Sprintf("env var %q", v.Name.Value), v.Name.Value)
+	}
+}
+
+func (rule *RuleCredentials) checkMatrix(n *Matrix) {
+	for _, r := range n.Rows {
+		for _, v := range r.Values {
+			rule.checkRawYAML(v, r.Name.Value)
+		}
+	}
+	for _, cs := range []*MatrixCombinations{n.Include, n.Exclude} {
+		if cs == nil {
+			continue
+		}
+		for _, c := range cs.Combinations {
+			for _, a := range c.Assigns {
+				rule.checkRawYAML(a.Value, a.Key.Value)
+			}
+		}
+	}
+}
+
+func (rule *RuleCredentials) checkRawYAML(v RawYAMLValue, name string) {
+	switch v := v.(type) {
+	case *RawYAMLObject:
+		for k, p := range v.Props {
+			rule.checkRawYAML(p, k)
+		}
+	case *RawYAMLArray:
+		for _, e := range v.Elems {
+			rule.checkRawYAML(e, name)
+		}
+	case *RawYAMLString:
+		// Quotes and block styles are unknown for raw YAML values. Report the error at the start
+		// of the literal
+		s := &String{Value: v.Value, Pos: v.Pos()}
+		rule.checkStringAt(s, fmt.Sprintf("matrix value %q", name), name, false)
+	}
+}
+
+func (rule *RuleCredentials) checkString(s *String, where, name string) {
+	rule.checkStringAt(s, where, name, true)
+}
+
+// checkStringAt checks the string value contains well-known credentials. When name is not empty
+// and it looks like a name for credential, the value is also checked with its entropy. When offset
+// is true, the error is reported at the position of the credential in the value as precise as
+// possible.
+func (rule *RuleCredentials) checkStringAt(s *String, where, name string, offset bool) {
+	if s == nil {
+		return
+	}
+
+	found := false
+	for _, p := range credentialPatterns {
+		for _, m := range p.re.FindAllStringIndex(s.Value, -1) {
+			found = true
+			pos, end := s.Pos, (*Pos)(nil)
+			if offset {
+				pos, end = credentialRangeIn(s, m[0], m[1])
+			}
+			err := rule.ErrorfWithCode(pos, "hardcoded-token", "%s is hard-coded in %s. it should be specified via secrets. do not put credentials directly", p.kind, where)
+			err.setEnd(end)
+		}
+	}
+	if found || name == "" || s.ContainsExpression() || !credentialNamePattern.MatchString(name) {
+		return
+	}
+
+	v := strings.TrimSpace(s.Value)
+	if looksLikeRandomCredential(v) {
+		pos, end := s.Pos, (*Pos)(nil)
+		if offset {
+			i := strings.Index(s.Value, v)
+			pos, end = credentialRangeIn(s, i, i+len(v))
+		}
+		err := rule.ErrorfWithCode(pos, "random-credential", "value of %s looks like hard-coded credential. it should be specified via secrets. do not put credentials directly", where)
+		err.setEnd(end)
+	}
+}
+
+// credentialRangeIn returns the range from the start offset to the end offset (exclusive) in the
+// string value. The range in the source can be computed only when the value is written as-is in
+// one line of the source or is a literal block scalar with "|". Otherwise (e.g. a double-quoted
+// string containing escapes or a folded string), the range of the whole string is returned.
+func credentialRangeIn(s *String, start, end int) (*Pos, *Pos) {
+	if s.EndPos == nil {
+		return s.Pos, nil
+	}
+
+	if !strings.Contains(s.Value, "\n") {
+		// Columns in the source are counted in characters, not in bytes
+		col := s.Pos.Col + utf8.RuneCountInString(s.Value[:start])
+		width := utf8.RuneCountInString(s.Value)
+		if s.Quoted {
+			col++
+			width += 2 // Open and close quotes
+		}
+		if s.EndPos.Line != s.Pos.Line || s.EndPos.Col-s.Pos.Col+1 != width {
+			return s.Pos, s.EndPos
+		}
+		return &Pos{s.Pos.Line, col}, &Pos{s.Pos.Line, col + utf8.RuneCountInString(s.Value[start:end]) - 1}
+	}
+
+	if !s.Literal {
+		return s.Pos, s.EndPos
+	}
+
+	// Lines of the literal block scalar follow the header line. Compute the indentation of the
+	// block from the end position, which is the last character of the last non-blank line, in the
+	// same way as the parser computes it
+	lines := strings.Split(s.Value, "\n")
+	last := len(lines) - 1
+	for last >= 0 && strings.TrimRight(lines[last], " \t") == "" {
+		last--
+	}
+	if last < 0 || s.EndPos.Line != s.Pos.Line+1+last {
+		return s.Pos, s.EndPos
+	}
+	indent := s.EndPos.Col - utf8.RuneCountInString(strings.TrimRight(lines[last], " \t"))
+	if indent < 0 {
+		return s.Pos, s.EndPos
+	}
+
+	posAt := func(offset int) *Pos {
+		pre := s.Value[:offset]
+		l := strings.Count(pre, "\n")
+		c := utf8.RuneCountInString(pre[strings.LastIndexByte(pre, '\n')+1:])
+		return &Pos{s.Pos.Line + 1 + l, indent + c + 1}
 	}
+	// end is exclusive. Compute the position of the last character of the range
+	_, w := utf8.DecodeLastRuneInString(s.Value[:end])
+	return posAt(start), posAt(end - w)
 }
@@ -0,0 +1,108 @@
+package actionlint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRuleCredentialsRangeInString(t *testing.T) {
+	tests := []struct {
+		what       string
+		str        *String
+		start, end int
+		pos, epos  *Pos
+	}{
+		{
+			what:  "plain",
+			str:   &String{Value: "foo secret bar", Pos: &Pos{3, 10}, EndPos: &Pos{3, 23}},
+			start: 4,
+			end:   10,
+			pos:   &Pos{3, 14},
+			epos:  &Pos{3, 19},
+		},
+		{
+			what:  "quoted",
+			str:   &String{Value: "foo secret", Quoted: true, Pos: &Pos{3, 10}, EndPos: &Pos{3, 21}},
+			start: 4,
+			end:   10,
+			pos:   &Pos{3, 15},
+			epos:  &Pos{3, 20},
+		},
+		{
+			what:  "non-ASCII characters",
+			str:   &String{Value: "あい secret", Pos: &Pos{3, 10}, EndPos: &Pos{3, 18}},
+			start: 7,
+			end:   13,
+			pos:   &Pos{3, 13},
+			epos:  &Pos{3, 18},
+		},
+		{
+			what:  "double-quoted with escapes",
+			str:   &String{Value: "\tsecret", Quoted: true, Pos: &Pos{3, 10}, EndPos: &Pos{3, 19}},
+			start: 1,
+			end:   7,
+			pos:   &Pos{3, 10},
+			epos:  &Pos{3, 19},
+		},
+		{
+			what:  "plain folded into multiple lines",
+			str:   &String{Value: "foo secret", Pos: &Pos{3, 10}, EndPos: &Pos{4, 15}},
+			start: 4,
+			end:   10,
+			pos:   &Pos{3, 10},
+			epos:  &Pos{4, 15},
+		},
+		{
+			what:  "unknown end position",
+			str:   &String{Value: "foo secret", Pos: &Pos{3, 10}},
+			start: 4,
+			end:   10,
+			pos:   &Pos{3, 10},
+		},
+		{
+			what:  "literal block",
+			str:   &String{Value: "foo\nあ secret\n", Literal: true, Pos: &Pos{3, 10}, EndPos: &Pos{5, 15}},
+			start: 8,
+			end:   14,
+			pos:   &Pos{5, 10},
+			epos:  &Pos{5, 15},
+		},
+		{
+			what:  "literal block with blank lines",
+			str:   &String{Value: "\nfoo\n\n  secret\nbar\n\n", Literal: true, Pos: &Pos{3, 10}, EndPos: &Pos{8, 9}},
+			start: 8,
+			end:   14,
+			pos:   &Pos{7, 9},
+			epos:  &Pos{7, 14},
+		},
+		{
+			what:  "literal block with unexpected end position",
+			str:   &String{Value: "foo\nsecret\n", Literal: true, Pos: &Pos{3, 10}, EndPos: &Pos{6, 12}},
+			start: 4,
+			end:   10,
+			pos:   &Pos{3, 10},
+			epos:  &Pos{6, 12},
+		},
+		{
+			what:  "folded block",
+			str:   &String{Value: "foo\nsecret\n", Pos: &Pos{3, 10}, EndPos: &Pos{5, 12}},
+			start: 4,
+			end:   10,
+			pos:   &Pos{3, 10},
+			epos:  &Pos{5, 12},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.what, func(t *testing.T) {
+			pos, epos := credentialRangeIn(tc.str, tc.start, tc.end)
+			if diff := cmp.Diff(tc.pos, pos); diff != "" {
+				t.Errorf("start position mismatch: %s", diff)
+			}
+			if diff := cmp.Diff(tc.epos, epos); diff != "" {
+				t.Errorf("end position mismatch: %s", diff)
+			}
+		})
+	}
+}
@@ -23,6 +23,34 @@ func NewRuleDeprecatedCommands() *RuleDeprecatedCommands {
 	}
 }
 
+// ForkJob implements JobLocalPass interface.
+func (rule *RuleDeprecatedCommands) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleDeprecatedCommands) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Workflow commands "set-output", "save-state", "set-env" and "add-path" are
+deprecated. Use $GITHUB_OUTPUT, $GITHUB_STATE, $GITHUB_ENV and $GITHUB_PATH
+environment files instead.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "::set-output name=foo::bar"
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "foo=bar" >> "$GITHUB_OUTPUT"
+`
+	return m
+}
+
 // VisitStep is callback when visiting Step node.
 func (rule *RuleDeprecatedCommands) VisitStep(n *Step) error {
 	if r, ok := n.Exec.(*ExecRun); ok && r.Run != nil {
@@ -46,8 +74,9 @@ func (rule *RuleDeprecatedCommands) VisitStep(n *Step) error {
 				panic("unreachable")
 			}
 
-			rule.Errorf(
+			rule.ErrorfWithCode(
 				r.Run.Pos,
+				"deprecated-command",
 				"workflow command %q was deprecated. use `%s` instead: https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions",
 				c,
 				a,
@@ -0,0 +1,254 @@
+package actionlint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Pattern to detect writes to environment files like `echo "..." >> $GITHUB_ENV` in bash or
+// `"..." | Out-File -FilePath $env:GITHUB_ENV -Append` in PowerShell.
+var envFileWritePattern = regexp.MustCompile(`(?:>|\btee\b[^|;]*|\bOut-File\b[^|;]*|\bAdd-Content\b[^|;]*)\s*["']?\$(?:env:)?\{?(GITHUB_ENV|GITHUB_PATH|GITHUB_OUTPUT)\b`)
+
+// Pattern to find environment variable references like $FOO, ${FOO}, or $env:FOO.
+var envVarRefPattern = regexp.MustCompile(`\$(?:env:|\{)?([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Pattern to find a start of here document like `<<EOF`, `<<-'EOF'`, or `<< "EOF"`.
+var heredocStartPattern = regexp.MustCompile(`<<-?\s*["']?([A-Za-z_][A-Za-z0-9_]*)["']?`)
+
+var envFileRisks = map[string]string{
+	"GITHUB_ENV":    "arbitrary environment variables such as LD_PRELOAD or NODE_OPTIONS into subsequent steps",
+	"GITHUB_PATH":   "arbitrary directories into PATH of subsequent steps",
+	"GITHUB_OUTPUT": "arbitrary step outputs with newlines",
+}
+
+// envFileWrite is a write to an environment file in a script.
+type envFileWrite struct {
+	file string
+	// line is a line number of the write in the script. It starts from 1.
+	line int
+	// content is the text which may be written to the file.
+	content string
+}
+
+// findEnvFileWrites finds writes to environment files in the script. Lines in a here document or a
+// `{ ... }` block redirected to the file are also considered as the content.
+func findEnvFileWrites(script string) []*envFileWrite {
+	lines := strings.Split(script, "\n")
+	ret := []*envFileWrite{}
+	block := -1
+	for i, l := range lines {
+		t := strings.TrimSpace(l)
+		if t == "{" {
+			block = i
+		}
+
+		m := envFileWritePattern.FindStringSubmatch(l)
+		if m == nil {
+			continue
+		}
+
+		w := &envFileWrite{file: m[1], line: i + 1, content: l}
+		if strings.HasPrefix(t, "}") && block >= 0 {
+			w.content = strings.Join(lines[block:i+1], "\n")
+			block = -1
+		} else if h := heredocStartPattern.FindStringSubmatch(l); h != nil {
+			end := i + 1
+			for end < len(lines) && strings.TrimSpace(lines[end]) != h[1] {
+				end++
+			}
+			w.content = strings.Join(lines[i:end], "\n")
+		}
+		ret = append(ret, w)
+	}
+	return ret
+}
+
+// RuleEnvFile is a rule to check writes to environment files ($GITHUB_ENV, $GITHUB_PATH, and
+// $GITHUB_OUTPUT) in "run:" scripts. Writing untrusted values to the files allows attackers to
+// inject environment variables, paths, or outputs into subsequent steps.
+// https://docs.github.com/en/actions/reference/workflows-and-actions/workflow-commands#environment-files
+type RuleEnvFile struct {
+	RuleBase
+	roots       UntrustedInputSearchRoots
+	untrusted   *UntrustedInputChecker
+	workflowEnv map[string][]string
+	jobEnv      map[string][]string
+}
+
+// NewRuleEnvFile creates a new RuleEnvFile instance.
+func NewRuleEnvFile() *RuleEnvFile {
+	return &RuleEnvFile{
+		RuleBase: RuleBase{
+			name: "env-file",
+			desc: "Checks for potentially untrusted values written to $GITHUB_ENV, $GITHUB_PATH, and $GITHUB_OUTPUT at \"run:\"",
+		},
+	}
+}
+
+// ForkJob implements JobLocalPass interface. The copy shares the untrusted input roots and the env
+// vars of the workflow. The untrusted input checker is created for the copy since it has a state.
+func (rule *RuleEnvFile) ForkJob() Pass {
+	r := forkRule(rule)
+	r.untrusted = NewUntrustedInputChecker(rule.roots)
+	return r
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleEnvFile) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Untrusted values written to $GITHUB_ENV, $GITHUB_PATH and $GITHUB_OUTPUT are
+checked. Attackers can inject arbitrary environment variables or outputs with
+newlines in the values. This rule tracks untrusted inputs passed via "env:".`
+	m.BadExample = `on: pull_request_target
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "TITLE=$TITLE" >> "$GITHUB_ENV"
+        env:
+          TITLE: ${{ github.event.pull_request.title }}
+`
+	m.GoodExample = `on: pull_request_target
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "TITLE=$TITLE"
+        env:
+          TITLE: ${{ github.event.pull_request.title }}
+`
+	return m
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleEnvFile) VisitWorkflowPre(n *Workflow) error {
+	rule.roots = rule.config.UntrustedInputSearchRoots()
+	rule.untrusted = NewUntrustedInputChecker(rule.roots)
+	rule.workflowEnv = rule.untrustedEnv(n.Env)
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleEnvFile) VisitJobPre(n *Job) error {
+	rule.jobEnv = rule.untrustedEnv(n.Env)
+	return nil
+}
+
+// VisitJobPost is callback when visiting Job node after visiting its children.
+func (rule *RuleEnvFile) VisitJobPost(n *Job) error {
+	rule.jobEnv = nil
+	return nil
+}
+
+// VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
+func (rule *RuleEnvFile) VisitWorkflowPost(n *Workflow) error {
+	rule.workflowEnv = nil
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleEnvFile) VisitStep(n *Step) error {
+	e, ok := n.Exec.(*ExecRun)
+	if !ok || e.Run == nil {
+		return nil
+	}
+
+	ws := findEnvFileWrites(e.Run.Value)
+	if len(ws) == 0 {
+		return nil
+	}
+
+	stepEnv := rule.untrustedEnv(n.Env)
+	for _, w := range ws {
+		// Untrusted inputs at ${{ }} placeholders in the script are not checked here since they are
+		// already reported by RuleExpression
+		var what []string
+		for _, m := range envVarRefPattern.FindAllStringSubmatch(w.content, -1) {
+			if m[1] == w.file {
+				continue
+			}
+			for _, i := range lookupUntrustedEnv(strings.ToLower(m[1]), stepEnv, rule.jobEnv, rule.workflowEnv) {
+				what = append(what, fmt.Sprintf("env var %q bound to potentially untrusted input %q", m[1], i))
+			}
+		}
+
+		seen := map[string]struct{}{}
+		for _, s := range what {
+			if _, ok := seen[s]; ok {
+				continue
+			}
+			seen[s] = struct{}{}
+			err := rule.ErrorfWithCode(
+				e.Run.Pos,
+				"untrusted-write",
+				"%s is written to $%s at line %d in the script. attackers can inject %s. validate the value before writing it. see https://docs.github.com/en/actions/reference/security/secure-use#good-practices-for-mitigating-script-injection-attacks for more details",
+				s,
+				w.file,
+				w.line,
+				envFileRisks[w.file],
+			)
+			err.setEnd(e.Run.EndPos)
+		}
+	}
+	return nil
+}
+
+// untrustedInputsIn returns paths to untrusted inputs used in ${{ }} placeholders in the string.
+func (rule *RuleEnvFile) untrustedInputsIn(s string) []string {
+	var ret []string
+	for {
+		i := strings.Index(s, "${{")
+		if i < 0 {
+			return ret
+		}
+		s = s[i+3:]
+
+		l := NewExprLexer(s)
+		expr, err := NewExprParser().Parse(l)
+		if err != nil {
+			return ret // Syntax error is reported by RuleExpression
+		}
+
+		rule.untrusted.Init()
+		VisitExprNode(expr, func(n, p ExprNode, entering bool) {
+			if entering {
+				rule.untrusted.OnVisitNodeEnter(n)
+			} else {
+				rule.untrusted.OnVisitNodeLeave(n)
+			}
+		})
+		rule.untrusted.OnVisitEnd()
+		ret = append(ret, rule.untrusted.foundInputs()...)
+
+		s = s[l.Offset():]
+	}
+}
+
+// untrustedEnv returns a map from env var names to untrusted inputs used in their values. Env vars
+// whose values are trusted are mapped to nil to shadow the same env vars at outer levels. Env vars
+// set by one ${{ }} expression like `env: ${{ fromJSON(...) }}` are skipped since their names are
+// unknown until running the workflow.
+func (rule *RuleEnvFile) untrustedEnv(env *Env) map[string][]string {
+	if env == nil || env.Vars == nil {
+		return nil
+	}
+	ret := make(map[string][]string, len(env.Vars))
+	for k, v := range env.Vars {
+		var is []string
+		if v.Value != nil {
+			is = rule.untrustedInputsIn(v.Value.Value)
+		}
+		ret[k] = is
+	}
+	return ret
+}
+
+func lookupUntrustedEnv(name string, envs ...map[string][]string) []string {
+	for _, env := range envs {
+		if is, ok := env[name]; ok {
+			return is
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleEnvFileFindWrites(t *testing.T) {
+	tests := []struct {
+		what    string
+		script  string
+		file    string
+		line    int
+		content string
+	}{
+		{
+			what:    "redirect",
+			script:  "echo foo\necho \"FOO=$FOO\" >> $GITHUB_ENV",
+			file:    "GITHUB_ENV",
+			line:    2,
+			content: "echo \"FOO=$FOO\" >> $GITHUB_ENV",
+		},
+		{
+			what:    "quoted file path with braces",
+			script:  `echo "$FOO" >> "${GITHUB_PATH}"`,
+			file:    "GITHUB_PATH",
+			line:    1,
+			content: `echo "$FOO" >> "${GITHUB_PATH}"`,
+		},
+		{
+			what:    "tee",
+			script:  `echo "foo=$FOO" | tee -a "$GITHUB_OUTPUT"`,
+			file:    "GITHUB_OUTPUT",
+			line:    1,
+			content: `echo "foo=$FOO" | tee -a "$GITHUB_OUTPUT"`,
+		},
+		{
+			what:    "PowerShell",
+			script:  `"FOO=$env:FOO" | Out-File -FilePath $env:GITHUB_ENV -Append`,
+			file:    "GITHUB_ENV",
+			line:    1,
+			content: `"FOO=$env:FOO" | Out-File -FilePath $env:GITHUB_ENV -Append`,
+		},
+		{
+			what:    "here document",
+			script:  "cat <<-'EOS' >> $GITHUB_ENV\nFOO=$FOO\nEOS\necho $BAR",
+			file:    "GITHUB_ENV",
+			line:    1,
+			content: "cat <<-'EOS' >> $GITHUB_ENV\nFOO=$FOO",
+		},
+		{
+			what:    "block",
+			script:  "echo $BAR\n{\n  echo 'foo<<EOS'\n  echo \"$FOO\"\n  echo EOS\n} >> \"$GITHUB_OUTPUT\"",
+			file:    "GITHUB_OUTPUT",
+			line:    6,
+			content: "{\n  echo 'foo<<EOS'\n  echo \"$FOO\"\n  echo EOS\n} >> \"$GITHUB_OUTPUT\"",
+		},
+		{
+			what:   "read",
+			script: "cat $GITHUB_ENV",
+		},
+		{
+			what:   "other file",
+			script: "echo $FOO >> $GITHUB_STEP_SUMMARY",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.what, func(t *testing.T) {
+			ws := findEnvFileWrites(tc.script)
+			if tc.file == "" {
+				if len(ws) > 0 {
+					t.Fatalf("unexpected writes: %#v", ws[0])
+				}
+				return
+			}
+			if len(ws) != 1 {
+				t.Fatalf("wanted 1 write but got %d writes", len(ws))
+			}
+			w := ws[0]
+			if w.file != tc.file {
+				t.Errorf("wanted file %q but got %q", tc.file, w.file)
+			}
+			if w.line != tc.line {
+				t.Errorf("wanted line %d but got %d", tc.line, w.line)
+			}
+			if w.content != tc.content {
+				t.Errorf("wanted content %q but got %q", tc.content, w.content)
+			}
+		})
+	}
+}
+
+func TestRuleEnvFileDetectUntrustedWrites(t *testing.T) {
+	tests := []struct {
+		what string
+		src  string
+		cfg  *Config
+		want []string
+	}{
+		{
+			// This is reported by RuleExpression
+			what: "untrusted input at placeholder",
+			src: `
+on: issues
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "TITLE=${{ github.event.issue.title }}" >> "$GITHUB_ENV"
+`,
+		},
+		{
+			what: "env vars set by expression",
+			src: `
+on: issues
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "TITLE=$TITLE" >> "$GITHUB_ENV"
+        env: ${{ fromJSON(github.event.issue.body) }}
+`,
+		},
+		{
+			what: "env vars at each level",
+			src: `
+on: issues
+env:
+  A: ${{ github.event.issue.title }}
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    env:
+      B: ${{ github.event.issue.body }}
+    steps:
+      - run: |
+          echo "$A" >> "$GITHUB_PATH"
+          echo "${B}" >> "$GITHUB_PATH"
+          echo "$C" >> "$GITHUB_PATH"
+        env:
+          C: ${{ github.head_ref }}
+`,
+			want: []string{
+				`:11:14: env var "A" bound to potentially untrusted input "github.event.issue.title" is written to $GITHUB_PATH at line 1`,
+				`:11:14: env var "B" bound to potentially untrusted input "github.event.issue.body" is written to $GITHUB_PATH at line 2`,
+				`:11:14: env var "C" bound to potentially untrusted input "github.head_ref" is written to $GITHUB_PATH at line 3`,
+			},
+		},
+		{
+			what: "shadowed env var",
+			src: `
+on: issues
+env:
+  A: ${{ github.event.issue.title }}
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "A=$A" >> "$GITHUB_ENV"
+        env:
+          A: foo
+`,
+		},
+		{
+			what: "untrusted input not written",
+			src: `
+on: issues
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: |
+          echo "$A"
+          echo "foo=bar" >> "$GITHUB_OUTPUT"
+        env:
+          A: ${{ github.event.issue.title }}
+`,
+		},
+		{
+			what: "safe function call",
+			src: `
+on: issues
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "BUG=${{ contains(github.event.issue.title, 'bug') }}" >> "$GITHUB_ENV"
+`,
+		},
+		{
+			what: "configured untrusted inputs",
+			src: `
+on: repository_dispatch
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "$A" >> "$GITHUB_PATH"
+        env:
+          A: ${{ github.event.client_payload.path }}
+      - run: echo "$B" >> "$GITHUB_PATH"
+        env:
+          B: ${{ github.head_ref }}
+`,
+			cfg: func() *Config {
+				c := &Config{}
+				c.UntrustedInputs.Untrusted = []string{"github.event.client_payload.*"}
+				c.UntrustedInputs.Safe = []string{"github.head_ref"}
+				return c
+			}(),
+			want: []string{
+				`:7:14: env var "A" bound to potentially untrusted input "github.event.client_payload.*" is written to $GITHUB_PATH`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.what, func(t *testing.T) {
+			w, errs := Parse([]byte(tc.src))
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
+			r := NewRuleEnvFile()
+			r.SetConfig(tc.cfg)
+			v := NewVisitor()
+			v.AddPass(r)
+			if err := v.Visit(w); err != nil {
+				t.Fatal(err)
+			}
+
+			errs = r.Errs()
+			if len(errs) != len(tc.want) {
+				t.Fatalf("wanted %d errors but got %d errors: %v", len(tc.want), len(errs), errs)
+			}
+			for i, err := range errs {
+				if have := err.Error(); !strings.Contains(have, tc.want[i]) {
+					t.Errorf("error %q does not contain %q", have, tc.want[i])
+				}
+			}
+		})
+	}
+}
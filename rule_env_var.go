@@ -17,6 +17,37 @@ func NewRuleEnvVar() *RuleEnvVar {
 	}
 }
 
+// ForkJob implements JobLocalPass interface.
+func (rule *RuleEnvVar) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleEnvVar) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Environment variable names at "env:" are checked. Names containing "=" or
+whitespaces are reported because they cannot be set as environment variables.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    env:
+      FOO BAR: hello
+    steps:
+      - run: echo "$FOO_BAR"
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    env:
+      FOO_BAR: hello
+    steps:
+      - run: echo "$FOO_BAR"
+`
+	return m
+}
+
 // VisitStep is callback when visiting Step node.
 func (rule *RuleEnvVar) VisitStep(n *Step) error {
 	rule.checkEnv(n.Env)
@@ -52,8 +83,9 @@ func (rule *RuleEnvVar) checkEnv(env *Env) {
 			continue // Key name can contain expressions (#312)
 		}
 		if strings.ContainsAny(v.Name.Value, "&= 	") {
-			rule.Errorf(
+			rule.ErrorfWithCode(
 				v.Name.Pos,
+				"invalid-name",
 				"environment variable name %q is invalid. '&', '=' and spaces should not be contained",
 				v.Name.Value,
 			)
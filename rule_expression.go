@@ -1,6 +1,7 @@
 package actionlint
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -29,6 +30,7 @@ type RuleExpression struct {
 	workflow         *Workflow
 	localActions     *LocalActionsCache
 	localWorkflows   *LocalReusableWorkflowCache
+	untrustedRoots   UntrustedInputSearchRoots
 }
 
 // NewRuleExpression creates new RuleExpression instance.
@@ -51,8 +53,42 @@ func NewRuleExpression(actionsCache *LocalActionsCache, workflowCache *LocalReus
 	}
 }
 
+// ForkJob implements JobLocalPass interface. The copy shares the workflow-level types, the workflow,
+// the untrusted input roots, and the caches of local actions and reusable workflows. The job-level
+// types are assigned in VisitJobPre.
+func (rule *RuleExpression) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleExpression) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Expressions in ${{ }} are parsed and type-checked. Syntax errors, undefined
+properties of contexts, wrong types of operands and function arguments, and
+contexts or functions which are not available at the place are reported.
+Untrusted inputs which may cause script injection are also reported.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    if: ${{ github.event_nam == 'push' }}
+    steps:
+      - run: echo hello
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    if: ${{ github.event_name == 'push' }}
+    steps:
+      - run: echo hello
+`
+	return m
+}
+
 // VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
 func (rule *RuleExpression) VisitWorkflowPre(n *Workflow) error {
+	rule.untrustedRoots = rule.config.UntrustedInputSearchRoots()
 	rule.checkString(n.Name, "")
 
 	for _, e := range n.On {
@@ -127,7 +163,7 @@ func (rule *RuleExpression) VisitWorkflowPre(n *Workflow) error {
 						case BoolType, AnyType:
 							// ok
 						default:
-							rule.Errorf(i.Default.Pos, "type of input %q must be bool but found type %s", i.Name.Value, ts[0].ty.String())
+							rule.ErrorfWithCode(i.Default.Pos, "type-mismatch", "type of input %q must be bool but found type %s", i.Name.Value, ts[0].ty.String())
 						}
 					}
 				case WorkflowCallEventInputTypeNumber:
@@ -137,7 +173,7 @@ func (rule *RuleExpression) VisitWorkflowPre(n *Workflow) error {
 						case NumberType, AnyType:
 							// ok
 						default:
-							rule.Errorf(i.Default.Pos, "type of input %q must be number but found type %s", i.Name.Value, ts[0].ty.String())
+							rule.ErrorfWithCode(i.Default.Pos, "type-mismatch", "type of input %q must be number but found type %s", i.Name.Value, ts[0].ty.String())
 						}
 					}
 				default:
@@ -216,7 +252,7 @@ func (rule *RuleExpression) VisitJobPre(n *Job) error {
 				case *ArrayType, StringType, AnyType:
 					// OK
 				default:
-					rule.Errorf(n.RunsOn.LabelsExpr.Pos, "type of expression at \"runs-on\" must be string or array but found type %q", ty.String())
+					rule.ErrorfWithCode(n.RunsOn.LabelsExpr.Pos, "type-mismatch", "type of expression at \"runs-on\" must be string or array but found type %q", ty.String())
 				}
 			}
 		} else {
@@ -333,7 +369,7 @@ func (rule *RuleExpression) getActionOutputsType(spec *String) *ObjectType {
 	if strings.HasPrefix(spec.Value, "./") {
 		meta, _, err := rule.localActions.FindMetadata(spec.Value)
 		if err != nil {
-			rule.Error(spec.Pos, err.Error())
+			rule.ErrorfWithCode(spec.Pos, "metadata-error", "%s", err.Error())
 			return NewMapObjectType(StringType{})
 		}
 		if meta == nil {
@@ -365,7 +401,7 @@ func (rule *RuleExpression) getWorkflowCallOutputsType(call *WorkflowCall) *Obje
 
 	m, err := rule.localWorkflows.FindMetadata(call.Uses.Value)
 	if err != nil {
-		rule.Error(call.Uses.Pos, err.Error())
+		rule.ErrorfWithCode(call.Uses.Pos, "metadata-error", "%s", err.Error())
 		return NewMapObjectType(StringType{})
 	}
 	if m == nil {
@@ -392,7 +428,7 @@ func (rule *RuleExpression) checkOneExpression(s *String, what, workflowKey stri
 
 	if len(ts) != 1 {
 		// This case should be unreachable since only one ${{ }} is included is checked by parser
-		rule.Errorf(s.Pos, "one ${{ }} expression should be included in %q value but got %d expressions", what, len(ts))
+		rule.ErrorfWithCode(s.Pos, "multiple-expressions", "one ${{ }} expression should be included in %q value but got %d expressions", what, len(ts))
 		return nil
 	}
 
@@ -407,7 +443,7 @@ func (rule *RuleExpression) checkObjectTy(ty ExprType, pos *Pos, what string) Ex
 	case *ObjectType, AnyType:
 		return ty
 	default:
-		rule.Errorf(pos, "type of expression at %q must be object but found type %s", what, ty.String())
+		rule.ErrorfWithCode(pos, "type-mismatch", "type of expression at %q must be object but found type %s", what, ty.String())
 		return nil
 	}
 }
@@ -420,7 +456,7 @@ func (rule *RuleExpression) checkArrayTy(ty ExprType, pos *Pos, what string) Exp
 	case *ArrayType, AnyType:
 		return ty
 	default:
-		rule.Errorf(pos, "type of expression at %q must be array but found type %s", what, ty.String())
+		rule.ErrorfWithCode(pos, "type-mismatch", "type of expression at %q must be array but found type %s", what, ty.String())
 		return nil
 	}
 }
@@ -433,7 +469,7 @@ func (rule *RuleExpression) checkNumberTy(ty ExprType, pos *Pos, what string) Ex
 	case NumberType, AnyType:
 		return ty
 	default:
-		rule.Errorf(pos, "type of expression at %q must be number but found type %s", what, ty.String())
+		rule.ErrorfWithCode(pos, "type-mismatch", "type of expression at %q must be number but found type %s", what, ty.String())
 		return nil
 	}
 }
@@ -530,7 +566,7 @@ func (rule *RuleExpression) checkWorkflowCall(c *WorkflowCall) {
 
 	m, err := rule.localWorkflows.FindMetadata(c.Uses.Value)
 	if err != nil {
-		rule.Error(c.Uses.Pos, err.Error())
+		rule.ErrorfWithCode(c.Uses.Pos, "metadata-error", "%s", err.Error())
 	}
 
 	for n, i := range c.Inputs {
@@ -570,14 +606,22 @@ func (rule *RuleExpression) checkWorkflowCall(c *WorkflowCall) {
 		}
 
 		if !mi.Type.Assignable(ty) {
-			rule.Errorf(
+			var related []RelatedLocation
+			if mi.Pos != nil && m.Path != "" {
+				r := relatedAt(mi.Pos, fmt.Sprintf("input %q is typed as %s here", mi.Name, mi.Type.String()))
+				r.Filepath = m.Path
+				related = []RelatedLocation{r}
+			}
+			err := rule.ErrorfWithCode(
 				i.Value.Pos,
+				"workflow-call-input",
 				"input %q is typed as %s by reusable workflow %q. %s value cannot be assigned",
 				mi.Name,
 				mi.Type.String(),
 				c.Uses.Value,
 				ty.String(),
 			)
+			err.Related = related
 		}
 	}
 
@@ -659,7 +703,7 @@ func (rule *RuleExpression) checkIfCondition(str *String, workflowKey string) {
 	}
 
 	if condTy != nil && !(BoolType{}).Assignable(condTy) {
-		rule.Errorf(str.Pos, "\"if\" condition should be type \"bool\" but got type %q", condTy.String())
+		rule.ErrorfWithCode(str.Pos, "type-mismatch", "\"if\" condition should be type \"bool\" but got type %q", condTy.String())
 	}
 }
 
@@ -667,7 +711,7 @@ func (rule *RuleExpression) checkTemplateEvaluatedType(ts []typedExpr) {
 	for _, t := range ts {
 		switch t.ty.(type) {
 		case *ObjectType, *ArrayType, NullType:
-			rule.Errorf(&t.pos, "object, array, and null values should not be evaluated in template with ${{ }} but evaluating the value of type %s", t.ty)
+			rule.ErrorfWithCode(&t.pos, "object-in-template", "object, array, and null values should not be evaluated in template with ${{ }} but evaluating the value of type %s", t.ty)
 		}
 	}
 }
@@ -713,7 +757,7 @@ func (rule *RuleExpression) checkBool(b *Bool, workflowKey string) {
 	case BoolType, AnyType:
 		// ok
 	default:
-		rule.Errorf(b.Expression.Pos, "type of expression must be bool but found type %s", ty.String())
+		rule.ErrorfWithCode(b.Expression.Pos, "type-mismatch", "type of expression must be bool but found type %s", ty.String())
 	}
 }
 
@@ -769,7 +813,11 @@ func (rule *RuleExpression) checkExprsIn(s string, pos *Pos, quoted, checkUntrus
 
 func (rule *RuleExpression) exprError(err *ExprError, lineBase, colBase int) {
 	pos := convertExprLineColToPos(err.Line, err.Column, lineBase, colBase)
-	rule.Error(pos, err.Message)
+	code := err.Code
+	if code == "" {
+		code = "syntax-error"
+	}
+	rule.ErrorfWithCode(pos, code, "%s", err.Message)
 }
 
 func (rule *RuleExpression) checkSemanticsOfExprNode(expr ExprNode, line, col int, checkUntrusted bool, workflowKey string) (ExprType, bool) {
@@ -778,6 +826,9 @@ func (rule *RuleExpression) checkSemanticsOfExprNode(expr ExprNode, line, col in
 		v = rule.config.ConfigVariables
 	}
 	c := NewExprSemanticsChecker(checkUntrusted, v)
+	if rule.untrustedRoots != nil {
+		c.SetUntrustedInputSearchRoots(rule.untrustedRoots)
+	}
 	if rule.matrixTy != nil {
 		c.UpdateMatrix(rule.matrixTy)
 	}
@@ -16,6 +16,38 @@ func NewRuleGlob() *RuleGlob {
 	}
 }
 
+// ForkJob implements JobLocalPass interface.
+func (rule *RuleGlob) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleGlob) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Glob patterns in filters of Webhook events such as "branches:", "tags:" and
+"paths:" are checked. Invalid syntax and characters which cannot be used in Git
+ref names are reported.`
+	m.BadExample = `on:
+  push:
+    tags: ['v*.*.*[']
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`
+	m.GoodExample = `on:
+  push:
+    tags: ['v*.*.*']
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`
+	return m
+}
+
 // VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
 func (rule *RuleGlob) VisitWorkflowPre(n *Workflow) error {
 	for _, e := range n.On {
@@ -82,6 +114,6 @@ func (rule *RuleGlob) globErrors(errs []InvalidGlobPattern, pos *Pos, quoted boo
 		if err.Column != 0 {
 			p.Col += err.Column - 1
 		}
-		rule.Errorf(&p, "%s. note: filter pattern syntax is explained at https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#filter-pattern-cheat-sheet", err.Message)
+		rule.ErrorfWithCode(&p, "invalid-pattern", "%s. note: filter pattern syntax is explained at https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#filter-pattern-cheat-sheet", err.Message)
 	}
 }
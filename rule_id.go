@@ -23,6 +23,35 @@ func NewRuleID() *RuleID {
 	}
 }
 
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleID) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Job IDs and step IDs are checked. Step IDs must be unique within a job. Note
+that IDs are case insensitive. IDs must start with a letter or "_" and contain
+only alphanumeric characters, "-" or "_".`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+        id: hello
+      - run: echo world
+        id: hello
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+        id: hello
+      - run: echo world
+        id: world
+`
+	return m
+}
+
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RuleID) VisitJobPre(n *Job) error {
 	rule.seen = map[string]*Pos{}
@@ -51,7 +80,14 @@ func (rule *RuleID) VisitStep(n *Step) error {
 
 	id := strings.ToLower(n.ID.Value)
 	if prev, ok := rule.seen[id]; ok {
-		rule.Errorf(n.ID.Pos, "step ID %q duplicates. previously defined at %s. step ID must be unique within a job. note that step ID is case insensitive", n.ID.Value, prev.String())
+		err := rule.ErrorfWithCode(
+			n.ID.Pos,
+			"duplicate-step-id",
+			"step ID %q duplicates. previously defined at %s. step ID must be unique within a job. note that step ID is case insensitive",
+			n.ID.Value,
+			prev.String(),
+		)
+		err.Related = []RelatedLocation{relatedAt(prev, "the same step ID is previously defined here")}
 		return nil
 	}
 	rule.seen[id] = n.ID.Pos
@@ -62,5 +98,5 @@ func (rule *RuleID) validateConvention(id *String, what string) {
 	if id == nil || id.Value == "" || id.ContainsExpression() || jobIDPattern.MatchString(id.Value) {
 		return
 	}
-	rule.Errorf(id.Pos, "invalid %s ID %q. %s ID must start with a letter or _ and contain only alphanumeric characters, -, or _", what, id.Value, what)
+	rule.ErrorfWithCode(id.Pos, "invalid-id", "invalid %s ID %q. %s ID must start with a letter or _ and contain only alphanumeric characters, -, or _", what, id.Value, what)
 }
@@ -0,0 +1,189 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Actions which request an OIDC token. An action is matched when its name in "{owner}/{repo}" or
+// "{owner}/{repo}/{path}" format starts with one of them.
+// https://docs.github.com/en/actions/concepts/security/openid-connect
+var oidcConsumingActions = []string{
+	"actions/attest",
+	"aws-actions/configure-aws-credentials",
+	"azure/login",
+	"codecov/codecov-action",
+	"google-github-actions/auth",
+	"hashicorp/vault-action",
+	"jfrog/setup-jfrog-cli",
+	"octo-sts/action",
+	"pypa/gh-action-pypi-publish",
+	"rubygems/release-gem",
+	"rubygems/configure-rubygems-credentials",
+	"sigstore/",
+	"slsa-framework/",
+}
+
+// Pattern to detect OIDC token requests in "run:" scripts. The runner sets ACTIONS_ID_TOKEN_REQUEST_URL
+// and ACTIONS_ID_TOKEN_REQUEST_TOKEN environment variables to request the token. `npm publish
+// --provenance` and `cosign` also request the token.
+var oidcScriptPattern = regexp.MustCompile(`ACTIONS_ID_TOKEN_REQUEST_(?:URL|TOKEN)|--provenance\b|\bcosign\b|\bgetIDToken\b`)
+
+// Events which can be triggered by untrusted users (including users of forked repositories) while
+// the workflow runs in the context of the base repository. Unlike "pull_request" event, the
+// workflow can obtain "id-token: write" permission on these events.
+// https://securitylab.github.com/research/github-actions-preventing-pwn-requests/
+var forkReachablePrivilegedEvents = map[string]struct{}{
+	"pull_request_target": {},
+	"workflow_run":        {},
+	"issue_comment":       {},
+	"issues":              {},
+	"discussion":          {},
+	"discussion_comment":  {},
+	"fork":                {},
+	"watch":               {},
+}
+
+// RuleIDToken is a rule to check "id-token: write" permission. The permission allows jobs to
+// request OIDC tokens which are exchanged for cloud credentials.
+// https://docs.github.com/en/actions/concepts/security/openid-connect
+type RuleIDToken struct {
+	RuleBase
+	workflowPerms *Permissions
+	event         string
+}
+
+// NewRuleIDToken creates a new RuleIDToken instance.
+func NewRuleIDToken() *RuleIDToken {
+	return &RuleIDToken{
+		RuleBase: RuleBase{
+			name: "id-token",
+			desc: "Checks for unnecessary or dangerous \"id-token: write\" permission",
+		},
+	}
+}
+
+// ForkJob implements JobLocalPass interface. The copy shares the permissions and the event of the
+// workflow.
+func (rule *RuleIDToken) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleIDToken) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `"id-token: write" permission allows jobs to obtain OIDC tokens which can be
+exchanged for cloud credentials. The permission granted to workflows triggered
+by events which untrusted users can trigger is reported. The permission granted
+to jobs which seem not to request OIDC tokens is also reported.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    permissions:
+      id-token: write
+    steps:
+      - run: echo hello
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+    steps:
+      - run: echo hello
+`
+	return m
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleIDToken) VisitWorkflowPre(n *Workflow) error {
+	rule.workflowPerms = n.Permissions
+	rule.event = ""
+	for _, e := range n.On {
+		name := e.EventName()
+		if _, ok := forkReachablePrivilegedEvents[name]; ok {
+			rule.event = name
+			break
+		}
+	}
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleIDToken) VisitJobPre(n *Job) error {
+	// Permissions at job level overwrite permissions at workflow level
+	perms, where := n.Permissions, ""
+	if perms == nil {
+		perms = rule.workflowPerms
+		where = " via workflow-level \"permissions:\""
+	}
+	v, pos := permissionOf(perms, "id-token")
+	if v != "write" {
+		return nil
+	}
+	if where != "" {
+		pos = n.ID.Pos // Report the error at each job
+	}
+
+	if rule.event != "" {
+		rule.ErrorfWithCode(
+			pos,
+			"untrusted-event",
+			"job %q obtains \"id-token: write\" permission%s though the workflow is triggered by %q event which can be triggered by untrusted users. attackers may obtain cloud credentials through OIDC token. avoid granting the permission on the event",
+			n.ID.Value,
+			where,
+			rule.event,
+		)
+	}
+
+	if n.WorkflowCall == nil && !requestsOIDCToken(n.Steps) {
+		rule.ErrorfWithCode(
+			pos,
+			"unused-id-token",
+			"job %q obtains \"id-token: write\" permission%s but no step seems to request OIDC token. remove the permission from the job following the principle of least privilege",
+			n.ID.Value,
+			where,
+		)
+	}
+
+	return nil
+}
+
+// VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
+func (rule *RuleIDToken) VisitWorkflowPost(n *Workflow) error {
+	rule.workflowPerms = nil
+	rule.event = ""
+	return nil
+}
+
+func requestsOIDCToken(steps []*Step) bool {
+	for _, s := range steps {
+		switch e := s.Exec.(type) {
+		case *ExecAction:
+			if e.Uses == nil || e.Uses.ContainsExpression() {
+				return true // Unknown action may request the token
+			}
+			u := strings.ToLower(e.Uses.Value)
+			if strings.HasPrefix(u, "./") || strings.HasPrefix(u, "docker://") {
+				return true // Local actions and Docker actions may request the token. Their implementations are unknown
+			}
+			for _, a := range oidcConsumingActions {
+				if strings.HasPrefix(u, a) {
+					return true
+				}
+			}
+			if strings.HasPrefix(u, "actions/github-script@") {
+				if i, ok := e.Inputs["script"]; ok && i.Value != nil && oidcScriptPattern.MatchString(i.Value.Value) {
+					return true
+				}
+			}
+		case *ExecRun:
+			if e.Run != nil && oidcScriptPattern.MatchString(e.Run.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
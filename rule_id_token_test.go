@@ -0,0 +1,214 @@
+package actionlint
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRuleIDTokenCheckPermission(t *testing.T) {
+	tests := []struct {
+		what string
+		src  string
+		want []string
+	}{
+		{
+			what: "no step requests OIDC token",
+			src: `
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    permissions:
+      id-token: write
+    steps:
+      - run: make test
+`,
+			want: []string{
+				`:7:17: job "test" obtains "id-token: write" permission but no step seems to request OIDC token`,
+			},
+		},
+		{
+			what: "workflow-level permissions",
+			src: `
+on: push
+permissions:
+  id-token: write
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make test
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make build
+`,
+			want: []string{
+				`:6:3: job "test" obtains "id-token: write" permission via workflow-level "permissions:" but no step`,
+				`:10:3: job "build" obtains "id-token: write" permission via workflow-level "permissions:" but no step`,
+			},
+		},
+		{
+			what: "write-all",
+			src: `
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    permissions: write-all
+    steps:
+      - run: make test
+`,
+			want: []string{
+				`:6:18: job "test" obtains "id-token: write" permission but no step`,
+			},
+		},
+		{
+			what: "job-level permissions overwrite workflow-level permissions",
+			src: `
+on: push
+permissions: write-all
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+    steps:
+      - run: make test
+`,
+		},
+		{
+			what: "read-all",
+			src: `
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    permissions: read-all
+    steps:
+      - run: make test
+`,
+		},
+		{
+			what: "steps requesting OIDC token",
+			src: `
+on: push
+permissions:
+  id-token: write
+jobs:
+  aws:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: aws-actions/configure-aws-credentials@v4
+  gcp:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: Google-GitHub-Actions/auth@v2
+  attest:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/attest-build-provenance@v2
+  npm:
+    runs-on: ubuntu-latest
+    steps:
+      - run: npm publish --provenance
+  curl:
+    runs-on: ubuntu-latest
+    steps:
+      - run: curl -H "Authorization bearer $ACTIONS_ID_TOKEN_REQUEST_TOKEN" "$ACTIONS_ID_TOKEN_REQUEST_URL"
+  github-script:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/github-script@v7
+        with:
+          script: const token = await core.getIDToken()
+  local:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ./.github/actions/deploy
+  reusable-workflow:
+    uses: ./.github/workflows/deploy.yaml
+`,
+		},
+		{
+			what: "github-script without requesting token",
+			src: `
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    permissions:
+      id-token: write
+    steps:
+      - uses: actions/github-script@v7
+        with:
+          script: console.log('hello')
+`,
+			want: []string{
+				`:7:17: job "test" obtains "id-token: write" permission but no step`,
+			},
+		},
+		{
+			what: "untrusted event",
+			src: `
+on: [push, issue_comment]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    permissions:
+      id-token: write
+    steps:
+      - uses: aws-actions/configure-aws-credentials@v4
+  call:
+    permissions:
+      id-token: write
+    uses: ./.github/workflows/deploy.yaml
+`,
+			want: []string{
+				`:7:17: job "deploy" obtains "id-token: write" permission though the workflow is triggered by "issue_comment" event`,
+				`:12:17: job "call" obtains "id-token: write" permission though the workflow is triggered by "issue_comment" event`,
+			},
+		},
+		{
+			what: "pull_request event",
+			src: `
+on: pull_request
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    permissions:
+      id-token: write
+    steps:
+      - uses: aws-actions/configure-aws-credentials@v4
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.what, func(t *testing.T) {
+			w, errs := Parse([]byte(tc.src))
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
+			r := NewRuleIDToken()
+			v := NewVisitor()
+			v.AddPass(r)
+			if err := v.Visit(w); err != nil {
+				t.Fatal(err)
+			}
+
+			errs = r.Errs()
+			slices.SortFunc(errs, compareErrors) // Jobs are visited in random order
+			if len(errs) != len(tc.want) {
+				t.Fatalf("wanted %d errors but got %d errors: %v", len(tc.want), len(errs), errs)
+			}
+			for i, err := range errs {
+				if have := err.Error(); !strings.Contains(have, tc.want[i]) {
+					t.Errorf("error %q does not contain %q", have, tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -19,6 +19,36 @@ func NewRuleIfCond() *RuleIfCond {
 	}
 }
 
+// ForkJob implements JobLocalPass interface.
+func (rule *RuleIfCond) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleIfCond) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Conditions at "if:" are checked. A condition mixing ${{ }} and other text is
+always evaluated to true because it is evaluated as a non-empty string. Constant
+conditions are also reported.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    if: ${{ github.event_name == 'push' }} && ${{ github.ref_name == 'main' }}
+    steps:
+      - run: echo hello
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    if: github.event_name == 'push' && github.ref_name == 'main'
+    steps:
+      - run: echo hello
+`
+	return m
+}
+
 // VisitStep is callback when visiting Step node.
 func (rule *RuleIfCond) VisitStep(n *Step) error {
 	rule.checkIfCond(n.If)
@@ -49,8 +79,9 @@ func (rule *RuleIfCond) checkIfCond(n *String) {
 func (rule *RuleIfCond) checkPlaceholder(n *String, start, end int) {
 	// Check number of ${{ }} for conditions like `${{ false }} || ${{ true }}` which are always evaluated to true
 	if start > 0 || end+len("}}") < len(n.Value) || strings.Count(n.Value, "${{") > 1 {
-		rule.Errorf(
+		rule.ErrorfWithCode(
 			n.Pos,
+			"always-true",
 			"if: condition %q is always evaluated to true because extra characters are around ${{ }}",
 			n.Value,
 		)
@@ -64,7 +95,7 @@ func (rule *RuleIfCond) checkExpression(pos *Pos, input string) {
 	l := NewExprLexer(i + "}}")
 	if e, err := NewExprParser().Parse(l); err == nil {
 		if NewExprSemanticsChecker(false, nil).IsConstant(e) {
-			rule.Errorf(pos, "constant expression %q in condition. remove the if: section", i)
+			rule.ErrorfWithCode(pos, "constant-condition", "constant expression %q in condition. remove the if: section", i)
 		}
 	}
 }
@@ -1,6 +1,8 @@
 package actionlint
 
 import (
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -16,11 +18,23 @@ const (
 type jobNode struct {
 	id       string
 	needs    []string
+	needsPos []*Pos
 	resolved []*jobNode
 	status   nodeStatus
 	pos      *Pos
 }
 
+// needPos returns the position of the job ID in "needs:" section of the node. When the ID is not
+// found, the position of the job is returned.
+func (n *jobNode) needPos(id string) *Pos {
+	for i, s := range n.needs {
+		if s == id {
+			return n.needsPos[i]
+		}
+	}
+	return n.pos
+}
+
 type edge struct {
 	from *jobNode
 	to   *jobNode
@@ -44,28 +58,60 @@ func NewRuleJobNeeds() *RuleJobNeeds {
 	}
 }
 
-func contains[T comparable](heystack []T, needle T) bool {
-	for _, s := range heystack {
-		if s == needle {
-			return true
-		}
-	}
-	return false
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleJobNeeds) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Job dependencies at "needs:" are checked. Jobs which do not exist, duplicate
+entries, and cyclic dependencies between jobs are reported. Job IDs are case
+insensitive.`
+	m.BadExample = `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo build
+  test:
+    needs: [bulid]
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`
+	m.GoodExample = `on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo build
+  test:
+    needs: [build]
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`
+	return m
 }
 
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RuleJobNeeds) VisitJobPre(n *Job) error {
 	needs := make([]string, 0, len(n.Needs))
+	needsPos := make([]*Pos, 0, len(n.Needs))
 	for _, j := range n.Needs {
 		id := strings.ToLower(j.Value)
-		if contains(needs, id) {
-			rule.Errorf(j.Pos, "job ID %q duplicates in \"needs\" section. note that job ID is case insensitive", j.Value)
+		if i := slices.Index(needs, id); i >= 0 {
+			err := rule.ErrorfWithCode(
+				j.Pos,
+				"duplicate-needs",
+				"job ID %q duplicates in \"needs\" section. note that job ID is case insensitive",
+				j.Value,
+			)
+			err.Related = []RelatedLocation{relatedAt(needsPos[i], fmt.Sprintf("job ID %q is first specified here", needs[i]))}
 			continue
 		}
 		if id != "" {
 			// Job ID is key of mapping. Key mapping is stored in lowercase since it is case
 			// insensitive. So values in 'needs' array must be compared in lowercase.
 			needs = append(needs, id)
+			needsPos = append(needsPos, j.Pos)
 		}
 	}
 
@@ -74,14 +120,22 @@ func (rule *RuleJobNeeds) VisitJobPre(n *Job) error {
 		return nil
 	}
 	if prev, ok := rule.nodes[id]; ok {
-		rule.Errorf(n.Pos, "job ID %q duplicates. previously defined at %s. note that job ID is case insensitive", n.ID.Value, prev.pos.String())
+		err := rule.ErrorfWithCode(
+			n.Pos,
+			"duplicate-job-id",
+			"job ID %q duplicates. previously defined at %s. note that job ID is case insensitive",
+			n.ID.Value,
+			prev.pos.String(),
+		)
+		err.Related = []RelatedLocation{relatedAt(prev.pos, fmt.Sprintf("job ID %q is previously defined here", prev.id))}
 	}
 
 	rule.nodes[id] = &jobNode{
-		id:     id,
-		needs:  needs,
-		status: nodeStatusNew,
-		pos:    n.ID.Pos,
+		id:       id,
+		needs:    needs,
+		needsPos: needsPos,
+		status:   nodeStatusNew,
+		pos:      n.ID.Pos,
 	}
 
 	return nil
@@ -96,7 +150,7 @@ func (rule *RuleJobNeeds) VisitWorkflowPost(n *Workflow) error {
 		for _, dep := range node.needs {
 			n, ok := rule.nodes[dep]
 			if !ok {
-				rule.Errorf(node.pos, "job %q needs job %q which does not exist in this workflow", id, dep)
+				rule.ErrorfWithCode(node.pos, "undefined-job", "job %q needs job %q which does not exist in this workflow", id, dep)
 				valid = false
 				continue
 			}
@@ -124,18 +178,23 @@ func (rule *RuleJobNeeds) VisitWorkflowPost(n *Workflow) error {
 		var msg strings.Builder
 		msg.WriteString("cyclic dependencies in \"needs\" job configurations are detected. detected cycle is ")
 
+		// Each dependency in the cycle is reported as a related location so that all "needs:"
+		// entries which make the cycle can be found
+		related := []RelatedLocation{}
 		msg.WriteString(strconv.Quote(start.id))
 		from, to := start, edges[start]
 		for {
 			msg.WriteString(" -> ")
 			msg.WriteString(strconv.Quote(to.id))
+			related = append(related, relatedAt(from.needPos(to.id), fmt.Sprintf("job %q needs job %q here", from.id, to.id)))
 			from, to = to, edges[to]
 			if from == start {
 				break
 			}
 		}
 
-		rule.Error(start.pos, msg.String())
+		err := rule.ErrorfWithCode(start.pos, "cyclic-dependency", "%s", msg.String())
+		err.Related = related
 	}
 
 	return nil
@@ -1,6 +1,9 @@
 package actionlint
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // RuleMatrix is a rule checker to check 'matrix' field of job.
 type RuleMatrix struct {
@@ -17,6 +20,41 @@ func NewRuleMatrix() *RuleMatrix {
 	}
 }
 
+// ForkJob implements JobLocalPass interface.
+func (rule *RuleMatrix) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleMatrix) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Values in "matrix:" section are checked. Duplicate values in the same matrix row
+are reported since they run the same job twice. Keys and values in "exclude:"
+which do not match to any combination of the matrix are also reported because
+they are likely typos.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    strategy:
+      matrix:
+        os: [ubuntu-latest, ubuntu-latest]
+    runs-on: ${{ matrix.os }}
+    steps:
+      - run: echo hello
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    strategy:
+      matrix:
+        os: [ubuntu-latest, windows-latest]
+    runs-on: ${{ matrix.os }}
+    steps:
+      - run: echo hello
+`
+	return m
+}
+
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RuleMatrix) VisitJobPre(n *Job) error {
 	if n.Strategy == nil || n.Strategy.Matrix == nil || n.Strategy.Matrix.Expression != nil {
@@ -52,13 +90,15 @@ func (rule *RuleMatrix) checkDuplicateInRow(row *MatrixRow) {
 		ok := true
 		for _, p := range seen {
 			if p.Equals(v) {
-				rule.Errorf(
+				err := rule.ErrorfWithCode(
 					v.Pos(),
+					"duplicate-value",
 					"duplicate value %s is found in matrix %q. the same value is at %s",
 					v.String(),
 					row.Name.Value,
 					p.Pos().String(),
 				)
+				err.Related = []RelatedLocation{relatedAt(p.Pos(), fmt.Sprintf("value %s is first defined here", p.String()))}
 				ok = false
 				break
 			}
@@ -140,7 +180,7 @@ func (rule *RuleMatrix) checkExclude(m *Matrix) {
 	}
 
 	if len(m.Rows) == 0 && (m.Include == nil || len(m.Include.Combinations) == 0) {
-		rule.Error(m.Pos, "\"exclude\" section exists but no matrix variation exists")
+		rule.ErrorfWithCode(m.Pos, "exclude-without-matrix", "\"exclude\" section exists but no matrix variation exists")
 		return
 	}
 
@@ -185,8 +225,9 @@ func (rule *RuleMatrix) checkExclude(m *Matrix) {
 				for k := range rows {
 					ss = append(ss, k)
 				}
-				rule.Errorf(
+				rule.ErrorfWithCode(
 					a.Key.Pos,
+					"undefined-exclude-key",
 					"%q in \"exclude\" section does not exist in matrix. available matrix configurations are %s",
 					k,
 					sortedQuotes(ss),
@@ -204,13 +245,21 @@ func (rule *RuleMatrix) checkExclude(m *Matrix) {
 			for _, v := range row {
 				ss = append(ss, v.String())
 			}
-			rule.Errorf(
+			// The matrix row may be defined only in "include:" section. In the case, there is no
+			// definition to point
+			var related []RelatedLocation
+			if r, ok := m.Rows[k]; ok {
+				related = []RelatedLocation{relatedAt(r.Name.Pos, fmt.Sprintf("matrix %q is defined here", r.Name.Value))}
+			}
+			err := rule.ErrorfWithCode(
 				a.Value.Pos(),
+				"unmatched-exclude-value",
 				"value %s in \"exclude\" does not match in matrix %q combinations. possible values are %s",
 				a.Value.String(),
 				k,
 				strings.Join(ss, ", "), // Note: do not use quotesBuilder
 			)
+			err.Related = related
 		}
 	}
 }
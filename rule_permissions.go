@@ -37,6 +37,38 @@ func NewRulePermissions() *RulePermissions {
 	}
 }
 
+// ForkJob implements JobLocalPass interface.
+func (rule *RulePermissions) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RulePermissions) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Permissions at "permissions:" are checked. Unknown permission scopes and
+invalid values of the scopes are reported. Available values are "read",
+"write" and "none".`
+	m.BadExample = `on: push
+permissions:
+  contents: readable
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`
+	m.GoodExample = `on: push
+permissions:
+  contents: read
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`
+	return m
+}
+
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RulePermissions) VisitJobPre(n *Job) error {
 	rule.checkPermissions(n.Permissions)
@@ -59,7 +91,7 @@ func (rule *RulePermissions) checkPermissions(p *Permissions) {
 		case "write-all", "read-all":
 			// OK
 		default:
-			rule.Errorf(p.All.Pos, "%q is invalid for permission for all the scopes. available values are \"read-all\", \"write-all\" or {}", p.All.Value)
+			rule.ErrorfWithCode(p.All.Pos, "invalid-value", "%q is invalid for permission for all the scopes. available values are \"read-all\", \"write-all\" or {}", p.All.Value)
 		}
 		return
 	}
@@ -72,12 +104,46 @@ func (rule *RulePermissions) checkPermissions(p *Permissions) {
 			for s := range allPermissionScopes {
 				ss = append(ss, s)
 			}
-			rule.Errorf(p.Name.Pos, "unknown permission scope %q. all available permission scopes are %s", n, sortedQuotes(ss))
+			rule.ErrorfWithCode(p.Name.Pos, "unknown-scope", "unknown permission scope %q. all available permission scopes are %s", n, sortedQuotes(ss))
 			continue
 		}
 
 		if !slices.Contains(s, p.Value.Value) {
-			rule.Errorf(p.Value.Pos, "%q is invalid as permission of scope %q. available values are %s", p.Value.Value, n, quotes(s))
+			rule.ErrorfWithCode(p.Value.Pos, "invalid-value", "%q is invalid as permission of scope %q. available values are %s", p.Value.Value, n, quotes(s))
+		}
+	}
+}
+
+// permissionOf returns the permission value ("read", "write", or "none") of the given scope and
+// its position. When all scopes are configured at once with "read-all" or "write-all", the value
+// is derived from it. When the scope is not configured or its value is invalid, this function
+// returns an empty string and nil.
+func permissionOf(p *Permissions, scope string) (string, *Pos) {
+	if p == nil {
+		return "", nil
+	}
+
+	if p.All != nil {
+		switch p.All.Value {
+		case "write-all":
+			return "write", p.All.Pos
+		case "read-all":
+			if slices.Contains(allPermissionScopes[scope], "read") {
+				return "read", p.All.Pos
+			}
+			return "none", p.All.Pos
+		default:
+			return "", nil
 		}
 	}
+
+	// When permissions are configured for each scope, scopes which are not listed have no access
+	s, ok := p.Scopes[scope]
+	if !ok {
+		return "none", p.Pos
+	}
+	if !slices.Contains(allPermissionScopes[scope], s.Value.Value) {
+		return "", nil
+	}
+	return s.Value.Value, s.Value.Pos
 }
@@ -0,0 +1,132 @@
+package actionlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// pluginInput is the JSON value sent to the stdin of a plugin command.
+type pluginInput struct {
+	// Path is the file path of the checked workflow.
+	Path string `json:"path"`
+	// Workflow is the syntax tree of the workflow. Fields are encoded with the same names as the
+	// fields of Workflow struct.
+	Workflow *Workflow `json:"workflow"`
+}
+
+// pluginError is an error reported by a plugin command.
+type pluginError struct {
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	// Code is the sub-code of the error within the plugin. It is optional.
+	Code string `json:"code,omitempty"`
+}
+
+// pluginOutput is the JSON value which a plugin command outputs to its stdout.
+type pluginOutput struct {
+	Errors []pluginError `json:"errors"`
+}
+
+// RulePlugin is a rule to check workflows with an external plugin command. The syntax tree of the
+// workflow is sent to the stdin of the command as JSON like {"path": string, "workflow": object}.
+// The command outputs the found errors to stdout as JSON like {"errors": [{"message": string,
+// "line": number, "column": number, "code": string}]}. The command may exit with non-zero status
+// when it outputs errors. Plugin commands are run concurrently with other external commands such as
+// shellcheck.
+type RulePlugin struct {
+	RuleBase
+	cmd     *CommandRunner
+	command string
+	path    string
+	mu      sync.Mutex
+}
+
+// NewRulePlugin creates a new RulePlugin instance from the plugin configuration. The command is a
+// command line with arguments. A relative file path of the executable is resolved from the root
+// directory of the project in the context. When the command is not found, this function returns an
+// error.
+func NewRulePlugin(cfg *PluginConfig, ctx *RuleContext) (*RulePlugin, error) {
+	argv, err := shellwords.Parse(cfg.Command)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse command %q of plugin %q: %w", cfg.Command, cfg.Name, err)
+	}
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("command of plugin %q is empty", cfg.Name)
+	}
+	exe := argv[0]
+	if ctx.Project != nil && strings.ContainsRune(filepath.ToSlash(exe), '/') && !filepath.IsAbs(exe) {
+		exe = filepath.Join(ctx.Project.RootDir(), exe)
+	}
+	cmd, err := ctx.newCommandRunner(exe, argv[1:], false)
+	if err != nil {
+		return nil, err
+	}
+	return &RulePlugin{
+		RuleBase: RuleBase{
+			name: cfg.Name,
+			desc: fmt.Sprintf("Checks workflows with plugin command %q", cfg.Command),
+		},
+		cmd:     cmd,
+		command: cfg.Command,
+		path:    ctx.Path,
+	}, nil
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RulePlugin) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `This rule is defined by the "plugins" configuration. The workflow is checked by
+running the plugin command.`
+	m.Command = rule.command
+	return m
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RulePlugin) VisitWorkflowPre(n *Workflow) error {
+	b, err := json.Marshal(&pluginInput{rule.path, n})
+	if err != nil {
+		return fmt.Errorf("could not encode workflow %q for plugin %q: %w", rule.path, rule.name, err)
+	}
+
+	rule.Debug("Running plugin command %q for %s", rule.command, rule.path)
+	rule.cmd.Run(nil, string(b), func(stdout []byte, err error) error {
+		if err != nil {
+			rule.Debug("Plugin command %q failed: %v", rule.command, err)
+			return fmt.Errorf("plugin %q did not run successfully while checking %s: %w", rule.name, rule.path, err)
+		}
+		return rule.parseOutput(stdout)
+	})
+	return nil
+}
+
+// VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
+func (rule *RulePlugin) VisitWorkflowPost(n *Workflow) error {
+	return rule.cmd.Wait() // Wait until the plugin process finishes
+}
+
+func (rule *RulePlugin) parseOutput(stdout []byte) error {
+	var out pluginOutput
+	if err := json.Unmarshal(stdout, &out); err != nil {
+		return fmt.Errorf("could not parse output of plugin %q while checking %s: %w. output: %q", rule.name, rule.path, err, stdout)
+	}
+
+	// This method needs to be thread-safe since the callback of CommandRunner.Run is called in a
+	// different goroutine.
+	rule.mu.Lock()
+	defer rule.mu.Unlock()
+	for _, e := range out.Errors {
+		pos := &Pos{Line: max(e.Line, 1), Col: max(e.Column, 1)}
+		if e.Code != "" {
+			rule.ErrorfWithCode(pos, e.Code, "%s", e.Message)
+		} else {
+			rule.Error(pos, e.Message)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package actionlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// testPluginProject creates a project which has a config with the plugin. The plugin writes its
+// stdin to the "input" file and its arguments to the "args" file in the project, and outputs the
+// content of the "output" file.
+func testPluginProject(t *testing.T, name, output string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake plugin script does not work on Windows")
+	}
+	dir := t.TempDir()
+	testWriteFiles(t, dir, map[string]string{
+		".git/HEAD":               "ref: refs/heads/main\n",
+		".github/actionlint.yaml": fmt.Sprintf("plugins:\n  - name: %s\n    command: ./scripts/plugin.sh\n", name),
+		".github/workflows/test.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`,
+		"output": output,
+	})
+	script := filepath.Join(dir, "scripts", "plugin.sh")
+	src := fmt.Sprintf("#!/bin/sh\necho \"$@\" > %q\ncat > %q\ncat %q\nexit 1\n", filepath.Join(dir, "args"), filepath.Join(dir, "input"), filepath.Join(dir, "output"))
+	if err := os.MkdirAll(filepath.Dir(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte(src), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func testLintWithPlugins(t *testing.T, dir string, enable bool) ([]*Error, error) {
+	t.Helper()
+	o := &LinterOptions{
+		WorkingDir:    dir,
+		EnablePlugins: enable,
+		CacheDir:      filepath.Join(dir, "cache"),
+	}
+	l, err := NewLinter(io.Discard, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l.LintRepository(dir)
+}
+
+func TestRulePluginReportErrors(t *testing.T) {
+	out := `{"errors": [
+  {"message": "job must have timeout", "line": 3, "column": 3, "code": "missing-timeout"},
+  {"message": "no position"}
+]}`
+	dir := testPluginProject(t, "org-policy", out)
+
+	errs, err := testLintWithPlugins(t, dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("wanted 2 errors from plugin but got %v", errs)
+	}
+	for i, want := range []struct {
+		msg  string
+		line int
+		col  int
+		code string
+	}{
+		{"no position", 1, 1, ""},
+		{"job must have timeout", 3, 3, "org-policy/missing-timeout"},
+	} {
+		e := errs[i]
+		if e.Message != want.msg || e.Line != want.line || e.Column != want.col || e.Code != want.code || e.Kind != "org-policy" {
+			t.Errorf("unexpected error at %d: %#v", i, e)
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "input"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var in struct {
+		Path     string
+		Workflow struct {
+			Jobs map[string]struct {
+				ID *String
+			}
+		}
+	}
+	if err := json.Unmarshal(b, &in); err != nil {
+		t.Fatalf("plugin input is broken: %v: %s", err, b)
+	}
+	if want := filepath.Join(".github", "workflows", "test.yaml"); in.Path != want {
+		t.Errorf("wanted path %q but got %q", want, in.Path)
+	}
+	if j, ok := in.Workflow.Jobs["test"]; !ok || j.ID.Pos.Line != 3 {
+		t.Errorf("workflow was not sent to plugin: %s", b)
+	}
+
+	if fs, _ := filepath.Glob(filepath.Join(dir, "cache", "*.json")); len(fs) > 0 {
+		t.Errorf("result checked with plugins should not be cached: %v", fs)
+	}
+}
+
+func TestRulePluginDisabledByDefault(t *testing.T) {
+	dir := testPluginProject(t, "org-policy", `{"errors": [{"message": "error", "line": 1, "column": 1}]}`)
+	errs, err := testLintWithPlugins(t, dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 0 {
+		t.Fatalf("plugin should not run without enabling plugins: %v", errs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "input")); err == nil {
+		t.Fatal("plugin command was run")
+	}
+}
+
+func TestRulePluginNameConflict(t *testing.T) {
+	dir := testPluginProject(t, "expression", `{"errors": [{"message": "error", "line": 1, "column": 1}]}`)
+	_, err := testLintWithPlugins(t, dir, true)
+	if err == nil {
+		t.Fatal("plugin whose name conflicts with built-in rule should cause an error")
+	}
+	if msg := err.Error(); !strings.Contains(msg, `plugin "expression" could not be enabled since a rule with the same name already exists`) {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+}
+
+func TestRulePluginCommandNotFound(t *testing.T) {
+	dir := testPluginProject(t, "org-policy", `{"errors": []}`)
+	testWriteFiles(t, dir, map[string]string{
+		".github/actionlint.yaml": "plugins:\n  - name: org-policy\n    command: ./scripts/not-found.sh\n",
+	})
+	_, err := testLintWithPlugins(t, dir, true)
+	if err == nil {
+		t.Fatal("plugin whose command is not found should cause an error")
+	}
+	if msg := err.Error(); !strings.Contains(msg, `plugin "org-policy" could not be enabled`) {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+}
+
+func TestRulePluginCommandWithArgs(t *testing.T) {
+	dir := testPluginProject(t, "org-policy", `{"errors": [{"message": "error", "line": 1, "column": 1}]}`)
+	testWriteFiles(t, dir, map[string]string{
+		".github/actionlint.yaml": "plugins:\n  - name: org-policy\n    command: ./scripts/plugin.sh --strict 'foo bar'\n",
+	})
+	errs, err := testLintWithPlugins(t, dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("wanted 1 error from plugin but got %v", errs)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "args"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := string(b), "--strict foo bar\n"; have != want {
+		t.Fatalf("wanted arguments %q but got %q", want, have)
+	}
+}
+
+func TestRulePluginBrokenOutput(t *testing.T) {
+	dir := testPluginProject(t, "org-policy", "this is not JSON")
+	_, err := testLintWithPlugins(t, dir, true)
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	if msg := err.Error(); !strings.Contains(msg, `could not parse output of plugin "org-policy"`) {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+}
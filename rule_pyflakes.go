@@ -3,6 +3,7 @@ package actionlint
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync"
 )
@@ -25,6 +26,64 @@ func getShellIsPythonKind(shell *String) shellIsPythonKind {
 	return shellIsPythonKindNotPython
 }
 
+// pyflakesMessageClasses is the list of the message classes of pyflakes with the patterns of their
+// messages. The class names are used as the error codes like "pyflakes/UndefinedName". Messages
+// which do not match any pattern such as syntax errors have no code other than the rule name.
+// https://github.com/PyCQA/pyflakes/blob/main/pyflakes/messages.py
+var pyflakesMessageClasses = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"UnusedImport", regexp.MustCompile(`^.+ imported but unused$`)},
+	{"RedefinedWhileUnused", regexp.MustCompile(`^redefinition of unused .+ from line \d+$`)},
+	{"ImportShadowedByLoopVar", regexp.MustCompile(`^import .+ from line \d+ shadowed by loop variable$`)},
+	{"ImportStarNotPermitted", regexp.MustCompile(`^'from .+ import \*' only allowed at module level$`)},
+	{"ImportStarUsed", regexp.MustCompile(`^'from .+ import \*' used; unable to detect undefined names$`)},
+	{"ImportStarUsage", regexp.MustCompile(`^.+ may be undefined, or defined from star imports: `)},
+	{"UndefinedExport", regexp.MustCompile(`^undefined name .+ in __all__$`)},
+	{"UndefinedName", regexp.MustCompile(`^undefined name `)},
+	{"UndefinedLocal", regexp.MustCompile(`^local variable .+ referenced before assignment$`)},
+	{"DuplicateArgument", regexp.MustCompile(`^duplicate argument .+ in function definition$`)},
+	{"MultiValueRepeatedKeyLiteral", regexp.MustCompile(`^dictionary key .+ repeated with different values$`)},
+	{"MultiValueRepeatedKeyVariable", regexp.MustCompile(`^dictionary key variable .+ repeated with different values$`)},
+	{"LateFutureImport", regexp.MustCompile(`^from __future__ imports must occur at the beginning of the file$`)},
+	{"FutureFeatureNotDefined", regexp.MustCompile(`^future feature .+ is not defined$`)},
+	{"UnusedVariable", regexp.MustCompile(`^local variable .+ is assigned to but never used$`)},
+	{"UnusedAnnotation", regexp.MustCompile(`^local variable .+ is annotated but never used$`)},
+	{"ReturnOutsideFunction", regexp.MustCompile(`^'return' outside function$`)},
+	{"YieldOutsideFunction", regexp.MustCompile(`^'(?:yield|yield from|await)' outside function$`)},
+	{"ContinueOutsideLoop", regexp.MustCompile(`^'continue' not properly in loop$`)},
+	{"BreakOutsideLoop", regexp.MustCompile(`^'break' outside loop$`)},
+	{"DefaultExceptNotLast", regexp.MustCompile(`^default 'except:' must be last$`)},
+	{"TwoStarredExpressions", regexp.MustCompile(`^two starred expressions in assignment$`)},
+	{"TooManyExpressionsInStarredAssignment", regexp.MustCompile(`^too many expressions in star-unpacking assignment$`)},
+	{"IfTuple", regexp.MustCompile(`^'if tuple literal' is always true`)},
+	{"AssertTuple", regexp.MustCompile(`^assertion is always true`)},
+	{"ForwardAnnotationSyntaxError", regexp.MustCompile(`^syntax error in forward annotation `)},
+	{"RaiseNotImplemented", regexp.MustCompile(`^'raise NotImplemented' should be 'raise NotImplementedError'$`)},
+	{"InvalidPrintSyntax", regexp.MustCompile(`^use of >> is invalid with print function$`)},
+	{"IsLiteral", regexp.MustCompile(`^use ==/!= to compare constant literals`)},
+	{"FStringMissingPlaceholders", regexp.MustCompile(`^f-string is missing placeholders$`)},
+	{"TStringMissingPlaceholders", regexp.MustCompile(`^t-string is missing placeholders$`)},
+}
+
+// pyflakesPositionPattern matches the position like "1:7: " at the start of pyflakes messages.
+var pyflakesPositionPattern = regexp.MustCompile(`^\d+:(?:\d+:)? `)
+
+// pyflakesErrorCode returns the name of the message class of the pyflakes message. An empty string
+// is returned when the class is unknown.
+func pyflakesErrorCode(msg string) string {
+	if loc := pyflakesPositionPattern.FindStringIndex(msg); loc != nil {
+		msg = msg[loc[1]:]
+	}
+	for _, c := range pyflakesMessageClasses {
+		if c.re.MatchString(msg) {
+			return c.name
+		}
+	}
+	return ""
+}
+
 // RulePyflakes is a rule to check Python scripts at 'run:' using pyflakes.
 // https://github.com/PyCQA/pyflakes
 type RulePyflakes struct {
@@ -59,6 +118,31 @@ func NewRulePyflakes(executable string, proc *concurrentProcess) (*RulePyflakes,
 	return newRulePyflakes(cmd), nil
 }
 
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RulePyflakes) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Python scripts at "run:" with "shell: python" are checked with pyflakes. This
+rule is enabled only when pyflakes command is found.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: print(msg)
+        shell: python
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: print('hello')
+        shell: python
+`
+	m.Command = "pyflakes"
+	return m
+}
+
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RulePyflakes) VisitJobPre(n *Job) error {
 	if n.Defaults != nil && n.Defaults.Run != nil {
@@ -165,7 +249,11 @@ func (rule *RulePyflakes) parseNextError(stdout []byte, pos *Pos) ([]byte, error
 
 	// This method needs to be thread-safe since concurrentProcess.run calls its callback in a different goroutine.
 	rule.mu.Lock()
-	rule.Errorf(pos, "pyflakes reported issue in this script: %s", msg)
+	if c := pyflakesErrorCode(string(msg)); c != "" {
+		rule.ErrorfWithCode(pos, c, "pyflakes reported issue in this script: %s", msg)
+	} else {
+		rule.Errorf(pos, "pyflakes reported issue in this script: %s", msg)
+	}
 	rule.mu.Unlock()
 
 	return b, nil
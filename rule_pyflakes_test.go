@@ -195,3 +195,46 @@ func TestRulePyflakesParsePyflakesOutputError(t *testing.T) {
 		t.Fatalf("Error %q does not contain expected message %q", have, want)
 	}
 }
+
+func TestRulePyflakesErrorCode(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"1:7: undefined name 'foo'", "UndefinedName"},
+		{"1: undefined name 'foo'", "UndefinedName"},
+		{"1:1: undefined name 'foo' in __all__", "UndefinedExport"},
+		{"1:1: 'os' imported but unused", "UnusedImport"},
+		{"2:5: local variable 'x' is assigned to but never used", "UnusedVariable"},
+		{"3:1: redefinition of unused 'f' from line 1", "RedefinedWhileUnused"},
+		{"1:7: f-string is missing placeholders", "FStringMissingPlaceholders"},
+		{"1:1: 'from os import *' used; unable to detect undefined names", "ImportStarUsed"},
+		{"1:7: unexpected EOF while parsing", ""},
+	}
+
+	for _, tc := range tests {
+		if have := pyflakesErrorCode(tc.msg); have != tc.want {
+			t.Errorf("wanted code %q but got %q for message %q", tc.want, have, tc.msg)
+		}
+	}
+
+	r := newRulePyflakes(&externalCommand{})
+	stdout := []byte("<stdin>:1:7: undefined name 'foo'\n<stdin>:1:7: unexpected EOF while parsing\n")
+	for len(stdout) > 0 {
+		o, err := r.parseNextError(stdout, &Pos{Line: 1, Col: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stdout = o
+	}
+	errs := r.Errs()
+	if len(errs) != 2 {
+		t.Fatalf("wanted 2 errors but got %v", errs)
+	}
+	if c := errs[0].Code; c != "pyflakes/UndefinedName" {
+		t.Errorf("wanted code of message class but got %q", c)
+	}
+	if c := errs[1].Code; c != "" {
+		t.Errorf("wanted no code for syntax error but got %q", c)
+	}
+}
@@ -0,0 +1,208 @@
+package actionlint
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RuleContext is the inputs to create rule instances for checking one workflow file. It is passed
+// to the factory functions registered in RuleRegistry so that rules defined outside this package
+// can get the same inputs as built-in rules.
+type RuleContext struct {
+	// Path is the file path of the workflow file to be checked.
+	Path string
+	// Project is the project which the workflow file belongs to. It is nil when the file is not in
+	// any project.
+	Project *Project
+	// Config is the configuration for the workflow file. It is nil when no config file was found.
+	// Note that the configuration is also passed to the rules via their SetConfig method.
+	Config *Config
+	// LocalActions is the cache of local actions in the project. It is shared by all rules and
+	// all workflow files in the project.
+	LocalActions *LocalActionsCache
+	// LocalReusableWorkflows is the cache of local reusable workflows in the project. It is shared
+	// by all rules and all workflow files in the project.
+	LocalReusableWorkflows *LocalReusableWorkflowCache
+	// LocalWorkflows is the cache of all workflows in the project. It is shared by all rules and all
+	// workflow files in the project.
+	LocalWorkflows *LocalWorkflowsCache
+
+	proc            *concurrentProcess
+	shellcheck      string
+	pyflakes        string
+	shellcheckCache *shellcheckCache
+}
+
+// NewCommandRunner creates a runner of the external command for a rule. The executable can be a
+// command name or a file path. When the executable is not found, this method returns an error.
+// When combineOutput is true, stdout and stderr of the process are combined.
+func (ctx *RuleContext) NewCommandRunner(executable string, combineOutput bool) (*CommandRunner, error) {
+	return ctx.newCommandRunner(executable, nil, combineOutput)
+}
+
+// newCommandRunner is the same as NewCommandRunner but the arguments are always passed to the
+// executable before the arguments given to CommandRunner.Run.
+func (ctx *RuleContext) newCommandRunner(executable string, args []string, combineOutput bool) (*CommandRunner, error) {
+	if ctx.proc == nil {
+		return nil, fmt.Errorf("cannot run %q since no process runner is available", executable)
+	}
+	cmd, err := ctx.proc.newCommandRunner(executable, combineOutput)
+	if err != nil {
+		return nil, err
+	}
+	cmd.args = append(cmd.args, args...)
+	return &CommandRunner{cmd}, nil
+}
+
+// RuleFactory creates a rule instance to check one workflow file. When the rule should not be
+// applied, the factory returns nil or an error. The error is not fatal. It is reported to the log
+// as the reason why the rule was disabled.
+type RuleFactory func(ctx *RuleContext) (Rule, error)
+
+type ruleEntry struct {
+	name    string
+	factory RuleFactory
+}
+
+// RuleRegistry is a set of rules to check workflow files. Each rule is registered with its name and
+// a factory function. Linter creates the rule instances with the factories for each workflow file
+// in the order of registration. Registering or unregistering rules while linting is not allowed.
+type RuleRegistry struct {
+	entries []ruleEntry
+}
+
+// NewRuleRegistry creates a new empty RuleRegistry instance. Use BuiltinRuleRegistry to create a
+// registry which contains all built-in rules.
+func NewRuleRegistry() *RuleRegistry {
+	return &RuleRegistry{}
+}
+
+// builtinRuleEntries is the built-in rules which do not depend on external commands in the order of
+// applying them.
+var builtinRuleEntries = []ruleEntry{
+	{"matrix", func(*RuleContext) (Rule, error) { return NewRuleMatrix(), nil }},
+	{"credentials", func(*RuleContext) (Rule, error) { return NewRuleCredentials(), nil }},
+	{"shell-name", func(*RuleContext) (Rule, error) { return NewRuleShellName(), nil }},
+	{"runner-label", func(*RuleContext) (Rule, error) { return NewRuleRunnerLabel(), nil }},
+	{"events", func(*RuleContext) (Rule, error) { return NewRuleEvents(), nil }},
+	{"job-needs", func(*RuleContext) (Rule, error) { return NewRuleJobNeeds(), nil }},
+	{"action", func(ctx *RuleContext) (Rule, error) { return NewRuleAction(ctx.LocalActions), nil }},
+	{"env-var", func(*RuleContext) (Rule, error) { return NewRuleEnvVar(), nil }},
+	{"id", func(*RuleContext) (Rule, error) { return NewRuleID(), nil }},
+	{"glob", func(*RuleContext) (Rule, error) { return NewRuleGlob(), nil }},
+	{"permissions", func(*RuleContext) (Rule, error) { return NewRulePermissions(), nil }},
+	{"id-token", func(*RuleContext) (Rule, error) { return NewRuleIDToken(), nil }},
+	{"workflow-call", func(ctx *RuleContext) (Rule, error) {
+		r := NewRuleWorkflowCall(ctx.Path, ctx.LocalReusableWorkflows)
+		if ctx.Project != nil {
+			r.repoOwner = ctx.Project.owner
+		}
+		return r, nil
+	}},
+	{"expression", func(ctx *RuleContext) (Rule, error) {
+		return NewRuleExpression(ctx.LocalActions, ctx.LocalReusableWorkflows), nil
+	}},
+	{"deprecated-commands", func(*RuleContext) (Rule, error) { return NewRuleDeprecatedCommands(), nil }},
+	{"env-file", func(*RuleContext) (Rule, error) { return NewRuleEnvFile(), nil }},
+	{"if-cond", func(*RuleContext) (Rule, error) { return NewRuleIfCond(), nil }},
+	{"workflow-run", func(ctx *RuleContext) (Rule, error) { return NewRuleWorkflowRun(ctx.LocalWorkflows), nil }},
+}
+
+func newShellcheckRuleFromContext(ctx *RuleContext) (Rule, error) {
+	if ctx.shellcheck == "" {
+		return nil, errors.New("shellcheck command name was empty")
+	}
+	r, err := NewRuleShellcheck(ctx.shellcheck, ctx.proc)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.shellcheckCache != nil {
+		r.cache = ctx.shellcheckCache
+	}
+	return r, nil
+}
+
+func newPyflakesRuleFromContext(ctx *RuleContext) (Rule, error) {
+	if ctx.pyflakes == "" {
+		return nil, errors.New("pyflakes command name was empty")
+	}
+	return NewRulePyflakes(ctx.pyflakes, ctx.proc)
+}
+
+// BuiltinRuleRegistry creates a new RuleRegistry instance which contains all built-in rules. The
+// "shellcheck" and "pyflakes" rules are disabled when the commands are not configured with
+// LinterOptions or not found. Register your own rules to the returned registry to apply them with
+// the built-in rules.
+func BuiltinRuleRegistry() *RuleRegistry {
+	es := make([]ruleEntry, 0, len(builtinRuleEntries)+2)
+	es = append(es, builtinRuleEntries...)
+	es = append(es, ruleEntry{"shellcheck", newShellcheckRuleFromContext}, ruleEntry{"pyflakes", newPyflakesRuleFromContext})
+	return &RuleRegistry{es}
+}
+
+// Register registers the rule factory with the name. The name should be the same as the name of
+// the rule created by the factory. It returns an error when a rule with the same name was already
+// registered.
+func (reg *RuleRegistry) Register(name string, factory RuleFactory) error {
+	if name == "" {
+		return errors.New("name of rule must not be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("factory of rule %q must not be nil", name)
+	}
+	if reg.Has(name) {
+		return fmt.Errorf("rule %q is already registered", name)
+	}
+	reg.entries = append(reg.entries, ruleEntry{name, factory})
+	return nil
+}
+
+// Unregister removes the rule with the name from the registry. It returns false when the rule was
+// not registered.
+func (reg *RuleRegistry) Unregister(name string) bool {
+	for i, e := range reg.entries {
+		if e.name == name {
+			reg.entries = append(reg.entries[:i:i], reg.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Has returns whether a rule with the name is registered.
+func (reg *RuleRegistry) Has(name string) bool {
+	for _, e := range reg.entries {
+		if e.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the registered rules in the order of registration.
+func (reg *RuleRegistry) Names() []string {
+	ns := make([]string, 0, len(reg.entries))
+	for _, e := range reg.entries {
+		ns = append(ns, e.name)
+	}
+	return ns
+}
+
+// Create creates the rule instances with the registered factories in the order of registration.
+// The second return value is the errors from the factories of the disabled rules. The errors are
+// wrapped with the rule names.
+func (reg *RuleRegistry) Create(ctx *RuleContext) ([]Rule, []error) {
+	rules := make([]Rule, 0, len(reg.entries))
+	var errs []error
+	for _, e := range reg.entries {
+		r, err := e.factory(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %q was disabled: %w", e.name, err))
+			continue
+		}
+		if r != nil {
+			rules = append(rules, r)
+		}
+	}
+	return rules, errs
+}
@@ -0,0 +1,171 @@
+package actionlint
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinRuleRegistryNames(t *testing.T) {
+	want := []string{}
+	for _, m := range BuiltinRuleMetadata() {
+		want = append(want, m.Name)
+	}
+	if have := BuiltinRuleRegistry().Names(); !slices.Equal(have, want) {
+		t.Fatalf("wanted %v but got %v", want, have)
+	}
+}
+
+func TestRuleRegistryRegisterAndUnregister(t *testing.T) {
+	f := func(*RuleContext) (Rule, error) { return nil, nil }
+	reg := NewRuleRegistry()
+	for _, n := range []string{"foo", "bar", "piyo"} {
+		if err := reg.Register(n, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		factory RuleFactory
+		want    string
+	}{
+		{"foo", f, `rule "foo" is already registered`},
+		{"", f, "name of rule must not be empty"},
+		{"nil", nil, `factory of rule "nil" must not be nil`},
+	} {
+		err := reg.Register(tc.name, tc.factory)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("wanted error %q for rule %q but got %v", tc.want, tc.name, err)
+		}
+	}
+
+	if !reg.Unregister("bar") {
+		t.Error("registered rule was not unregistered")
+	}
+	if reg.Unregister("bar") {
+		t.Error("unregistered rule should not be found")
+	}
+	if reg.Has("bar") || !reg.Has("piyo") {
+		t.Errorf("unexpected rules after unregistering: %v", reg.Names())
+	}
+	if have, want := reg.Names(), []string{"foo", "piyo"}; !slices.Equal(have, want) {
+		t.Fatalf("wanted %v but got %v", want, have)
+	}
+}
+
+func TestRuleRegistryCreateDisabledRules(t *testing.T) {
+	reg := NewRuleRegistry()
+	reg.Register("foo", func(*RuleContext) (Rule, error) { return nil, fmt.Errorf("foo is not available") })
+	reg.Register("bar", func(*RuleContext) (Rule, error) { return nil, nil })
+	reg.Register("piyo", func(*RuleContext) (Rule, error) { return NewRuleMatrix(), nil })
+
+	rules, errs := reg.Create(&RuleContext{})
+	if len(rules) != 1 || rules[0].Name() != "matrix" {
+		t.Errorf("unexpected rules: %v", rules)
+	}
+	if len(errs) != 1 || errs[0].Error() != `rule "foo" was disabled: foo is not available` {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestLinterRuleRegistry(t *testing.T) {
+	dir := t.TempDir()
+	testWriteFiles(t, dir, map[string]string{
+		".git/HEAD":               "ref: refs/heads/main\n",
+		".github/actionlint.yaml": "config-variables: [FOO]\n",
+		".github/workflows/test.yaml": `on: push
+jobs:
+  test:
+    runs-on: unknown-label
+    steps:
+      - run: echo
+      - run: echo
+`,
+	})
+
+	var ctx *RuleContext
+	reg := BuiltinRuleRegistry()
+	reg.Unregister("runner-label")
+	err := reg.Register("this-is-test", func(c *RuleContext) (Rule, error) {
+		ctx = c
+		return &customRuleForTest{RuleBase: NewRuleBase("this-is-test", "")}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewLinter(io.Discard, &LinterOptions{Rules: reg, CacheDir: filepath.Join(dir, "cache"), WorkingDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.cache != nil {
+		t.Error("result cache should be disabled when rule registry is set")
+	}
+
+	path := filepath.Join(dir, ".github", "workflows", "test.yaml")
+	errs, err := l.LintFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Kind != "this-is-test" {
+		t.Fatalf("only the error from the registered rule was expected but got %v", errs)
+	}
+
+	if ctx == nil {
+		t.Fatal("factory was not called")
+	}
+	if want := filepath.Join(".github", "workflows", "test.yaml"); ctx.Path != want {
+		t.Errorf("wanted path %q but got %q", want, ctx.Path)
+	}
+	if ctx.Project == nil || ctx.Project.RootDir() != dir {
+		t.Errorf("project at %q was not passed: %v", dir, ctx.Project)
+	}
+	if ctx.Config == nil || !slices.Equal(ctx.Config.ConfigVariables, []string{"FOO"}) {
+		t.Errorf("config was not passed: %v", ctx.Config)
+	}
+	if ctx.LocalActions == nil || ctx.LocalReusableWorkflows == nil || ctx.LocalWorkflows == nil {
+		t.Errorf("caches were not passed: %#v", ctx)
+	}
+}
+
+func TestRuleContextNewCommandRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake command script does not work on Windows")
+	}
+	exe := filepath.Join(t.TempDir(), "fake")
+	if err := os.WriteFile(exe, []byte("#!/bin/sh\necho \"$1\"\ncat\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	proc := newConcurrentProcess(1)
+	ctx := &RuleContext{proc: proc}
+	r, err := ctx.NewCommandRunner(exe, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out string
+	r.Run([]string{"hello"}, "world", func(stdout []byte, err error) error {
+		out = string(stdout)
+		return err
+	})
+	if err := r.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	proc.wait()
+	if out != "hello\nworld" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	if _, err := ctx.NewCommandRunner(filepath.Join(t.TempDir(), "missing"), false); err == nil {
+		t.Error("missing command should cause an error")
+	}
+	if _, err := (&RuleContext{}).NewCommandRunner(exe, false); err == nil {
+		t.Error("no process runner should cause an error")
+	}
+}
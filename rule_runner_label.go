@@ -135,6 +135,36 @@ func NewRuleRunnerLabel() *RuleRunnerLabel {
 	}
 }
 
+// ForkJob implements JobLocalPass interface. The compats map is not shared since it is created in
+// VisitJobPre.
+func (rule *RuleRunnerLabel) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleRunnerLabel) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Runner labels at "runs-on:" are checked. Unknown labels are reported because
+the job waits for a runner forever. Labels for self-hosted runners must be
+listed in "self-hosted-runner" section of actionlint.yaml. Conflicting labels
+such as two different OSes are also reported.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latst
+    steps:
+      - run: echo hello
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`
+	return m
+}
+
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RuleRunnerLabel) VisitJobPre(n *Job) error {
 	if n.RunsOn == nil {
@@ -209,7 +239,7 @@ func (rule *RuleRunnerLabel) verifyRunnerLabel(label *String) runnerOSCompat {
 	for _, k := range known {
 		m, err := path.Match(k, l)
 		if err != nil {
-			rule.Errorf(label.Pos, "label pattern %q is an invalid glob. kindly check list of labels in actionlint.yaml config file: %v", k, err)
+			rule.ErrorfWithCode(label.Pos, "invalid-pattern", "label pattern %q is an invalid glob. kindly check list of labels in actionlint.yaml config file: %v", k, err)
 			return compatInvalid
 		}
 		if m {
@@ -217,8 +247,9 @@ func (rule *RuleRunnerLabel) verifyRunnerLabel(label *String) runnerOSCompat {
 		}
 	}
 
-	rule.Errorf(
+	rule.ErrorfWithCode(
 		label.Pos,
+		"unknown-label",
 		"label %q is unknown. available labels are %s. if it is a custom label for self-hosted runner, set list of labels in actionlint.yaml config file",
 		label.Value,
 		quotesAll(
@@ -268,7 +299,7 @@ func (rule *RuleRunnerLabel) tryToGetLabelsInMatrix(label *String, m *Matrix) []
 		if row, ok := m.Rows[prop]; ok {
 			for _, v := range row.Values {
 				if s, ok := v.(*RawYAMLString); ok && !ContainsExpression(s.Value) {
-					labels = append(labels, &String{s.Value, false, s.Pos()})
+					labels = append(labels, &String{s.Value, false, false, s.Pos(), nil})
 				}
 			}
 		}
@@ -279,7 +310,7 @@ func (rule *RuleRunnerLabel) tryToGetLabelsInMatrix(label *String, m *Matrix) []
 			if combi.Assigns != nil {
 				if assign, ok := combi.Assigns[prop]; ok {
 					if s, ok := assign.Value.(*RawYAMLString); ok && !ContainsExpression(s.Value) {
-						labels = append(labels, &String{s.Value, false, s.Pos()})
+						labels = append(labels, &String{s.Value, false, false, s.Pos(), nil})
 					}
 				}
 			}
@@ -292,7 +323,7 @@ func (rule *RuleRunnerLabel) tryToGetLabelsInMatrix(label *String, m *Matrix) []
 func (rule *RuleRunnerLabel) checkConflict(comp runnerOSCompat, label *String) bool {
 	for c, l := range rule.compats {
 		if c&comp == 0 {
-			rule.Errorf(label.Pos, "label %q conflicts with label %q defined at %s. note: to run your job on each workers, use matrix", label.Value, l.Value, l.Pos)
+			rule.ErrorfWithCode(label.Pos, "conflicting-labels", "label %q conflicts with label %q defined at %s. note: to run your job on each workers, use matrix", label.Value, l.Value, l.Pos)
 			return false
 		}
 	}
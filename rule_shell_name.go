@@ -30,6 +30,36 @@ func NewRuleShellName() *RuleShellName {
 	}
 }
 
+// ForkJob implements JobLocalPass interface.
+func (rule *RuleShellName) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleShellName) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Shell names at "shell:" are checked. Unknown shell names and shells which are
+not available on the runner OS (e.g. "powershell" on Linux runners is fine but
+"cmd" is not) are reported.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+        shell: dash
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+        shell: bash
+`
+	return m
+}
+
 // VisitStep is callback when visiting Step node.
 func (rule *RuleShellName) VisitStep(n *Step) error {
 	if run, ok := n.Exec.(*ExecRun); ok {
@@ -105,8 +135,9 @@ func (rule *RuleShellName) checkShellName(node *String) {
 		}
 	}
 
-	rule.Errorf(
+	rule.ErrorfWithCode(
 		node.Pos,
+		"invalid-shell",
 		"shell name %q is invalid%s. available names are %s",
 		node.Value,
 		onPlatform,
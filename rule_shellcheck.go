@@ -3,11 +3,15 @@ package actionlint
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 )
 
 type shellcheckError struct {
+	File    string `json:"file,omitempty"`
 	Line    int    `json:"line"`
 	Column  int    `json:"column"`
 	Level   string `json:"level"`
@@ -15,6 +19,20 @@ type shellcheckError struct {
 	Message string `json:"message"`
 }
 
+// shellcheckBatchSize is the maximum number of scripts passed to one shellcheck process. Scripts are
+// split into multiple processes to run them in parallel and to avoid too long command lines.
+const shellcheckBatchSize = 32
+
+// shellcheckScript is a script at "run:" waiting for being checked by shellcheck.
+type shellcheckScript struct {
+	key    string
+	shell  string
+	args   []string
+	script string
+	pos    *Pos
+	result *shellcheckResult
+}
+
 // RuleShellcheck is a rule to check shell scripts at 'run:' using shellcheck.
 // https://github.com/koalaman/shellcheck
 type RuleShellcheck struct {
@@ -24,6 +42,8 @@ type RuleShellcheck struct {
 	jobShell      string
 	runnerShell   string
 	mu            sync.Mutex
+	cache         *shellcheckCache
+	pending       []*shellcheckScript
 }
 
 func newRuleShellcheck(cmd *externalCommand) *RuleShellcheck {
@@ -36,6 +56,7 @@ func newRuleShellcheck(cmd *externalCommand) *RuleShellcheck {
 		workflowShell: "",
 		jobShell:      "",
 		runnerShell:   "",
+		cache:         newShellcheckCache("", nil),
 	}
 }
 
@@ -50,6 +71,30 @@ func NewRuleShellcheck(executable string, proc *concurrentProcess) (*RuleShellch
 	return newRuleShellcheck(cmd), nil
 }
 
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleShellcheck) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Scripts at "run:" are checked with shellcheck. ${{ }} placeholders are replaced
+with dummy values before running shellcheck. This rule is enabled only when
+shellcheck command is found.`
+	m.BadExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo $HOME
+`
+	m.GoodExample = `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "$HOME"
+`
+	m.Command = "shellcheck"
+	return m
+}
+
 // VisitStep is callback when visiting Step node.
 func (rule *RuleShellcheck) VisitStep(n *Step) error {
 	run, ok := n.Exec.(*ExecRun)
@@ -100,6 +145,7 @@ func (rule *RuleShellcheck) VisitWorkflowPre(n *Workflow) error {
 // VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
 func (rule *RuleShellcheck) VisitWorkflowPost(n *Workflow) error {
 	rule.workflowShell = ""
+	rule.flush()
 	return rule.cmd.wait() // Wait until all processes running for this rule
 }
 
@@ -173,7 +219,6 @@ func (rule *RuleShellcheck) runShellcheck(src, shell string, pos *Pos) {
 	}
 
 	src = sanitizeExpressionsInScript(src)
-	rule.Debug("%s: Run shellcheck for %s script:\n%s", pos, sh, src)
 
 	// Reasons to exclude the rules:
 	//
@@ -190,8 +235,7 @@ func (rule *RuleShellcheck) runShellcheck(src, shell string, pos *Pos) {
 	//           this can happen. For example, `if [ -z ${{ env.FOO }} ]` -> `if [ -z ______________ ]` (#113).
 	// - SC2043: Loop can be detected as only running once when the target of iteration is a placeholder. (#355)
 	//           e.g. `for foo in ${{ inputs.foo }}; do`
-	args := []string{"--norc", "-f", "json", "-x", "--shell", sh, "-e", "SC1091,SC2194,SC2050,SC2153,SC2154,SC2157,SC2043", "-"}
-	rule.Debug("%s: Running %s command with %s", pos, rule.cmd.exe, args)
+	args := []string{"--norc", "-f", "json", "-x", "--shell", sh, "-e", "SC1091,SC2194,SC2050,SC2153,SC2154,SC2157,SC2043"}
 
 	// Use same options to run shell process described at document
 	// https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#using-a-specific-shell
@@ -201,36 +245,147 @@ func (rule *RuleShellcheck) runShellcheck(src, shell string, pos *Pos) {
 	}
 	script := fmt.Sprintf("%s\n%s\n", setup, src)
 
-	rule.cmd.run(args, script, func(stdout []byte, err error) error {
+	// Scripts are checked at once when visiting the workflow finishes
+	rule.pending = append(rule.pending, &shellcheckScript{
+		key:    rule.cache.key(rule.cmd.exe, slices.Concat(rule.cmd.args, args), script),
+		shell:  sh,
+		args:   args,
+		script: script,
+		pos:    pos,
+	})
+}
+
+// flush runs shellcheck for all pending scripts. Scripts are batched by their shells and passed to
+// one shellcheck process as separate files. Scripts which are already checked or being checked by
+// other rules are not passed to shellcheck. Their results are reported when they are available.
+func (rule *RuleShellcheck) flush() {
+	batches := map[string][]*shellcheckScript{}
+	for _, s := range rule.pending {
+		res, owner := rule.cache.acquire(s.key)
+		if !owner {
+			rule.await(res, s.pos)
+			continue
+		}
+		s.result = res
+		batches[s.shell] = append(batches[s.shell], s)
+	}
+	rule.pending = nil
+
+	for _, sh := range []string{"bash", "sh"} {
+		ss := batches[sh]
+		for len(ss) > 0 {
+			n := min(len(ss), shellcheckBatchSize)
+			rule.runBatch(ss[:n])
+			ss = ss[n:]
+		}
+	}
+}
+
+// await waits for the result of the same script checked by other rule and reports it at the
+// position of this script.
+func (rule *RuleShellcheck) await(res *shellcheckResult, pos *Pos) {
+	rule.cmd.eg.Go(func() error {
+		<-res.done
+		if res.err != nil {
+			return res.err
+		}
+		rule.report(res.errs, pos)
+		return nil
+	})
+}
+
+// runBatch runs one shellcheck process for the scripts. All the scripts must be for the same shell.
+func (rule *RuleShellcheck) runBatch(scripts []*shellcheckScript) {
+	fail := func(err error) error {
+		for _, s := range scripts {
+			s.result.resolve(nil, err)
+		}
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "actionlint-shellcheck-")
+	if err != nil {
+		err = fmt.Errorf("could not create temporary directory to run shellcheck: %w", err)
+		rule.cmd.eg.Go(func() error { return fail(err) })
+		return
+	}
+
+	args := slices.Clone(scripts[0].args)
+	files := make(map[string]*shellcheckScript, len(scripts))
+	for i, s := range scripts {
+		f := filepath.Join(dir, fmt.Sprintf("%d.sh", i))
+		if err := os.WriteFile(f, []byte(s.script), 0600); err != nil {
+			os.RemoveAll(dir)
+			err = fmt.Errorf("could not write script at %s to temporary file to run shellcheck: %w", s.pos, err)
+			rule.cmd.eg.Go(func() error { return fail(err) })
+			return
+		}
+		rule.Debug("%s: Run shellcheck for %s script as %s:\n%s", s.pos, s.shell, f, s.script)
+		files[f] = s
+		args = append(args, f)
+	}
+	rule.Debug("Running %s command for %d scripts with %s", rule.cmd.exe, len(scripts), args)
+
+	rule.cmd.run(args, "", func(stdout []byte, err error) error {
+		defer os.RemoveAll(dir)
+
 		if err != nil {
 			rule.Debug("Command %s %s failed: %v", rule.cmd.exe, args, err)
-			return fmt.Errorf("`%s %s` did not run successfully while checking script at %s: %w", rule.cmd.exe, strings.Join(args, " "), pos, err)
+			if len(scripts) > 1 {
+				// Check the scripts one by one so that one bad script does not mask the results of
+				// the other scripts and the failure is reported at the position of the bad script
+				for i := range scripts {
+					rule.runBatch(scripts[i : i+1])
+				}
+				return nil
+			}
+			return fail(fmt.Errorf("`%s %s` did not run successfully while checking script at %s: %w", rule.cmd.exe, strings.Join(args, " "), scripts[0].pos, err))
 		}
 
 		errs := []shellcheckError{}
 		if err := json.Unmarshal(stdout, &errs); err != nil {
-			return fmt.Errorf("could not parse JSON output from shellcheck: %w: stdout=%q", err, stdout)
-		}
-		if len(errs) == 0 {
-			return nil
+			return fail(fmt.Errorf("could not parse JSON output from shellcheck: %w: stdout=%q", err, stdout))
 		}
 
-		// Synchronize rule.Errorf calls
-		rule.mu.Lock()
-		defer rule.mu.Unlock()
-		// It's better to show source location in the script as position of error, but it's not
-		// possible easily. YAML has multiple block styles with '|', '>', '|+', '>+', '|-', '>-'. Some
-		// of them remove indentation and/or blank lines. So restoring source position in block string
-		// is not possible. Sourcemap is necessary to do it.
-		// Instead, actionlint shows position of 'run:' as position of error. And separately show
-		// location in script which is reported by shellcheck in error message.
-		for _, err := range errs {
-			// Consider the first line is setup for running shell which was implicitly added for better check
-			line := err.Line - 1
-			msg := strings.TrimSuffix(err.Message, ".") // Trim period aligning style of error message
-			rule.Errorf(pos, "shellcheck reported issue in this script: SC%d:%s:%d:%d: %s", err.Code, err.Level, line, err.Column, msg)
+		found := make(map[*shellcheckScript][]shellcheckError, len(scripts))
+		for _, e := range errs {
+			s, ok := files[e.File]
+			if !ok {
+				return fail(fmt.Errorf("shellcheck reported an issue in unknown file %q: %s", e.File, e.Message))
+			}
+			e.File = "" // Temporary file path is meaningless after demultiplexing the results
+			found[s] = append(found[s], e)
 		}
 
+		for _, s := range scripts {
+			es := found[s]
+			s.result.resolve(es, nil)
+			rule.cache.store(s.key, es)
+			rule.report(es, s.pos)
+		}
 		return nil
 	})
 }
+
+// report reports the errors found by shellcheck in the script at the position.
+func (rule *RuleShellcheck) report(errs []shellcheckError, pos *Pos) {
+	if len(errs) == 0 {
+		return
+	}
+
+	// Synchronize rule.Errorf calls
+	rule.mu.Lock()
+	defer rule.mu.Unlock()
+	// It's better to show source location in the script as position of error, but it's not
+	// possible easily. YAML has multiple block styles with '|', '>', '|+', '>+', '|-', '>-'. Some
+	// of them remove indentation and/or blank lines. So restoring source position in block string
+	// is not possible. Sourcemap is necessary to do it.
+	// Instead, actionlint shows position of 'run:' as position of error. And separately show
+	// location in script which is reported by shellcheck in error message.
+	for _, err := range errs {
+		// Consider the first line is setup for running shell which was implicitly added for better check
+		line := err.Line - 1
+		msg := strings.TrimSuffix(err.Message, ".") // Trim period aligning style of error message
+		rule.ErrorfWithCode(pos, fmt.Sprintf("SC%d", err.Code), "shellcheck reported issue in this script: SC%d:%s:%d:%d: %s", err.Code, err.Level, line, err.Column, msg)
+	}
+}
@@ -1,8 +1,16 @@
 package actionlint
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestRuleShellcheckSanitizeExpressionsInScript(t *testing.T) {
@@ -197,3 +205,210 @@ func TestRuleShellcheckDetectShell(t *testing.T) {
 		})
 	}
 }
+
+// testFakeShellcheck creates a fake shellcheck executable which reports one issue for each script
+// file passed as argument. The message of the issue is the script. Each process and each script
+// are recorded in the log file as "process" and "script" lines. Getting the version is not recorded.
+func testFakeShellcheck(t *testing.T) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake shellcheck script does not work on Windows")
+	}
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	exe := filepath.Join(dir, "shellcheck")
+	src := fmt.Sprintf(`#!/bin/sh
+if [ "$1" = --version ]; then
+  echo 'version: 0.0.0'
+  exit
+fi
+log=%q
+echo process >> "$log"
+for a in "$@"; do
+  case "$a" in
+    *.sh)
+      if grep -q crash "$a"; then
+        echo 'crashed' >&2
+        exit 1
+      fi
+      ;;
+  esac
+done
+sep=
+printf '['
+for a in "$@"; do
+  case "$a" in
+    *.sh)
+      echo script >> "$log"
+      printf '%%s{"file":"%%s","line":2,"column":6,"level":"info","code":2086,"message":"%%s"}' "$sep" "$a" "$(sed -n 2p "$a")"
+      sep=,
+      ;;
+  esac
+done
+echo ']'
+`, log)
+	if err := os.WriteFile(exe, []byte(src), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return exe, log
+}
+
+func testCountLogs(t *testing.T, path, line string) int {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(b, []byte(line+"\n"))
+}
+
+func testShellcheckErrorsAt(errs []*Error) map[int]string {
+	ret := map[int]string{}
+	for _, err := range errs {
+		if err.Kind == "shellcheck" {
+			ret[err.Line] = err.Message
+		}
+	}
+	return ret
+}
+
+func TestRuleShellcheckCacheDeduplicateScripts(t *testing.T) {
+	exe, log := testFakeShellcheck(t)
+	src := `on: push
+jobs:
+  a:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo $FOO
+  b:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo $FOO
+  c:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo $FOO
+      - run: echo $BAR
+`
+	cache := filepath.Join(t.TempDir(), "cache")
+
+	lint := func(path string) []*Error {
+		t.Helper()
+		l, err := NewLinter(io.Discard, &LinterOptions{Shellcheck: exe, CacheDir: cache})
+		if err != nil {
+			t.Fatal(err)
+		}
+		errs, err := l.Lint(path, []byte(src), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return errs
+	}
+
+	errs := lint("test.yaml")
+	if n := testCountLogs(t, log, "script"); n != 2 {
+		t.Fatalf("shellcheck should check each unique script once but it checked %d scripts", n)
+	}
+	want := map[int]string{
+		6:  "shellcheck reported issue in this script: SC2086:info:1:6: echo $FOO",
+		10: "shellcheck reported issue in this script: SC2086:info:1:6: echo $FOO",
+		14: "shellcheck reported issue in this script: SC2086:info:1:6: echo $FOO",
+		15: "shellcheck reported issue in this script: SC2086:info:1:6: echo $BAR",
+	}
+	if diff := cmp.Diff(want, testShellcheckErrorsAt(errs)); diff != "" {
+		t.Fatal("errors were not reported at each occurrence of the scripts:", diff)
+	}
+
+	// Results are reused across runs via the cache directory. Use another file path to avoid
+	// reusing the result of the entire file
+	for _, err := range errs {
+		err.Filepath = "other.yaml"
+	}
+	if diff := cmp.Diff(errs, lint("other.yaml")); diff != "" {
+		t.Fatal("cached results are different from the first run:", diff)
+	}
+	if n := testCountLogs(t, log, "script"); n != 2 {
+		t.Fatalf("shellcheck should not run with the cache but it checked %d scripts in total", n)
+	}
+}
+
+func TestRuleShellcheckBatchScripts(t *testing.T) {
+	exe, log := testFakeShellcheck(t)
+	var b strings.Builder
+	b.WriteString(`on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo $SH
+        shell: sh
+`)
+	n := shellcheckBatchSize + 1
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "      - run: echo $V%d\n", i)
+	}
+
+	l, err := NewLinter(io.Discard, &LinterOptions{Shellcheck: exe})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, err := l.Lint("test.yaml", []byte(b.String()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One process for sh script, and two processes for bash scripts since the number of them
+	// exceeds the batch size
+	if p := testCountLogs(t, log, "process"); p != 3 {
+		t.Fatalf("wanted 3 shellcheck processes but got %d", p)
+	}
+	if s := testCountLogs(t, log, "script"); s != n+1 {
+		t.Fatalf("wanted %d scripts checked but got %d", n+1, s)
+	}
+
+	want := map[int]string{
+		6: "shellcheck reported issue in this script: SC2086:info:1:6: echo $SH",
+	}
+	for i := 0; i < n; i++ {
+		want[8+i] = fmt.Sprintf("shellcheck reported issue in this script: SC2086:info:1:6: echo $V%d", i)
+	}
+	if diff := cmp.Diff(want, testShellcheckErrorsAt(errs)); diff != "" {
+		t.Fatal("errors were not demultiplexed to the positions of the scripts:", diff)
+	}
+}
+
+func TestRuleShellcheckBatchFailure(t *testing.T) {
+	exe, log := testFakeShellcheck(t)
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo $FOO
+      - run: crash
+      - run: echo $BAR
+`
+	l, err := NewLinter(io.Discard, &LinterOptions{Shellcheck: exe})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = l.Lint("test.yaml", []byte(src), nil)
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	if msg := err.Error(); !strings.Contains(msg, "line:7,col:9") {
+		t.Fatalf("failure was not reported at the position of the bad script: %q", msg)
+	}
+
+	// One process for the batch and one process for each script on retry
+	if p := testCountLogs(t, log, "process"); p != 4 {
+		t.Fatalf("wanted 4 shellcheck processes but got %d", p)
+	}
+	if s := testCountLogs(t, log, "script"); s != 2 {
+		t.Fatalf("wanted 2 scripts checked but got %d", s)
+	}
+}
@@ -50,6 +50,27 @@ func TestRuleBaseErrorfAndErrs(t *testing.T) {
 	}
 }
 
+func TestRuleBaseErrorfWithExtraInformation(t *testing.T) {
+	r := NewRuleBase("dummy", "")
+	sug := &Suggestion{Line: 2, Column: 1, EndLine: 2, EndColumn: 3, Text: "bar"}
+	rel := []RelatedLocation{{Line: 5, Column: 6, Note: "related"}}
+	r.ErrorfRange(&Pos{Line: 1, Col: 2}, &Pos{Line: 1, Col: 4}, "range %d", 1)
+	r.ErrorfWithSuggestion(&Pos{Line: 2, Col: 1}, sug, "suggestion %d", 2)
+	r.ErrorfWithRelated(&Pos{Line: 3, Col: 1}, rel, "related %d", 3)
+	e := r.ErrorfWithCode(&Pos{Line: 4, Col: 1}, "sub-code", "code %d", 4)
+	e.Related = rel
+
+	want := []*Error{
+		{Message: "range 1", Line: 1, Column: 2, EndLine: 1, EndColumn: 4, Kind: "dummy"},
+		{Message: "suggestion 2", Line: 2, Column: 1, Kind: "dummy", Suggestions: []*Suggestion{sug}},
+		{Message: "related 3", Line: 3, Column: 1, Kind: "dummy", Related: rel},
+		{Message: "code 4", Line: 4, Column: 1, Kind: "dummy", Code: "dummy/sub-code", Related: rel},
+	}
+	if diff := cmp.Diff(r.Errs(), want); diff != "" {
+		t.Error("unexpected errors from Errs() method:", diff)
+	}
+}
+
 func TestRuleBaseDebugOutput(t *testing.T) {
 	r := NewRuleBase("dummy-name", "")
 	r.Debug("this %s output", "is not")
@@ -11,6 +11,9 @@ type RuleWorkflowCall struct {
 	workflowCallEventPos *Pos
 	workflowPath         string
 	cache                *LocalReusableWorkflowCache
+	// repoOwner is the owner of the repository of the workflow. It is trusted by "secrets: inherit"
+	// check. It is empty when it is unknown.
+	repoOwner string
 }
 
 // NewRuleWorkflowCall creates a new RuleWorkflowCall instance. 'workflowPath' is a file path to
@@ -27,6 +30,33 @@ func NewRuleWorkflowCall(workflowPath string, cache *LocalReusableWorkflowCache)
 	}
 }
 
+// ForkJob implements JobLocalPass interface. The copy shares the position of the workflow_call
+// event, the workflow path, and the cache of local reusable workflows, which is safe for concurrent
+// use.
+func (rule *RuleWorkflowCall) ForkJob() Pass {
+	return forkRule(rule)
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleWorkflowCall) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Calls of reusable workflows at "jobs.<job_id>.uses:" are checked. The format of
+"uses:" is validated. Inputs and secrets passed to local reusable workflows are
+checked with their "workflow_call" event definitions. "secrets: inherit" for
+reusable workflows in untrusted repositories is reported.`
+	m.BadExample = `on: push
+jobs:
+  call:
+    uses: ./.github/workflows/reusable.yaml@main
+`
+	m.GoodExample = `on: push
+jobs:
+  call:
+    uses: ./.github/workflows/reusable.yaml
+`
+	return m
+}
+
 // VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
 func (rule *RuleWorkflowCall) VisitWorkflowPre(n *Workflow) error {
 	for _, e := range n.On {
@@ -58,6 +88,7 @@ func (rule *RuleWorkflowCall) VisitJobPre(n *Job) error {
 	}
 
 	if isWorkflowCallUsesRepoFormat(u.Value) {
+		rule.checkInheritSecretsToRepo(n.WorkflowCall)
 		return nil
 	}
 
@@ -68,8 +99,9 @@ func (rule *RuleWorkflowCall) VisitJobPre(n *Job) error {
 		rule.cache.writeCache(u.Value, nil)
 	}
 
-	rule.Errorf(
+	rule.ErrorfWithCode(
 		u.Pos,
+		"invalid-uses",
 		"reusable workflow call %q at \"uses\" is not following the format \"owner/repo/path/to/workflow.yml@ref\" nor \"./path/to/workflow.yml\". see https://docs.github.com/en/actions/learn-github-actions/reusing-workflows for more details",
 		u.Value,
 	)
@@ -80,11 +112,14 @@ func (rule *RuleWorkflowCall) checkWorkflowCallUsesLocal(call *WorkflowCall) {
 	u := call.Uses
 	m, err := rule.cache.FindMetadata(u.Value)
 	if err != nil {
-		rule.Error(u.Pos, err.Error())
+		rule.ErrorfWithCode(u.Pos, "metadata-error", "%s", err.Error())
 		return
 	}
 	if m == nil {
 		rule.Debug("Skip workflow call %q since no metadata was found", u.Value)
+		if call.InheritSecrets {
+			rule.checkInheritSecretsToLocal(call, nil)
+		}
 		return
 	}
 
@@ -92,7 +127,7 @@ func (rule *RuleWorkflowCall) checkWorkflowCallUsesLocal(call *WorkflowCall) {
 	for n, i := range m.Inputs {
 		if i != nil && i.Required {
 			if _, ok := call.Inputs[n]; !ok {
-				rule.Errorf(u.Pos, "input %q is required by %q reusable workflow", i.Name, u.Value)
+				rule.ErrorfWithCode(u.Pos, "missing-required-input", "input %q is required by %q reusable workflow", i.Name, u.Value)
 			}
 		}
 	}
@@ -110,16 +145,18 @@ func (rule *RuleWorkflowCall) checkWorkflowCallUsesLocal(call *WorkflowCall) {
 					note = "defined inputs are " + sortedQuotes(is)
 				}
 			}
-			rule.Errorf(i.Name.Pos, "input %q is not defined in %q reusable workflow. %s", i.Name.Value, u.Value, note)
+			rule.ErrorfWithCode(i.Name.Pos, "undefined-input", "input %q is not defined in %q reusable workflow. %s", i.Name.Value, u.Value, note)
 		}
 	}
 
 	// Validate secrets
-	if !call.InheritSecrets {
+	if call.InheritSecrets {
+		rule.checkInheritSecretsToLocal(call, m)
+	} else {
 		for n, s := range m.Secrets {
 			if s.Required {
 				if _, ok := call.Secrets[n]; !ok {
-					rule.Errorf(u.Pos, "secret %q is required by %q reusable workflow", s.Name, u.Value)
+					rule.ErrorfWithCode(u.Pos, "missing-required-secret", "secret %q is required by %q reusable workflow", s.Name, u.Value)
 				}
 			}
 		}
@@ -137,7 +174,7 @@ func (rule *RuleWorkflowCall) checkWorkflowCallUsesLocal(call *WorkflowCall) {
 						note = "defined secrets are " + sortedQuotes(ss)
 					}
 				}
-				rule.Errorf(s.Name.Pos, "secret %q is not defined in %q reusable workflow. %s", s.Name.Value, u.Value, note)
+				rule.ErrorfWithCode(s.Name.Pos, "undefined-secret", "secret %q is not defined in %q reusable workflow. %s", s.Name.Value, u.Value, note)
 			}
 		}
 	}
@@ -145,6 +182,62 @@ func (rule *RuleWorkflowCall) checkWorkflowCallUsesLocal(call *WorkflowCall) {
 	rule.Debug("Validated reusable workflow %q", u.Value)
 }
 
+// Check `secrets: inherit` at a call of reusable workflow in other repository. Inheriting secrets
+// passes all secrets of this repository to the workflow. It is only allowed for the workflows owned
+// by the owner of this repository or the trusted owners.
+func (rule *RuleWorkflowCall) checkInheritSecretsToRepo(call *WorkflowCall) {
+	if !call.InheritSecrets {
+		return
+	}
+
+	u := call.Uses
+	owner := u.Value[:strings.IndexRune(u.Value, '/')] // Format was already validated
+	if rule.repoOwner != "" && strings.EqualFold(rule.repoOwner, owner) {
+		rule.Debug("Secrets are inherited by %q since it is owned by the owner of this repository", u.Value)
+		return
+	}
+	if rule.config != nil {
+		for _, o := range rule.config.SecretsInherit.TrustedOwners {
+			if strings.EqualFold(o, owner) {
+				rule.Debug("Secrets are inherited by %q since owner %q is trusted", u.Value, owner)
+				return
+			}
+		}
+	}
+
+	rule.ErrorfWithCode(
+		u.Pos,
+		"inherit-secrets",
+		"\"secrets: inherit\" passes all secrets to reusable workflow %q in external repository. owner %q is not trusted. pass only the secrets which the workflow requires explicitly with \"secrets:\" or add the owner to \"trusted-owners\" in \"secrets-inherit\" configuration",
+		u.Value,
+		owner,
+	)
+}
+
+// Check `secrets: inherit` at a call of local reusable workflow. Local reusable workflows are
+// trusted so this check is only enabled by "check-local" in "secrets-inherit" configuration. When
+// the metadata of the reusable workflow is known, the message suggests the secrets defined in it.
+func (rule *RuleWorkflowCall) checkInheritSecretsToLocal(call *WorkflowCall, m *ReusableWorkflowMetadata) {
+	if rule.config == nil || !rule.config.SecretsInherit.CheckLocal {
+		return
+	}
+
+	note := "pass only the secrets which the workflow requires explicitly with \"secrets:\""
+	if m != nil {
+		if len(m.Secrets) == 0 {
+			note = "the workflow defines no secret. remove \"secrets: inherit\""
+		} else {
+			ss := make([]string, 0, len(m.Secrets))
+			for _, s := range m.Secrets {
+				ss = append(ss, s.Name)
+			}
+			note = fmt.Sprintf("the workflow defines secrets %s. pass them explicitly with \"secrets:\"", sortedQuotes(ss))
+		}
+	}
+
+	rule.ErrorfWithCode(call.Uses.Pos, "inherit-secrets", "\"secrets: inherit\" passes all secrets to reusable workflow %q. %s", call.Uses.Value, note)
+}
+
 // Parse ./{path/{filename}
 // https://docs.github.com/en/actions/learn-github-actions/reusing-workflows#calling-a-reusable-workflow
 func isWorkflowCallUsesLocalFormat(u string) bool {
@@ -123,7 +123,7 @@ func TestRuleWorkflowCallWriteEventNodeToMetadataCache(t *testing.T) {
 	}
 
 	cwd := filepath.Join("path", "to", "project")
-	c := NewLocalReusableWorkflowCache(&Project{cwd, nil}, cwd, nil)
+	c := NewLocalReusableWorkflowCache(&Project{cwd, nil, false, ""}, cwd, nil)
 	r := NewRuleWorkflowCall("test-workflow.yaml", c)
 
 	if err := r.VisitWorkflowPre(w); err != nil {
@@ -142,7 +142,7 @@ func TestRuleWorkflowCallWriteEventNodeToMetadataCache(t *testing.T) {
 
 	want := &ReusableWorkflowMetadata{
 		Inputs: ReusableWorkflowMetadataInputs{
-			"input1": {"input1", false, StringType{}},
+			"input1": {"input1", false, StringType{}, &Pos{}},
 		},
 		Outputs: ReusableWorkflowMetadataOutputs{
 			"output1": {"output1"},
@@ -150,6 +150,7 @@ func TestRuleWorkflowCallWriteEventNodeToMetadataCache(t *testing.T) {
 		Secrets: ReusableWorkflowMetadataSecrets{
 			"secret1": {"secret1", false},
 		},
+		Path: "test-workflow.yaml",
 	}
 
 	if diff := cmp.Diff(want, m); diff != "" {
@@ -159,14 +160,14 @@ func TestRuleWorkflowCallWriteEventNodeToMetadataCache(t *testing.T) {
 
 func TestRuleWorkflowCallCheckReusableWorkflowCall(t *testing.T) {
 	cwd := filepath.Join("testdata", "reusable_workflow_metadata")
-	cache := NewLocalReusableWorkflowCache(&Project{cwd, nil}, cwd, nil)
+	cache := NewLocalReusableWorkflowCache(&Project{cwd, nil, false, ""}, cwd, nil)
 
 	for i, md := range []*ReusableWorkflowMetadata{
 		// workflow0.yaml
 		{
 			Inputs: ReusableWorkflowMetadataInputs{
-				"optional_input": {"optional_input", false, StringType{}},
-				"required_input": {"required_input", true, StringType{}},
+				"optional_input": {"optional_input", false, StringType{}, nil},
+				"required_input": {"required_input", true, StringType{}, nil},
 			},
 			Outputs: ReusableWorkflowMetadataOutputs{
 				"output": {"output"},
@@ -179,8 +180,8 @@ func TestRuleWorkflowCallCheckReusableWorkflowCall(t *testing.T) {
 		// workflow1.yaml: Inputs and outputs in upper case (#216)
 		{
 			Inputs: ReusableWorkflowMetadataInputs{
-				"optional_input": {"OPTIONAL_INPUT", false, StringType{}},
-				"required_input": {"REQUIRED_INPUT", true, StringType{}},
+				"optional_input": {"OPTIONAL_INPUT", false, StringType{}, nil},
+				"required_input": {"REQUIRED_INPUT", true, StringType{}, nil},
 			},
 			Outputs: ReusableWorkflowMetadataOutputs{
 				"output": {"OUTPUT"},
@@ -383,3 +384,113 @@ func TestRuleWorkflowCallCheckReusableWorkflowCall(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleWorkflowCallInheritSecrets(t *testing.T) {
+	cwd := filepath.Join("testdata", "reusable_workflow_metadata")
+	cache := NewLocalReusableWorkflowCache(&Project{cwd, nil, false, ""}, cwd, nil)
+	cache.writeCache("./with_secrets.yaml", &ReusableWorkflowMetadata{
+		Secrets: ReusableWorkflowMetadataSecrets{
+			"foo": {"FOO", true},
+			"bar": {"bar", false},
+		},
+	})
+	cache.writeCache("./no_secret.yaml", &ReusableWorkflowMetadata{})
+
+	tests := []struct {
+		what   string
+		uses   string
+		owners []string
+		repo   string
+		local  bool
+		want   string
+	}{
+		{
+			what: "external workflow",
+			uses: "owner/repo/.github/workflows/x.yaml@main",
+			want: `"secrets: inherit" passes all secrets to reusable workflow "owner/repo/.github/workflows/x.yaml@main" in external repository. owner "owner" is not trusted`,
+		},
+		{
+			what:   "external workflow of untrusted owner",
+			uses:   "owner/repo/.github/workflows/x.yaml@main",
+			owners: []string{"other", "owner2"},
+			want:   `owner "owner" is not trusted`,
+		},
+		{
+			what:   "external workflow of trusted owner",
+			uses:   "owner/repo/.github/workflows/x.yaml@main",
+			owners: []string{"other", "owner"},
+		},
+		{
+			what:   "owner is case insensitive",
+			uses:   "Owner/repo/.github/workflows/x.yaml@main",
+			owners: []string{"OWNER"},
+		},
+		{
+			what: "external workflow of repository owner",
+			uses: "owner/repo/.github/workflows/x.yaml@main",
+			repo: "Owner",
+		},
+		{
+			what: "external workflow of other than repository owner",
+			uses: "owner/repo/.github/workflows/x.yaml@main",
+			repo: "other",
+			want: `owner "owner" is not trusted`,
+		},
+		{
+			what: "local workflow",
+			uses: "./with_secrets.yaml",
+		},
+		{
+			what:  "check local workflow",
+			uses:  "./with_secrets.yaml",
+			local: true,
+			want:  `"secrets: inherit" passes all secrets to reusable workflow "./with_secrets.yaml". the workflow defines secrets "FOO", "bar". pass them explicitly`,
+		},
+		{
+			what:  "check local workflow defining no secret",
+			uses:  "./no_secret.yaml",
+			local: true,
+			want:  `the workflow defines no secret. remove "secrets: inherit"`,
+		},
+		{
+			what:  "check local workflow with unknown metadata",
+			uses:  "./.github/workflows/${{ env.FOO }}.yaml",
+			local: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.what, func(t *testing.T) {
+			r := NewRuleWorkflowCall("this-workflow.yaml", cache)
+			r.repoOwner = tc.repo
+			cfg := &Config{}
+			cfg.SecretsInherit.TrustedOwners = tc.owners
+			cfg.SecretsInherit.CheckLocal = tc.local
+			r.SetConfig(cfg)
+
+			j := &Job{
+				WorkflowCall: &WorkflowCall{
+					Uses:           &String{Value: tc.uses, Pos: &Pos{}},
+					InheritSecrets: true,
+				},
+			}
+			if err := r.VisitJobPre(j); err != nil {
+				t.Fatal(err)
+			}
+
+			errs := r.Errs()
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatal("wanted one error but got", errs)
+			}
+			if msg := errs[0].Message; !strings.Contains(msg, tc.want) {
+				t.Fatalf("%q is not contained in error message %q", tc.want, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// Events which can be triggered by someone who doesn't have write access to the repository. Code or
+// inputs controlled by the person can be run or used in workflows triggered by these events.
+// https://securitylab.github.com/research/github-actions-preventing-pwn-requests/
+var untrustedTriggerEvents = map[string]struct{}{
+	"pull_request":                {},
+	"pull_request_target":         {},
+	"pull_request_review":         {},
+	"pull_request_review_comment": {},
+	"issue_comment":               {},
+	"issues":                      {},
+	"discussion":                  {},
+	"discussion_comment":          {},
+	"fork":                        {},
+	"watch":                       {},
+}
+
+// Setup actions which save and restore caches when "cache" input is set.
+var cacheableSetupActions = map[string]struct{}{
+	"actions/setup-node":   {},
+	"actions/setup-python": {},
+	"actions/setup-go":     {},
+	"actions/setup-java":   {},
+	"actions/setup-dotnet": {},
+}
+
+// actionNameOf returns the action name in "{owner}/{repo}" or "{owner}/{repo}/{path}" format and
+// the action node of the step. The name is in lower case since action names are case-insensitive.
+// When the step does not run an action, this function returns an empty string and nil.
+func actionNameOf(s *Step) (string, *ExecAction) {
+	e, ok := s.Exec.(*ExecAction)
+	if !ok || e.Uses == nil || e.Uses.ContainsExpression() {
+		return "", nil
+	}
+	n := e.Uses.Value
+	if i := strings.IndexRune(n, '@'); i >= 0 {
+		n = n[:i]
+	}
+	return strings.ToLower(n), e
+}
+
+func hasCacheInput(e *ExecAction) bool {
+	i, ok := e.Inputs["cache"]
+	if !ok || i.Value == nil {
+		return false
+	}
+	v := strings.TrimSpace(i.Value.Value)
+	return v != "" && v != "false"
+}
+
+func isArtifactUploadStep(s *Step) bool {
+	n, _ := actionNameOf(s)
+	return n == "actions/upload-artifact"
+}
+
+func isArtifactDownloadStep(s *Step) bool {
+	n, e := actionNameOf(s)
+	switch n {
+	case "actions/download-artifact":
+		// Without "run-id" input, this action only downloads artifacts uploaded in the current workflow run
+		_, ok := e.Inputs["run-id"]
+		return ok
+	case "dawidd6/action-download-artifact":
+		return true
+	default:
+		return false
+	}
+}
+
+func isCacheSaveStep(s *Step) bool {
+	n, e := actionNameOf(s)
+	switch n {
+	case "actions/cache", "actions/cache/save":
+		return true
+	default:
+		_, ok := cacheableSetupActions[n]
+		return ok && hasCacheInput(e)
+	}
+}
+
+func isCacheRestoreStep(s *Step) bool {
+	n, e := actionNameOf(s)
+	switch n {
+	case "actions/cache", "actions/cache/restore":
+		return true
+	default:
+		_, ok := cacheableSetupActions[n]
+		return ok && hasCacheInput(e)
+	}
+}
+
+// poisoningSource is a workflow triggered by an untrusted event which uploads artifacts or saves
+// caches. They can be poisoned by attackers.
+type poisoningSource struct {
+	workflow  *LocalWorkflow
+	event     string
+	artifacts bool
+	caches    bool
+}
+
+func newPoisoningSource(w *LocalWorkflow) *poisoningSource {
+	event := ""
+	for _, e := range w.Workflow.On {
+		n := e.EventName()
+		if _, ok := untrustedTriggerEvents[n]; ok {
+			event = n
+			break
+		}
+	}
+	if event == "" {
+		return nil
+	}
+
+	s := &poisoningSource{workflow: w, event: event}
+	for _, j := range w.Workflow.Jobs {
+		for _, step := range j.Steps {
+			if isArtifactUploadStep(step) {
+				s.artifacts = true
+			}
+			if isCacheSaveStep(step) {
+				s.caches = true
+			}
+		}
+	}
+	if !s.artifacts && !s.caches {
+		return nil
+	}
+	return s
+}
+
+// RuleWorkflowRun is a rule to check artifacts and caches consumed by privileged workflows triggered
+// by "workflow_run" event. Workflows triggered by "workflow_run" event run with secrets and a
+// write-access token even if the triggering workflow was run by an untrusted event. When they use
+// artifacts or caches written by the triggering workflow, attackers can poison them.
+// https://securitylab.github.com/research/github-actions-preventing-pwn-requests/
+type RuleWorkflowRun struct {
+	RuleBase
+	cache   *LocalWorkflowsCache
+	sources []*poisoningSource
+}
+
+// NewRuleWorkflowRun creates a new RuleWorkflowRun instance.
+func NewRuleWorkflowRun(cache *LocalWorkflowsCache) *RuleWorkflowRun {
+	return &RuleWorkflowRun{
+		RuleBase: RuleBase{
+			name: "workflow-run",
+			desc: "Checks for artifacts and caches used in \"workflow_run\" workflows which were written by workflows triggered by untrusted events",
+		},
+		cache:   cache,
+		sources: nil,
+	}
+}
+
+// Metadata returns the metadata of the rule to explain it.
+func (rule *RuleWorkflowRun) Metadata() *RuleMetadata {
+	m := rule.RuleBase.Metadata()
+	m.Explanation = `Workflows triggered by "workflow_run" event are privileged. When the triggering
+workflow can be run by untrusted events such as "pull_request", artifacts
+uploaded and caches saved by it may be poisoned. Downloading such artifacts and
+restoring such caches in the privileged workflow are reported. This rule looks
+up the triggering workflows in the same repository.`
+	m.BadExample = `# Triggered by "CI" workflow which runs on "pull_request" event and uploads artifacts
+on:
+  workflow_run:
+    workflows: [CI]
+    types: [completed]
+jobs:
+  report:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/download-artifact@v5
+`
+	m.GoodExample = `# Triggered by "CI" workflow which runs on "pull_request" event and uploads artifacts
+on:
+  workflow_run:
+    workflows: [CI]
+    types: [completed]
+jobs:
+  report:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "CI finished with $CONCLUSION"
+        env:
+          CONCLUSION: ${{ github.event.workflow_run.conclusion }}
+`
+	return m
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleWorkflowRun) VisitWorkflowPre(n *Workflow) error {
+	for _, e := range n.On {
+		e, ok := e.(*WebhookEvent)
+		if !ok || e.Hook.Value != "workflow_run" {
+			continue
+		}
+		for _, name := range e.Workflows {
+			if name.ContainsExpression() {
+				continue
+			}
+			ws, err := rule.cache.FindByName(name.Value)
+			if err != nil {
+				rule.ErrorfWithCode(name.Pos, "metadata-error", "%s", err.Error())
+				continue
+			}
+			for _, w := range ws {
+				if s := newPoisoningSource(w); s != nil {
+					rule.Debug("Workflow %q at %q triggered by untrusted event %q writes artifacts=%v, caches=%v", name.Value, w.Path, s.event, s.artifacts, s.caches)
+					rule.sources = append(rule.sources, s)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleWorkflowRun) VisitStep(n *Step) error {
+	if len(rule.sources) == 0 {
+		return nil
+	}
+
+	what := ""
+	var src *poisoningSource
+	if isArtifactDownloadStep(n) {
+		for _, s := range rule.sources {
+			if s.artifacts {
+				what, src = "artifacts uploaded", s
+				break
+			}
+		}
+	} else if isCacheRestoreStep(n) {
+		for _, s := range rule.sources {
+			if s.caches {
+				what, src = "caches saved", s
+				break
+			}
+		}
+	}
+	if src == nil {
+		return nil
+	}
+
+	e := n.Exec.(*ExecAction)
+	rule.ErrorfWithCode(
+		e.Uses.Pos,
+		"poisoning",
+		"%s by workflow %q at %q may be poisoned since the workflow is triggered by untrusted %q event. this workflow triggered by \"workflow_run\" event is privileged. validate the contents carefully or avoid using them. see https://securitylab.github.com/research/github-actions-preventing-pwn-requests/ for more details",
+		what,
+		src.workflow.Name(),
+		src.workflow.Path,
+		src.event,
+	)
+	return nil
+}
+
+// VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
+func (rule *RuleWorkflowRun) VisitWorkflowPost(n *Workflow) error {
+	rule.sources = nil
+	return nil
+}
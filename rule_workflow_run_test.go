@@ -0,0 +1,169 @@
+package actionlint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRuleWorkflowRunDetectPoisoning(t *testing.T) {
+	tests := []struct {
+		what string
+		src  string
+		want []string
+	}{
+		{
+			what: "download artifacts from untrusted workflow",
+			src: `
+on:
+  workflow_run:
+    workflows: [CI]
+    types: [completed]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/download-artifact@v4
+        with:
+          name: dist
+          run-id: ${{ github.event.workflow_run.id }}
+          github-token: ${{ secrets.GITHUB_TOKEN }}
+      - uses: dawidd6/action-download-artifact@v6
+`,
+			want: []string{
+				`:10:15: artifacts uploaded by workflow "CI" at ".github/workflows/ci.yaml" may be poisoned since the workflow is triggered by untrusted "pull_request" event`,
+				`:15:15: artifacts uploaded by workflow "CI" at ".github/workflows/ci.yaml" may be poisoned`,
+			},
+		},
+		{
+			what: "restore caches saved by untrusted workflow",
+			src: `
+on:
+  workflow_run:
+    workflows: [CI, .github/workflows/no_name.yml]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/cache/restore@v4
+        with:
+          path: ~/.npm
+          key: npm
+      - uses: actions/setup-node@v4
+        with:
+          cache: npm
+`,
+			want: []string{
+				`:9:15: caches saved by workflow "CI" at ".github/workflows/ci.yaml" may be poisoned`,
+				`:13:15: caches saved by workflow "CI" at ".github/workflows/ci.yaml" may be poisoned`,
+			},
+		},
+		{
+			what: "untrusted workflow without name",
+			src: `
+on:
+  workflow_run:
+    workflows: [.github/workflows/no_name.yml]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/cache@v4
+        with:
+          path: ~/.npm
+          key: npm
+      - uses: actions/download-artifact@v4
+        with:
+          run-id: ${{ github.event.workflow_run.id }}
+`,
+			want: []string{
+				`:9:15: caches saved by workflow ".github/workflows/no_name.yml" at ".github/workflows/no_name.yml" may be poisoned since the workflow is triggered by untrusted "issue_comment" event`,
+			},
+		},
+		{
+			what: "artifacts of the current workflow run",
+			src: `
+on:
+  workflow_run:
+    workflows: [CI]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/download-artifact@v4
+        with:
+          name: dist
+`,
+		},
+		{
+			what: "trusted workflow",
+			src: `
+on:
+  workflow_run:
+    workflows: [Release]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/download-artifact@v4
+        with:
+          run-id: ${{ github.event.workflow_run.id }}
+`,
+		},
+		{
+			what: "unknown workflow",
+			src: `
+on:
+  workflow_run:
+    workflows: [Unknown, "${{ env.NAME }}"]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: dawidd6/action-download-artifact@v6
+`,
+		},
+		{
+			what: "not triggered by workflow_run",
+			src: `
+on: push
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: dawidd6/action-download-artifact@v6
+      - uses: actions/cache@v4
+        with:
+          path: ~/.npm
+          key: npm
+`,
+		},
+	}
+
+	proj := &Project{filepath.Join("testdata", "local_workflows"), nil, false, ""}
+	for _, tc := range tests {
+		t.Run(tc.what, func(t *testing.T) {
+			w, errs := Parse([]byte(tc.src))
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
+			r := NewRuleWorkflowRun(NewLocalWorkflowsCache(proj, nil))
+			v := NewVisitor()
+			v.AddPass(r)
+			if err := v.Visit(w); err != nil {
+				t.Fatal(err)
+			}
+
+			errs = r.Errs()
+			if len(errs) != len(tc.want) {
+				t.Fatalf("wanted %d errors but got %d errors: %v", len(tc.want), len(errs), errs)
+			}
+			for i, err := range errs {
+				if have := err.Error(); !strings.Contains(have, tc.want[i]) {
+					t.Errorf("error %q does not contain %q", have, tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package actionlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Server is a long-running linter which serves JSON-RPC 2.0 requests over HTTP. One Linter instance
+// and the caches of local actions, local reusable workflows, and local workflows are shared by all
+// requests so that clients like editor plugins do not pay the cost of starting actionlint and
+// warming up the caches for each check. The caches are kept until they are invalidated by the
+// "invalidate" method.
+//
+// The following methods are available. The Server type implements http.Handler so it can be served
+// with httptest.Server in tests of clients.
+//
+//   - "lint": Checks the content of a workflow. Params are {"path": string, "content": string}
+//   - "lintFiles": Checks the workflow files. Params are {"files": [string]}
+//   - "listRules": Returns the metadata of all built-in rules. No param is necessary
+//   - "invalidate": Invalidates the caches for the files. Params are {"files": [string]}. When no
+//     file is given, all caches are discarded and config files are read again
+//
+// The result of "lint" and "lintFiles" is {"errors": [...]}. Each error has the same fields as
+// the {{json .}} output of -format flag.
+type Server struct {
+	linter *Linter
+	// mu is locked for reading while checking workflows and is locked for writing while
+	// invalidating the caches.
+	mu   sync.RWMutex
+	acf  *LocalActionsCacheFactory
+	rwcf *LocalReusableWorkflowCacheFactory
+	lwcf *LocalWorkflowsCacheFactory
+}
+
+// NewServer creates a new Server instance. The opts parameter configures the shared Linter
+// instance. Options to output errors like Format are ignored since errors are returned in
+// responses.
+func NewServer(opts *LinterOptions) (*Server, error) {
+	o := *opts
+	o.Format = ""
+	l, err := NewLinter(io.Discard, &o)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{linter: l}
+	s.resetCaches()
+	return s, nil
+}
+
+func (s *Server) resetCaches() {
+	dbg := s.linter.debugWriter()
+	s.acf = NewLocalActionsCacheFactory(dbg)
+	s.rwcf = NewLocalReusableWorkflowCacheFactory(s.linter.cwd, dbg)
+	s.lwcf = NewLocalWorkflowsCacheFactory(dbg)
+}
+
+// Lint checks the content of the workflow. The path parameter is a file path where the content
+// came from. When the file exists, the project is detected from the path. Calling this method is
+// thread-safe.
+func (s *Server) Lint(path string, content []byte) ([]*Error, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var proj *Project
+	if _, err := os.Stat(path); err == nil {
+		p, err := s.linter.projects.At(path)
+		if err != nil {
+			return nil, err
+		}
+		proj = p
+	}
+
+	proc := s.linter.newProcess()
+	errs, err := s.linter.check(path, content, proj, proc, s.acf.GetCache(proj), s.rwcf.GetCache(proj), s.lwcf.GetCache(proj))
+	proc.wait()
+	return errs, err
+}
+
+// LintFiles checks the workflow files and returns the results in the same order as the file
+// paths. Calling this method is thread-safe.
+func (s *Server) LintFiles(files []string) ([]*FileResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.linter.checkFiles(files, nil, s.acf, s.rwcf, s.lwcf, nil)
+}
+
+// ListRules returns the metadata of all built-in rules.
+func (s *Server) ListRules() []*RuleMetadata {
+	return BuiltinRuleMetadata()
+}
+
+// Invalidate invalidates the cache entries which depend on the files. Call this method when the
+// files are changed. When no file is given, all caches are discarded and config files of projects
+// are read again. Calling this method is thread-safe. It waits until running checks finish.
+func (s *Server) Invalidate(files []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(files) == 0 {
+		s.linter.log("Invalidate all caches")
+		s.resetCaches()
+		s.linter.projects = NewProjects()
+		return nil
+	}
+
+	for _, f := range files {
+		f = absPath(f)
+		p, err := s.linter.projects.At(f)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			continue
+		}
+		s.linter.log("Invalidate caches for", f)
+		if _, err := invalidateCachesForFile(p, f, s.acf, s.rwcf, s.lwcf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JSON-RPC 2.0 error codes
+// https://www.jsonrpc.org/specification#error_object
+const (
+	jsonrpcParseError     = -32700
+	jsonrpcInvalidRequest = -32600
+	jsonrpcMethodNotFound = -32601
+	jsonrpcInvalidParams  = -32602
+	jsonrpcServerError    = -32000
+)
+
+type jsonrpcRequest struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type jsonrpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonrpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
+}
+
+type serverLintParams struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+type serverFilesParams struct {
+	Files []string `json:"files"`
+}
+
+type serverErrorsResult struct {
+	Errors []*ErrorTemplateFields `json:"errors"`
+}
+
+func serverErrorsResultOf(rs []*FileResult) *serverErrorsResult {
+	es := []*ErrorTemplateFields{}
+	for _, r := range rs {
+		for _, e := range r.Errors {
+			es = append(es, e.GetTemplateFields(r.Source))
+		}
+	}
+	return &serverErrorsResult{es}
+}
+
+// serverRequestSizeLimit is the maximum size of a request body.
+const serverRequestSizeLimit = 32 * 1024 * 1024
+
+func (s *Server) call(method string, params json.RawMessage) (interface{}, *jsonrpcError) {
+	invalid := func(err error) *jsonrpcError {
+		return &jsonrpcError{jsonrpcInvalidParams, fmt.Sprintf("invalid params for %q method: %s", method, err)}
+	}
+	failed := func(err error) *jsonrpcError {
+		return &jsonrpcError{jsonrpcServerError, err.Error()}
+	}
+
+	switch method {
+	case "lint":
+		var p serverLintParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalid(err)
+		}
+		if p.Path == "" {
+			p.Path = s.linter.stdin
+		}
+		src := []byte(p.Content)
+		errs, err := s.Lint(p.Path, src)
+		if err != nil {
+			return nil, failed(err)
+		}
+		return serverErrorsResultOf([]*FileResult{{p.Path, src, errs}}), nil
+	case "lintFiles":
+		var p serverFilesParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalid(err)
+		}
+		rs, err := s.LintFiles(p.Files)
+		if err != nil {
+			return nil, failed(err)
+		}
+		return serverErrorsResultOf(rs), nil
+	case "listRules":
+		return s.ListRules(), nil
+	case "invalidate":
+		var p serverFilesParams
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &p); err != nil {
+				return nil, invalid(err)
+			}
+		}
+		if err := s.Invalidate(p.Files); err != nil {
+			return nil, failed(err)
+		}
+		return struct{}{}, nil
+	default:
+		return nil, &jsonrpcError{jsonrpcMethodNotFound, fmt.Sprintf("method %q does not exist", method)}
+	}
+}
+
+// ServeHTTP handles a JSON-RPC 2.0 request sent with POST method. Batch requests are not supported.
+// When the request is a notification, the method is called and no content is returned. Requests
+// whose content type is not "application/json" or whose host is not a loopback address are
+// rejected.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Reject requests from web pages. A browser can send a POST request with "text/plain" content
+	// type without preflight, and DNS rebinding allows the page to read the response with a host
+	// name other than loopback addresses.
+	if !isLoopbackHost(r.Host) {
+		http.Error(w, fmt.Sprintf("host %q is not allowed. only loopback addresses are allowed", r.Host), http.StatusForbidden)
+		return
+	}
+	if t, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || t != "application/json" {
+		http.Error(w, `content type must be "application/json"`, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	res := &jsonrpcResponse{Version: "2.0", ID: json.RawMessage("null")}
+	var req jsonrpcRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, serverRequestSizeLimit)).Decode(&req); err != nil {
+		res.Error = &jsonrpcError{jsonrpcParseError, fmt.Sprintf("could not parse JSON-RPC request: %s", err)}
+	} else if req.Version != "2.0" || req.Method == "" {
+		res.Error = &jsonrpcError{jsonrpcInvalidRequest, `request must have "jsonrpc": "2.0" and "method" fields`}
+	} else {
+		s.linter.log("Received", req.Method, "request")
+		res.Result, res.Error = s.call(req.Method, req.Params)
+		if len(req.ID) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		res.ID = req.ID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		s.linter.log("Could not write response:", err)
+	}
+}
+
+// localServerAddr validates the address to serve the API and returns the normalized address. Only
+// loopback addresses are allowed since the API can read any files. When the host is omitted like
+// ":8080", 127.0.0.1 is used.
+func localServerAddr(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q to serve API: %w", addr, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	if !isLoopbackHost(host) {
+		return "", fmt.Errorf("address %q to serve API must be a loopback address such as 127.0.0.1 or localhost", addr)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// isLoopbackHost returns whether the host is "localhost" or a loopback IP address. The host may
+// have a port like "127.0.0.1:8080".
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,283 @@
+package actionlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testJSONRPCResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *jsonrpcError   `json:"error"`
+}
+
+func testCallServer(t *testing.T, url string, body string) *testJSONRPCResponse {
+	t.Helper()
+	res, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d for request %s", res.StatusCode, body)
+	}
+	var r testJSONRPCResponse
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Version != "2.0" {
+		t.Fatalf("unexpected version in response: %q", r.Version)
+	}
+	return &r
+}
+
+func testCallServerMethod(t *testing.T, url, method string, params interface{}) json.RawMessage {
+	t.Helper()
+	req := map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method}
+	if params != nil {
+		req["params"] = params
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := testCallServer(t, url, string(b))
+	if r.Error != nil {
+		t.Fatalf("%s method failed: %+v", method, r.Error)
+	}
+	if string(r.ID) != "1" {
+		t.Fatalf("unexpected ID in response: %s", r.ID)
+	}
+	return r.Result
+}
+
+func testServerErrorCodes(t *testing.T, result json.RawMessage) []string {
+	t.Helper()
+	var r serverErrorsResult
+	if err := json.Unmarshal(result, &r); err != nil {
+		t.Fatal(err)
+	}
+	cs := []string{}
+	for _, e := range r.Errors {
+		cs = append(cs, e.Code)
+	}
+	return cs
+}
+
+func TestServerMethods(t *testing.T) {
+	dir := t.TempDir()
+	workflow := filepath.Join(dir, ".github", "workflows", "test.yaml")
+	action := `name: Test
+description: Test action
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`
+	testWriteFiles(t, dir, map[string]string{
+		".git/HEAD": "ref: refs/heads/main\n",
+		".github/workflows/test.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ./action
+`,
+		"action/action.yml": action,
+	})
+
+	s, err := NewServer(&LinterOptions{WorkingDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	r := testCallServerMethod(t, ts.URL, "lint", map[string]string{
+		"path":    "test.yaml",
+		"content": "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ${{ unknown }}\n",
+	})
+	if have, want := testServerErrorCodes(t, r), []string{"expression/undefined-variable"}; !cmp.Equal(want, have) {
+		t.Fatal("unexpected result of lint:", cmp.Diff(want, have))
+	}
+
+	files := map[string][]string{"files": {workflow}}
+	r = testCallServerMethod(t, ts.URL, "lintFiles", files)
+	if have := testServerErrorCodes(t, r); len(have) != 0 {
+		t.Fatal("unexpected errors at first lintFiles:", have)
+	}
+
+	// Make the action require an input. The cached metadata is used until it is invalidated
+	testWriteFiles(t, dir, map[string]string{
+		"action/action.yml": strings.Replace(action, "runs:", "inputs:\n  name:\n    description: Name\n    required: true\nruns:", 1),
+	})
+	r = testCallServerMethod(t, ts.URL, "lintFiles", files)
+	if have := testServerErrorCodes(t, r); len(have) != 0 {
+		t.Fatal("cached action metadata should be used before invalidation:", have)
+	}
+
+	testCallServerMethod(t, ts.URL, "invalidate", map[string][]string{"files": {filepath.Join(dir, "action", "action.yml")}})
+	r = testCallServerMethod(t, ts.URL, "lintFiles", files)
+	if have, want := testServerErrorCodes(t, r), []string{"action/missing-required-input"}; !cmp.Equal(want, have) {
+		t.Fatal("unexpected result of lintFiles after invalidation:", cmp.Diff(want, have))
+	}
+
+	// Invalidate all caches
+	testCallServerMethod(t, ts.URL, "invalidate", nil)
+
+	r = testCallServerMethod(t, ts.URL, "listRules", nil)
+	var rules []*RuleMetadata
+	if err := json.Unmarshal(r, &rules); err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != len(BuiltinRuleMetadata()) || rules[0].Name == "" || rules[0].Description == "" {
+		t.Fatalf("unexpected result of listRules: %v", rules)
+	}
+}
+
+func TestServerInvalidRequests(t *testing.T) {
+	s, err := NewServer(&LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	testCases := []struct {
+		what string
+		body string
+		code int
+	}{
+		{"broken JSON", `{"jsonrpc":`, jsonrpcParseError},
+		{"no version", `{"id":1,"method":"listRules"}`, jsonrpcInvalidRequest},
+		{"no method", `{"jsonrpc":"2.0","id":1}`, jsonrpcInvalidRequest},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"foo"}`, jsonrpcMethodNotFound},
+		{"invalid params", `{"jsonrpc":"2.0","id":1,"method":"lintFiles","params":{"files":"foo"}}`, jsonrpcInvalidParams},
+		{"missing params", `{"jsonrpc":"2.0","id":1,"method":"lint"}`, jsonrpcInvalidParams},
+		{"file not found", `{"jsonrpc":"2.0","id":1,"method":"lintFiles","params":{"files":["this-file-does-not-exist.yaml"]}}`, jsonrpcServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			r := testCallServer(t, ts.URL, tc.body)
+			if r.Error == nil {
+				t.Fatalf("error was not returned: %s", r.Result)
+			}
+			if r.Error.Code != tc.code {
+				t.Fatalf("wanted error code %d but got %d: %s", tc.code, r.Error.Code, r.Error.Message)
+			}
+		})
+	}
+
+	res, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("GET request should not be allowed but got status %d", res.StatusCode)
+	}
+
+	// Notification has no response
+	res, err = http.Post(ts.URL, "application/json", bytes.NewReader([]byte(`{"jsonrpc":"2.0","method":"invalidate"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		t.Fatalf("wanted status %d for notification but got %d", http.StatusNoContent, res.StatusCode)
+	}
+}
+
+func TestServerRejectRequestsFromWebPages(t *testing.T) {
+	s, err := NewServer(&LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"listRules"}`
+	testCases := []struct {
+		what   string
+		host   string
+		ctype  string
+		status int
+	}{
+		{"OK", "", "application/json", http.StatusOK},
+		{"content type with charset", "", "application/json; charset=utf-8", http.StatusOK},
+		{"localhost", "localhost:8123", "application/json", http.StatusOK},
+		{"IPv6 loopback", "[::1]:8123", "application/json", http.StatusOK},
+		{"text/plain", "", "text/plain", http.StatusUnsupportedMediaType},
+		{"form", "", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"no content type", "", "", http.StatusUnsupportedMediaType},
+		{"DNS rebinding", "attacker.example.com:8123", "application/json", http.StatusForbidden},
+		{"non-loopback address", "192.168.1.1", "application/json", http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.host != "" {
+				req.Host = tc.host
+			}
+			if tc.ctype != "" {
+				req.Header.Set("Content-Type", tc.ctype)
+			}
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+			if res.StatusCode != tc.status {
+				t.Fatalf("wanted status %d but got %d", tc.status, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServerLocalAddr(t *testing.T) {
+	testCases := []struct {
+		addr string
+		want string
+		err  bool
+	}{
+		{":8123", "127.0.0.1:8123", false},
+		{"127.0.0.1:8123", "127.0.0.1:8123", false},
+		{"localhost:0", "localhost:0", false},
+		{"[::1]:8123", "[::1]:8123", false},
+		{"0.0.0.0:8123", "", true},
+		{"example.com:8123", "", true},
+		{"8123", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.addr, func(t *testing.T) {
+			have, err := localServerAddr(tc.addr)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("error did not occur: %q", have)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have != tc.want {
+				t.Fatalf("wanted %q but got %q", tc.want, have)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package actionlint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// shellcheckResult is a result of running shellcheck for one script. The done channel is closed
+// when the result is available.
+type shellcheckResult struct {
+	done chan struct{}
+	errs []shellcheckError
+	err  error
+}
+
+func (r *shellcheckResult) resolve(errs []shellcheckError, err error) {
+	r.errs = errs
+	r.err = err
+	close(r.done)
+}
+
+// shellcheckCache is a cache of shellcheck results keyed by the script, the shell, and the
+// arguments of shellcheck. The same script often appears in many jobs and in many workflows, but
+// shellcheck needs to run only once for it. Positions in the results are relative to the script so
+// that they can be reported at each occurrence of the script.
+// When dir is not empty, the results are also stored in the directory to reuse them across runs.
+// This type is thread safe since it is shared by all rules while linting files in parallel.
+type shellcheckCache struct {
+	dir     string
+	dbg     io.Writer
+	mu      sync.Mutex
+	results map[string]*shellcheckResult
+	once    sync.Once
+	version string
+}
+
+func newShellcheckCache(dir string, dbg io.Writer) *shellcheckCache {
+	return &shellcheckCache{
+		dir:     dir,
+		dbg:     dbg,
+		results: map[string]*shellcheckResult{},
+	}
+}
+
+func (c *shellcheckCache) debug(format string, args ...interface{}) {
+	if c.dbg == nil {
+		return
+	}
+	format = "[ShellcheckCache] " + format + "\n"
+	fmt.Fprintf(c.dbg, format, args...)
+}
+
+// key returns the cache key for running the executable with the arguments and the stdin.
+func (c *shellcheckCache) key(exe string, args []string, stdin string) string {
+	h := sha256.New()
+	if c.dir != "" {
+		// The version is necessary only for the results stored across runs
+		c.once.Do(func() { c.version = commandVersion(exe) })
+		fmt.Fprintf(h, "version:%s\x00", c.version)
+	}
+	fmt.Fprintf(h, "exe:%s\x00args:%s\x00", exe, strings.Join(args, "\x00"))
+	h.Write([]byte(stdin))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *shellcheckCache) file(key string) string {
+	return filepath.Join(c.dir, "shellcheck", key+".json")
+}
+
+// acquire returns the result for the key. When the second return value is true, the caller is
+// the first one requesting the key and must resolve the result. Otherwise the caller should wait
+// until the done channel of the result is closed.
+func (c *shellcheckCache) acquire(key string) (*shellcheckResult, bool) {
+	c.mu.Lock()
+	r, ok := c.results[key]
+	if !ok {
+		r = &shellcheckResult{done: make(chan struct{})}
+		c.results[key] = r
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.debug("Reuse result of shellcheck for key %s", key)
+		return r, false
+	}
+
+	if c.dir == "" {
+		return r, true
+	}
+	b, err := os.ReadFile(c.file(key))
+	if err != nil {
+		return r, true
+	}
+	var errs []shellcheckError
+	if err := json.Unmarshal(b, &errs); err != nil {
+		c.debug("Broken cache file for key %s: %s", key, err)
+		return r, true
+	}
+	c.debug("Found result of shellcheck in cache for key %s", key)
+	r.resolve(errs, nil)
+	return r, false
+}
+
+// store stores the result of shellcheck in the cache directory. Failing to write the cache is not
+// fatal.
+func (c *shellcheckCache) store(key string, errs []shellcheckError) {
+	if c.dir == "" {
+		return
+	}
+	b, err := json.Marshal(errs)
+	if err == nil {
+		err = writeFileAtomic(c.file(key), b)
+	}
+	if err != nil {
+		c.debug("Could not write cache for key %s: %s", key, err)
+	}
+}
@@ -0,0 +1,257 @@
+package actionlint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// fileStamp is a state of a file to detect changes of the file by polling.
+type fileStamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func statFile(path string) fileStamp {
+	s, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{true, s.Size(), s.ModTime()}
+}
+
+// watcher checks workflow files in a project again when they or their dependencies are changed.
+// Changes are detected by polling the states of the files. The caches of local actions, local
+// reusable workflows, and local workflows are kept across checks and only the entries affected by
+// the changes are invalidated.
+type watcher struct {
+	linter *Linter
+	proj   *Project
+	acf    *LocalActionsCacheFactory
+	rwcf   *LocalReusableWorkflowCacheFactory
+	lwcf   *LocalWorkflowsCacheFactory
+	// deps maps the absolute file path of each workflow to the files which the workflow depends on.
+	deps   map[string][]string
+	stamps map[string]fileStamp
+}
+
+func newWatcher(l *Linter, proj *Project) *watcher {
+	dbg := l.debugWriter()
+	return &watcher{
+		linter: l,
+		proj:   proj,
+		acf:    NewLocalActionsCacheFactory(dbg),
+		rwcf:   NewLocalReusableWorkflowCacheFactory(l.cwd, dbg),
+		lwcf:   NewLocalWorkflowsCacheFactory(dbg),
+		deps:   map[string][]string{},
+		stamps: map[string]fileStamp{},
+	}
+}
+
+func projectConfigFiles(proj *Project) []string {
+	return []string{
+		filepath.Join(proj.RootDir(), ".github", "actionlint.yaml"),
+		filepath.Join(proj.RootDir(), ".github", "actionlint.yml"),
+	}
+}
+
+// invalidateCachesForFile invalidates the cache entries which depend on the changed file at the
+// absolute path in the project. When the file is the config file, the config is reloaded and this
+// function returns true.
+func invalidateCachesForFile(
+	proj *Project,
+	path string,
+	acf *LocalActionsCacheFactory,
+	rwcf *LocalReusableWorkflowCacheFactory,
+	lwcf *LocalWorkflowsCacheFactory,
+) (bool, error) {
+	if slices.Contains(projectConfigFiles(proj), path) {
+		return true, proj.reloadConfig()
+	}
+
+	rel, err := filepath.Rel(proj.RootDir(), path)
+	if err != nil {
+		return false, nil
+	}
+	rel = filepath.ToSlash(rel)
+
+	if filepath.Dir(path) == proj.WorkflowsDir() {
+		rwcf.GetCache(proj).invalidate(rel)
+		lwcf.GetCache(proj).invalidate()
+	}
+	if b := filepath.Base(path); b == "action.yml" || b == "action.yaml" {
+		acf.GetCache(proj).invalidate(filepath.ToSlash(filepath.Dir(rel)))
+	}
+	return false, nil
+}
+
+// watchedFiles returns all files to be watched. Files which do not exist are also included to
+// detect their creation.
+func (w *watcher) watchedFiles(workflows []string) []string {
+	ret := slices.Concat(workflows, projectConfigFiles(w.proj))
+	for _, ds := range w.deps {
+		ret = append(ret, ds...)
+	}
+	slices.Sort(ret)
+	return slices.Compact(ret)
+}
+
+func (w *watcher) snapshot(workflows []string) map[string]fileStamp {
+	ret := map[string]fileStamp{}
+	for _, f := range w.watchedFiles(workflows) {
+		ret[f] = statFile(f)
+	}
+	return ret
+}
+
+// lint checks the workflow files and updates their dependencies.
+func (w *watcher) lint(files []string) ([]*Error, error) {
+	errs, err := w.linter.lintFiles(files, w.proj, w.acf, w.rwcf, w.lwcf, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		src, err := os.ReadFile(f)
+		if err != nil {
+			delete(w.deps, f)
+			continue
+		}
+		wf, _ := Parse(src)
+		w.deps[f] = localDependencies(wf, w.proj)
+	}
+	return errs, nil
+}
+
+// start checks all workflow files in the project.
+func (w *watcher) start() ([]*Error, error) {
+	files, err := collectYAMLFiles(w.proj.WorkflowsDir())
+	if err != nil {
+		return nil, err
+	}
+	errs, err := w.lint(files)
+	if err != nil {
+		return nil, err
+	}
+	w.stamps = w.snapshot(files)
+	return errs, nil
+}
+
+// poll detects changed files since the previous check and checks the affected workflow files
+// again. It returns the checked workflow files and the errors found in them. When nothing was
+// changed, the returned files are empty.
+func (w *watcher) poll() ([]string, []*Error, error) {
+	files, err := collectYAMLFiles(w.proj.WorkflowsDir())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cur := w.snapshot(files)
+	changed := []string{}
+	for f, s := range cur {
+		if w.stamps[f] != s {
+			changed = append(changed, f)
+		}
+	}
+	for f := range w.stamps {
+		if _, ok := cur[f]; !ok {
+			changed = append(changed, f)
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil, nil
+	}
+	slices.Sort(changed)
+	w.linter.log("Detected changed files:", changed)
+	w.stamps = cur // Even if the following check fails, do not retry it until the next change
+
+	targets := map[string]struct{}{}
+	for _, f := range changed {
+		config, err := invalidateCachesForFile(w.proj, f, w.acf, w.rwcf, w.lwcf)
+		if err != nil {
+			return nil, nil, err
+		}
+		if config {
+			for _, wf := range files {
+				targets[wf] = struct{}{}
+			}
+			continue
+		}
+
+		if slices.Contains(files, f) {
+			targets[f] = struct{}{}
+		} else {
+			delete(w.deps, f) // The workflow was removed
+		}
+		for wf, ds := range w.deps {
+			if slices.Contains(ds, f) {
+				targets[wf] = struct{}{}
+			}
+		}
+	}
+
+	linted := make([]string, 0, len(targets))
+	for f := range targets {
+		if slices.Contains(files, f) {
+			linted = append(linted, f)
+		}
+	}
+	slices.Sort(linted)
+
+	var errs []*Error
+	if len(linted) > 0 {
+		errs, err = w.lint(linted)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	w.stamps = w.snapshot(files)
+	return linted, errs, nil
+}
+
+// Watch checks all workflow files in the repository and keeps checking them again when they are
+// changed until the stop channel is closed. Workflow files which use changed local actions or local
+// reusable workflows are also checked again. When the config file is changed, all workflow files
+// are checked again. Changes are detected by polling the files with the interval. The project is
+// detected from the dir parameter as well as LintRepository. Errors occurred after the first check
+// are reported to the log writer and do not stop watching.
+func (l *Linter) Watch(dir string, interval time.Duration, stop <-chan struct{}) error {
+	if dir == "" {
+		dir = l.cwd
+	}
+
+	p, err := l.projects.At(dir)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return fmt.Errorf("no project was found in any parent directories of %q. check workflows directory is put correctly in your Git repository", dir)
+	}
+
+	w := newWatcher(l, p)
+	errs, err := w.start()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(l.logOut, "Found %d error(s). Watching changes in %s\n", len(errs), p.RootDir())
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-t.C:
+			files, errs, err := w.poll()
+			if err != nil {
+				fmt.Fprintln(l.logOut, err)
+				continue
+			}
+			if len(files) > 0 {
+				fmt.Fprintf(l.logOut, "Found %d error(s) in %d changed file(s)\n", len(errs), len(files))
+			}
+		}
+	}
+}
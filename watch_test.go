@@ -0,0 +1,191 @@
+package actionlint
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func testWatcherErrorCodes(errs []*Error) []string {
+	ret := []string{}
+	for _, err := range errs {
+		ret = append(ret, err.Filepath+":"+err.Code)
+	}
+	return ret
+}
+
+func TestWatcherChecksOnlyAffectedWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	testWriteFiles(t, dir, map[string]string{
+		".git/HEAD": "ref: refs/heads/main\n",
+		".github/workflows/action.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ./action
+`,
+		".github/workflows/caller.yaml": `on: push
+jobs:
+  call:
+    uses: ./.github/workflows/reusable.yaml
+`,
+		".github/workflows/reusable.yaml": `on:
+  workflow_call:
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`,
+		"action/action.yml": `name: Test
+description: Test action
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`,
+	})
+
+	proj, err := NewProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	l, err := NewLinter(&out, &LinterOptions{WorkingDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := newWatcher(l, proj)
+
+	errs, err := w.start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors at first check: %v", errs)
+	}
+
+	poll := func(want []string) []*Error {
+		t.Helper()
+		files, errs, err := w.poll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, f := range files {
+			files[i], _ = filepath.Rel(dir, f)
+			files[i] = filepath.ToSlash(files[i])
+		}
+		if diff := cmp.Diff(want, files, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatal("unexpected files were checked:", diff)
+		}
+		return errs
+	}
+
+	poll(nil) // Nothing was changed
+
+	// Only the workflow using the changed local action is checked with the new action metadata
+	testWriteFiles(t, dir, map[string]string{
+		"action/action.yml": `name: Test
+description: Test action
+inputs:
+  name:
+    description: Name
+    required: true
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`,
+	})
+	errs = poll([]string{".github/workflows/action.yaml"})
+	if have, want := testWatcherErrorCodes(errs), []string{filepath.Join(".github", "workflows", "action.yaml") + ":action/missing-required-input"}; !cmp.Equal(want, have) {
+		t.Fatalf("unexpected errors after changing the local action: %s", cmp.Diff(want, have))
+	}
+
+	// The reusable workflow and its caller are checked with the new workflow metadata
+	testWriteFiles(t, dir, map[string]string{
+		".github/workflows/reusable.yaml": `on:
+  workflow_call:
+    inputs:
+      name:
+        type: string
+        required: true
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`,
+	})
+	errs = poll([]string{".github/workflows/caller.yaml", ".github/workflows/reusable.yaml"})
+	if have, want := testWatcherErrorCodes(errs), []string{filepath.Join(".github", "workflows", "caller.yaml") + ":workflow-call/missing-required-input"}; !cmp.Equal(want, have) {
+		t.Fatalf("unexpected errors after changing the reusable workflow: %s", cmp.Diff(want, have))
+	}
+
+	// All workflows are checked when the config file is created
+	testWriteFiles(t, dir, map[string]string{
+		".github/actionlint.yaml": `paths:
+  .github/workflows/**/*.yaml:
+    ignore: ['code:.*/missing-required-input']
+`,
+	})
+	errs = poll([]string{".github/workflows/action.yaml", ".github/workflows/caller.yaml", ".github/workflows/reusable.yaml"})
+	if len(errs) != 0 {
+		t.Fatalf("errors should be ignored by the new config: %v", errs)
+	}
+
+	// Removed workflow is no longer checked
+	if err := os.Remove(filepath.Join(dir, ".github", "workflows", "action.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	poll(nil)
+}
+
+func TestLinterWatchStop(t *testing.T) {
+	dir := t.TempDir()
+	testWriteFiles(t, dir, map[string]string{
+		".git/HEAD": "ref: refs/heads/main\n",
+		".github/workflows/test.yaml": `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown_context }}
+`,
+	})
+
+	var log bytes.Buffer
+	l, err := NewLinter(io.Discard, &LinterOptions{LogWriter: &log})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+	if err := l.Watch(dir, time.Millisecond, stop); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := "Found 1 error(s). Watching changes in", log.String(); !strings.Contains(have, want) {
+		t.Fatalf("wanted %q in log but got %q", want, have)
+	}
+}
+
+func TestLinterWatchNoProject(t *testing.T) {
+	l, err := NewLinter(io.Discard, &LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Watch(t.TempDir(), time.Millisecond, nil); err == nil {
+		t.Fatal("error should occur when no project is found")
+	}
+}